@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// projectAction 为选择项目后可执行的操作，在项目目录中执行
+type projectAction struct {
+	Name string
+	Run  func(ctx context.Context, config *Config, folder, path string) error
+	// Stay 为执行后是否回到操作菜单
+	Stay bool
+}
+
+// 选择项目后的操作菜单，第一项为默认操作，直接回车即执行
+var projectActions = []projectAction{
+	{Name: "打开编辑器并启动服务", Run: openAndLaunch},
+	{Name: "只打开编辑器", Run: func(ctx context.Context, config *Config, folder, _ string) error {
+		return openEditor(ctx, config, findProject(config, folder))
+	}},
+	{Name: "只启动服务", Run: launchOnly},
+	{Name: "运行脚本", Run: runScript, Stay: true},
+	{Name: "运行 VS Code 任务", Run: runVSCodeTask, Stay: true},
+	{Name: "git pull", Run: gitPull, Stay: true},
+	{Name: "切换分支后启动", Run: chooseBranch},
+	{Name: "新建 worktree", Run: createWorktree},
+	{Name: "GitHub/GitLab（仓库页面、PR）", Run: forgeActions, Stay: true},
+	{Name: "在此打开终端", Run: openShell, Stay: true},
+	{Name: "在文件管理器中打开", Run: func(_ context.Context, _ *Config, _, path string) error { return openFileManager(path) }, Stay: true},
+	{Name: "打开链接", Run: openLink, Stay: true},
+	{Name: "复制路径", Run: func(_ context.Context, _ *Config, _, path string) error { return copyToClipboard(path) }, Stay: true},
+	{Name: "项目信息", Run: showProjectInfo, Stay: true},
+	{Name: "查看 README", Run: showReadme, Stay: true},
+	{Name: "项目笔记", Run: projectNotes, Stay: true},
+	{Name: "清除记住的选择", Run: forgetPreferences, Stay: true},
+	{Name: "删除项目", Run: deleteProject, Stay: true},
+}
+
+// 列出操作菜单供用户选择，插件添加的操作排在内置操作之后。直接回车执行默认操作，输入 q 或输入结束时返回 io.EOF。开启 mouse 时可以点击操作
+func chooseProjectAction(ctx context.Context, config *Config, folder string) (projectAction, error) {
+	projectActions := append(projectActions[:len(projectActions):len(projectActions)], pluginMenuActions(ctx, config, folder)...)
+	fmt.Printf("%s：\n", folder)
+	for i, action := range projectActions {
+		fmt.Printf("%d. %s\n", i+1, action.Name)
+	}
+	listed := true
+	for {
+		prompt := "请输入操作编号（直接回车为 1，q 退出）: "
+		var input string
+		var err error
+		if config.Mouse {
+			var click clickList
+			if listed {
+				click = clickList{First: 1, Count: len(projectActions)}
+			}
+			input, err = readInput(ctx, prompt, func(rune) bool { return false }, nil, click)
+		} else {
+			input, err = readLine(ctx, prompt)
+		}
+		listed = false
+		if err == io.EOF || input == quitInput {
+			return projectAction{}, io.EOF
+		}
+		if input == "" {
+			return projectActions[0], nil
+		}
+		choice, err := parseChoice(input, 1, len(projectActions))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return projectActions[choice-1], nil
+	}
+}
+
+// 打开编辑器，再进入启动目录检测项目类型并启动服务
+func openAndLaunch(ctx context.Context, config *Config, folder, path string) error {
+	beginLaunch(folder)
+	stop := timePhase("editor")
+	ok := openEditorOrContinue(ctx, config, folder, findProject(config, folder))
+	stop()
+	if !ok {
+		setExitCode(exitCanceled)
+		return nil
+	}
+	return launchOnly(ctx, config, folder, path)
+}
+
+// 不打开编辑器，进入启动目录检测项目类型并启动服务
+func launchOnly(ctx context.Context, config *Config, folder, path string) error {
+	// 根据项目配置确定服务启动的目录
+	workDir := "."
+	if project := findProject(config, folder); project != nil && project.WorkDir != "" {
+		workDir = project.WorkDir
+	}
+
+	// 切换到服务启动目录
+	if err := os.Chdir(workDir); err != nil {
+		return fmt.Errorf("无法进入启动目录 %s: %v", workDir, err)
+	}
+
+	beginLaunch(folder)
+	if !prepareLaunch(ctx, config, folder) {
+		return nil
+	}
+
+	// 检测项目类型并启动服务
+	recordLaunch(folder)
+	launchServer(ctx, folder, path, config)
+	return nil
+}
+
+// 启动服务前的检查：脚本的 launch 钩子、启动参数、所需工具、依赖服务、首次启动的初始化命令和插件步骤，返回是否继续启动
+func prepareLaunch(ctx context.Context, config *Config, folder string) bool {
+	// 脚本的 launch 钩子可以按分支、时间等条件跳过启动
+	if !scriptAllowsLaunch(config, folder) {
+		return false
+	}
+	if !askInputs(ctx, config, folder) {
+		setExitCode(exitCanceled)
+		return false
+	}
+	// 启动参数需要等待输入，不计入启动准备的耗时
+	defer timePhase("prepare")()
+	if !ensureTools(ctx, config, folder) || !ensureDependencies(ctx, config, folder) || !runFirstRun(ctx, config, folder) || !runPluginSteps(ctx, config, folder) {
+		setExitCode(exitCanceled)
+		return false
+	}
+	return true
+}
+
+// 列出项目中可运行的脚本：package.json 中的 scripts 和 composer.json 中的 scripts
+func projectScripts() []launchAction {
+	var actions []launchAction
+	var pkg packageJSON
+	if err := readJSONFile("package.json", &pkg); err == nil {
+		manager := nodePackageManager()
+		for _, name := range sortedKeys(pkg.Scripts) {
+			actions = append(actions, launchAction{Name: manager + " run " + name, Command: []string{manager, "run", name}})
+		}
+	}
+	var composer composerJSON
+	if err := readJSONFile("composer.json", &composer); err == nil {
+		for _, name := range sortedKeys(composer.Scripts) {
+			actions = append(actions, launchAction{Name: "composer run " + name, Command: []string{"composer", "run", name}})
+		}
+	}
+	return actions
+}
+
+// 获取 map 按字典序排列的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 选择并运行项目中的一个脚本，上次运行的脚本直接回车即可再次运行，脚本结束后返回
+func runScript(ctx context.Context, config *Config, folder, _ string) error {
+	scripts := projectScripts()
+	if len(scripts) == 0 {
+		fmt.Println("未找到可运行的脚本")
+		return nil
+	}
+	names := make([]string, len(scripts))
+	for i, script := range scripts {
+		names[i] = script.Name
+	}
+	fmt.Println("脚本：")
+	choice, err := chooseRemembered(ctx, "请输入脚本编号", names, projectPreferences(folder).Script)
+	if err != nil {
+		return nil
+	}
+	updatePreferences(folder, func(p *projectPreference) { p.Script = names[choice-1] })
+	return runForeground(ctx, config, folder, 0, scripts[choice-1].Command)
+}
+
+// 在项目目录中执行 git pull
+func gitPull(ctx context.Context, config *Config, folder, _ string) error {
+	if !fileExists(".git") {
+		fmt.Println("项目不是 git 仓库")
+		return nil
+	}
+	if skipOffline(" git pull") {
+		return nil
+	}
+	// 拉取代码后通常接着启动，计入下一次启动的耗时
+	beginLaunch(folder)
+	defer timePhase("git")()
+	return runForeground(ctx, config, folder, stepTimeout(config, "git"), []string{"git", "pull"})
+}
+
+// 在当前目录前台运行命令并等待结束，使用项目的环境变量，env 为追加的环境变量。timeout 为 0 表示不限时，按下 Ctrl+C 时回到菜单
+func runForeground(ctx context.Context, config *Config, folder string, timeout time.Duration, command []string, env ...string) error {
+	command = wrapCommand(command)
+	if err := runStep(ctx, timeout, append(projectEnv(ctx, config, folder), env...), command...); err != nil {
+		return fmt.Errorf("%s 执行失败: %v", strings.Join(command, " "), err)
+	}
+	return nil
+}
+
+// 在系统文件管理器中打开目录
+func openFileManager(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		// explorer 会把路径中的逗号当作参数分隔符，改为打开工作目录以避免传入路径
+		cmd = exec.Command("explorer", ".")
+		cmd.Dir = path
+	case "darwin":
+		cmd = exec.Command("open", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	// explorer 成功打开时也可能返回非零退出码，只检查能否启动
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("无法打开文件管理器: %v", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// 在系统默认浏览器中打开链接
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		// 不经过 cmd /c start，避免链接中的 & 被 cmd 解释
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("无法打开链接 %s: %v", url, err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// 列出项目配置的链接，选择后在浏览器中打开，只有一个链接时直接打开
+func openLink(ctx context.Context, config *Config, folder, _ string) error {
+	links := projectLinks(config, folder)
+	switch len(links) {
+	case 0:
+		fmt.Println("未配置链接，可在配置文件的 links 中添加")
+		return nil
+	case 1:
+		return openURL(links[0].URL)
+	}
+	fmt.Println("链接：")
+	for i, link := range links {
+		fmt.Printf("%d. %s  %s\n", i+1, link.Name, link.URL)
+	}
+	for {
+		choice, err := getUserChoice(ctx, "请输入链接编号: ", len(links))
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return openURL(links[choice-1].URL)
+	}
+}
+
+// 在当前终端中打开项目目录下的交互式 shell，退出 shell 后返回
+func openShell(ctx context.Context, config *Config, folder, path string) error {
+	shell := configuredShell(config)
+	fmt.Printf("已在 %s 中打开 %s，输入 exit 返回\n", path, shell)
+	cmd := exec.Command(shell)
+	cmd.Dir = path
+	cmd.Env = projectEnv(ctx, config, folder)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = processStdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// 将文本复制到系统剪贴板
+func copyToClipboard(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		// clip 按本地代码页解析输入，含中文的路径会乱码，改用 PowerShell 并经环境变量传入
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", "Set-Clipboard -Value $env:QUICKSTART_CLIPBOARD")
+		cmd.Env = append(os.Environ(), "QUICKSTART_CLIPBOARD="+text)
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	default:
+		for _, candidate := range [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		} {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				cmd = exec.Command(candidate[0], candidate[1:]...)
+				break
+			}
+		}
+		if cmd == nil {
+			return fmt.Errorf("未找到剪贴板工具，请安装 wl-clipboard、xclip 或 xsel")
+		}
+	}
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("无法复制到剪贴板: %v", err)
+	}
+	fmt.Printf("已复制路径：%s\n", text)
+	return nil
+}
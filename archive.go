@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 执行 archive / unarchive 子命令
+func runArchiveCommand(command string, args []string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	if command == "archive" {
+		if len(args) == 0 {
+			return fmt.Errorf("用法: quickstart archive <项目>")
+		}
+		return archiveProject(config, resolveAlias(config, args[0]))
+	}
+	if len(args) == 0 {
+		if len(config.Archived) == 0 {
+			fmt.Println("没有已归档的项目。")
+		}
+		for _, name := range config.Archived {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	return unarchiveProject(config, resolveAlias(config, args[0]))
+}
+
+// 在菜单中选择要归档的项目
+func archiveFromMenu(ctx context.Context, config *Config, projects []*Project) error {
+	choice, err := getUserChoice(ctx, "请输入要归档的项目编号: ", len(projects))
+	if err != nil {
+		return err
+	}
+	return archiveProject(config, projects[choice-1].Name)
+}
+
+// 获取归档目录的绝对路径，相对路径相对于工作目录，未配置时返回空字符串
+func archiveDir(config *Config) string {
+	if config.ArchiveDir == "" || filepath.IsAbs(config.ArchiveDir) {
+		return config.ArchiveDir
+	}
+	return filepath.Join(config.ProjectDir, config.ArchiveDir)
+}
+
+// 归档项目：配置了归档目录时将项目文件夹移动到归档目录，并记录到配置文件中以便在菜单中隐藏
+func archiveProject(config *Config, name string) error {
+	if contains(name, config.Archived) {
+		return fmt.Errorf("项目 %s 已归档", name)
+	}
+	// 远程项目和 WSL 项目不在工作目录中，只隐藏不移动
+	project := findProject(config, name)
+	local := project == nil || project.Remote == "" && project.WSL == ""
+	if dir := archiveDir(config); dir != "" && local {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("无法创建归档目录: %v", err)
+		}
+		if err := os.Rename(filepath.Join(config.ProjectDir, name), filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("无法移动项目 %s: %v", name, err)
+		}
+	}
+	if err := updateArchived(name, true); err != nil {
+		return err
+	}
+	config.Archived = append(config.Archived, name)
+	fmt.Printf("已归档项目：%s\n", name)
+	return nil
+}
+
+// 取消归档：将项目从归档目录移回工作目录，并从配置文件中移除归档记录
+func unarchiveProject(config *Config, name string) error {
+	if !contains(name, config.Archived) {
+		return fmt.Errorf("项目 %s 未归档", name)
+	}
+	if dir := archiveDir(config); dir != "" && fileExists(filepath.Join(dir, name)) {
+		if err := os.Rename(filepath.Join(dir, name), filepath.Join(config.ProjectDir, name)); err != nil {
+			return fmt.Errorf("无法移动项目 %s: %v", name, err)
+		}
+	}
+	if err := updateArchived(name, false); err != nil {
+		return err
+	}
+	fmt.Printf("已取消归档：%s\n", name)
+	return nil
+}
+
+// 在配置文件中添加或移除归档记录，项目的备注等配置保持不变，取消归档后继续生效
+func updateArchived(name string, archived bool) error {
+	raw, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	list := raw.Archived[:0]
+	for _, n := range raw.Archived {
+		if n != name {
+			list = append(list, n)
+		}
+	}
+	if archived {
+		list = append(list, name)
+	}
+	raw.Archived = list
+	return writeConfig(raw)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go-quickStart/ipc"
+)
+
+// 执行 attach 子命令：连接守护进程，在当前终端中显示项目服务的最近输出和实时输出。
+// 按 Ctrl+C、Ctrl+D 或 q 断开，服务继续在守护进程中运行
+func runAttachCommand(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("用法: quickstart attach <项目>")
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	name := resolveAlias(config, args[0])
+	client, err := ipc.Dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	detached := make(chan struct{})
+	restore, keys := enableRawInput()
+	if keys {
+		defer restore()
+		fmt.Printf("已连接 %s，按 Ctrl+C 或 %s 断开，服务继续运行\n", name, quitInput)
+		go func() {
+			for {
+				r, err := readRune(ctx, nil)
+				if err != nil || r == 3 || r == 4 || string(r) == quitInput {
+					close(detached)
+					return
+				}
+			}
+		}()
+	} else {
+		interrupt, release := claimInterrupt()
+		defer release()
+		go func() {
+			<-interrupt
+			close(detached)
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.StreamLogs(name, func(line ipc.LogLine) {
+			fmt.Println(line.Text)
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s 的服务已退出\n", name)
+	case <-detached:
+		fmt.Printf("已断开 %s，服务继续在守护进程中运行\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 分支列表最多显示的数量，更早的分支可以直接输入名称
+const maxBranches = 30
+
+// gitBranch 为可切换的本地或远程分支
+type gitBranch struct {
+	Name    string // 本地分支名，或 origin/feature 形式的远程分支名
+	Remote  bool   // 只存在于远程，切换时创建同名的跟踪分支
+	Updated string // 最后一次提交的相对时间
+}
+
+// 本地分支名，远程分支去掉远程名
+func (b gitBranch) local() string {
+	if b.Remote {
+		if _, name, ok := strings.Cut(b.Name, "/"); ok {
+			return name
+		}
+	}
+	return b.Name
+}
+
+// 列出当前目录仓库的分支，按最后提交时间从新到旧排列。已有同名本地分支的远程分支不重复列出
+func listBranches(ctx context.Context) ([]gitBranch, error) {
+	out, err := queryOutput(ctx, "git", "for-each-ref", "--sort=-committerdate",
+		"--format=%(refname)%09%(refname:short)%09%(committerdate:relative)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("无法读取分支: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	local := make(map[string]bool)
+	for _, line := range lines {
+		if fields := strings.SplitN(line, "\t", 3); len(fields) == 3 && strings.HasPrefix(fields[0], "refs/heads/") {
+			local[fields[1]] = true
+		}
+	}
+	var branches []gitBranch
+	for _, line := range lines {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 || strings.HasSuffix(fields[0], "/HEAD") {
+			continue
+		}
+		b := gitBranch{Name: fields[1], Remote: strings.HasPrefix(fields[0], "refs/remotes/"), Updated: fields[2]}
+		if b.Remote && local[b.local()] {
+			continue
+		}
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+// 选择分支并切换后打开编辑器、启动服务。列出最近提交的本地和远程分支，输入 f 先从远程获取；
+// 有未提交的修改时询问是否储藏后再切换
+func chooseBranch(ctx context.Context, config *Config, folder, path string) error {
+	if _, err := queryOutput(ctx, "git", "rev-parse", "--git-dir"); err != nil {
+		fmt.Println("项目不是 git 仓库")
+		return nil
+	}
+	beginLaunch(folder)
+	var branch gitBranch
+	for {
+		branches, err := listBranches(ctx)
+		if err != nil {
+			return err
+		}
+		current := gitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+		fmt.Println("分支（按最近提交排列）：")
+		for i, b := range branches {
+			if i == maxBranches {
+				fmt.Printf("…… 还有 %d 个分支，可直接输入分支名\n", len(branches)-maxBranches)
+				break
+			}
+			mark := "  "
+			if b.Name == current {
+				mark = "* "
+			}
+			fmt.Printf("%d. %s%s  · %s\n", i+1, mark, b.Name, b.Updated)
+		}
+		input, err := readLine(ctx, "请输入分支编号或名称（f 获取远程分支，直接回车取消）: ")
+		if err != nil || input == "" {
+			return nil
+		}
+		if input == "f" {
+			if skipOffline("获取远程分支") {
+				continue
+			}
+			stop := timePhase("git")
+			if err := runForeground(ctx, config, folder, stepTimeout(config, "git"), []string{"git", "fetch", "--all", "--prune"}); err != nil {
+				fmt.Println(err)
+			}
+			stop()
+			continue
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(branches) {
+			branch = branches[n-1]
+			break
+		}
+		var found bool
+		if branch, found = findBranch(branches, input); found {
+			break
+		}
+		fmt.Printf("未找到分支 %s\n", input)
+	}
+
+	if err := checkoutBranch(ctx, config, folder, branch); err == errStepCanceled {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return openAndLaunch(ctx, config, folder, path)
+}
+
+// 按名称查找分支，只存在于远程的分支也可以只写本地分支名
+func findBranch(branches []gitBranch, name string) (gitBranch, bool) {
+	for _, b := range branches {
+		if b.Name == name || b.Remote && b.local() == name {
+			return b, true
+		}
+	}
+	return gitBranch{}, false
+}
+
+// 不询问用户，在项目的指定分支上打开编辑器并启动服务，用于 quickstart <项目>@<分支>。
+// 分支已在某个 worktree 中检出时直接在其中启动；工作区干净时切换到该分支，有未提交的修改时
+// 为该分支新建 worktree，不动当前的修改。本地和远程都没有该分支时先从远程获取
+func launchBranch(ctx context.Context, config *Config, folder, branch string) error {
+	if project := findProject(config, folder); project != nil && (project.Remote != "" || project.WSL != "") {
+		return fmt.Errorf("远程项目和 WSL 项目不支持指定分支")
+	}
+	if err := os.Chdir(folder); err != nil {
+		return err
+	}
+	path, _ := os.Getwd()
+	common, _, ok := readGitDir(path)
+	if !ok {
+		return fmt.Errorf("%s 不是 git 仓库", folder)
+	}
+	beginLaunch(folder)
+
+	if dir := branchWorktree(ctx, branch); dir != "" {
+		if !samePath(dir, path) {
+			fmt.Printf("分支 %s 已在 worktree %s 中检出\n", branch, dir)
+			if err := os.Chdir(dir); err != nil {
+				return err
+			}
+			return openAndLaunch(ctx, config, filepath.Base(dir), dir)
+		}
+		fmt.Printf("已在分支 %s 上\n", branch)
+		return openAndLaunch(ctx, config, folder, path)
+	}
+
+	branches, err := listBranches(ctx)
+	if err != nil {
+		return err
+	}
+	b, found := findBranch(branches, branch)
+	if !found && !skipOffline("获取远程分支") {
+		stop := timePhase("git")
+		err := runForeground(ctx, config, folder, stepTimeout(config, "git"), []string{"git", "fetch", "--all", "--prune"})
+		stop()
+		if err != nil {
+			return err
+		}
+		if branches, err = listBranches(ctx); err != nil {
+			return err
+		}
+		b, found = findBranch(branches, branch)
+	}
+	if !found {
+		setExitCode(exitNotFound)
+		return fmt.Errorf("%s 中没有分支 %s，新建分支可在操作菜单中选择「新建 worktree」", folder, branch)
+	}
+
+	if out, err := queryOutput(ctx, "git", "status", "--porcelain"); err == nil && countLines(out) > 0 {
+		fmt.Printf("%s 有 %d 个文件未提交，为分支 %s 新建 worktree\n", folder, countLines(out), branch)
+		name, target, err := addWorktree(ctx, folder, path, common, branch)
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(target); err != nil {
+			return err
+		}
+		return openAndLaunch(ctx, config, name, target)
+	}
+	if err := checkoutBranch(ctx, config, folder, b); err != nil {
+		return err
+	}
+	return openAndLaunch(ctx, config, folder, path)
+}
+
+// 切换到分支，远程分支创建同名的跟踪分支。有未提交的修改时询问是否先储藏（包括未跟踪的文件），
+// 储藏后不自动恢复，切回原分支后可用 git stash pop 恢复
+func checkoutBranch(ctx context.Context, config *Config, folder string, branch gitBranch) error {
+	current := gitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if branch.local() == current {
+		fmt.Printf("已在分支 %s 上\n", current)
+		return nil
+	}
+	if out, err := queryOutput(ctx, "git", "status", "--porcelain"); err == nil && countLines(out) > 0 {
+		fmt.Printf("有 %d 个文件未提交\n", countLines(out))
+		if !confirm(ctx, "是否储藏后切换？(Y/n): ") {
+			fmt.Println("已取消")
+			setExitCode(exitCanceled)
+			return errStepCanceled
+		}
+		message := fmt.Sprintf("quickstart: 切换到 %s 前自动储藏", branch.local())
+		stop := timePhase("git")
+		err := runForeground(ctx, config, folder, stepTimeout(config, "git"), []string{"git", "stash", "push", "--include-untracked", "-m", message})
+		stop()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已将 %s 上的修改储藏，切回后可用 git stash pop 恢复\n", current)
+	}
+	command := []string{"git", "checkout", branch.Name}
+	if branch.Remote {
+		command = []string{"git", "checkout", "--track", branch.Name}
+	}
+	defer timePhase("git")()
+	return runForeground(ctx, config, folder, stepTimeout(config, "git"), command)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 解析全局参数，返回剩余的参数
+func parseGlobalFlags(args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			// -- 之后的参数原样保留，交给子命令处理
+			return append(rest, args[i:]...), nil
+		case arg == "--profile":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--profile 需要指定配置档名称")
+			}
+			i++
+			profileFlag = args[i]
+		case strings.HasPrefix(arg, "--profile="):
+			profileFlag = strings.TrimPrefix(arg, "--profile=")
+		case arg == "--editor" || arg == "--env":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s 需要指定值", arg)
+			}
+			i++
+			if err := setConfigFlag(arg, args[i]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(arg, "--editor=") || strings.HasPrefix(arg, "--env="):
+			name, value, _ := strings.Cut(arg, "=")
+			if err := setConfigFlag(name, value); err != nil {
+				return nil, err
+			}
+		case arg == "--quiet" || arg == "-q":
+			quietFlag = true
+		case arg == "--offline":
+			offlineFlag = true
+			// 插件、脚本和服务可通过环境变量判断是否离线
+			os.Setenv("QUICKSTART_OFFLINE", "1")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, nil
+}
+
+// 记录覆盖配置的命令行参数，--env 的值为 名称=值，可以指定多次
+func setConfigFlag(name, value string) error {
+	if name == "--editor" {
+		editorFlag = value
+		return nil
+	}
+	key, v, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("--env 的格式应为 名称=值: %s", value)
+	}
+	envFlags[key] = v
+	return nil
+}
+
+// 执行命令行子命令，不是子命令时视为要启动的项目名称或别名
+func runSubcommand(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "ps":
+		return runPS()
+	case "version", "--version":
+		printVersion()
+		return nil
+	case "license":
+		printLicense()
+		return nil
+	case "init":
+		return runInit(ctx)
+	case "doctor":
+		return runDoctor(ctx)
+	case "config":
+		return runConfigCommand(ctx, args[1:])
+	case "focus":
+		return runFocusCommand(args[1:])
+	case "last":
+		return runLastCommand(ctx)
+	case "info":
+		return runInfoCommand(ctx, args[1:])
+	case "daemon":
+		return runDaemon(ctx, args[1:])
+	case "tray":
+		return runTray(ctx)
+	case "attach":
+		return runAttachCommand(ctx, args[1:])
+	case "list":
+		return runListCommand(args[1:])
+	case "each":
+		return runEachCommand(ctx, args[1:])
+	case "group":
+		return runGroupCommand(ctx, args[1:])
+	case "start":
+		return runStartCommand(ctx, args[1:])
+	case "down":
+		return runDown(ctx)
+	case "stats":
+		return runStatsCommand(args[1:])
+	case "report":
+		return runReportCommand(ctx, args[1:])
+	case "archive", "unarchive":
+		return runArchiveCommand(args[0], args[1:])
+	default:
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("无法读取配置文件: %v", err)
+		}
+		if err := runProjectByName(ctx, config, args[0]); err != nil {
+			printUsage()
+			return err
+		}
+		return nil
+	}
+}
+
+// 打印命令行用法
+func printUsage() {
+	fmt.Println(`用法:
+  quickstart [--profile 名称] [--editor 编辑器] [--env 名称=值]... [--quiet] [--offline] [命令]
+
+命令:
+  quickstart          显示项目菜单
+  quickstart <项目>   直接启动指定名称或别名的项目
+  quickstart <项目>@<分支>  切换到该分支（有未提交的修改时新建 worktree）后启动
+  quickstart last     直接启动最近一次启动的项目，在菜单中直接回车同样如此
+  quickstart ps       列出正在运行的服务
+  quickstart down     停止所有服务和启动的依赖服务
+  quickstart version  显示版本和构建信息，也可使用 --version
+  quickstart license  显示许可证
+  quickstart init     交互式创建配置文件
+  quickstart doctor   检查运行环境并给出修复建议
+  quickstart config export [文件]  导出配置
+  quickstart config import <文件>  导入配置
+  quickstart config sync           与同步文件双向同步配置
+  quickstart config show [--effective] [项目]  显示配置，--effective 时显示合并后生效的值及来源
+  quickstart config set [--project 项目] [--secret] <名称> [值]  设置环境变量，--secret 时保存到系统钥匙串
+  quickstart info <项目>           显示项目概况
+  quickstart archive <项目>        归档项目
+  quickstart unarchive [项目]      取消归档，未指定项目时列出已归档的项目
+  quickstart stats                 显示各项目的使用统计
+  quickstart stats --daily|--weekly [--since 日期] [--csv]  按日或按周汇总各项目的运行时长，--csv 时输出为 CSV
+  quickstart list [--format=格式]  列出项目，格式为 text、json 或 alfred
+  quickstart daemon [--metrics 地址] 在后台运行守护进程，供编辑器插件通过本地套接字控制，可提供 Prometheus 指标
+  quickstart daemon install [--metrics 地址]  注册登录时自动启动的守护进程，uninstall 移除
+  quickstart tray                  在系统托盘中列出项目，可从菜单启动、停止服务
+  quickstart attach <项目>         显示守护进程中项目服务的输出，断开后服务继续运行
+  quickstart each [--tag 标签] [--parallel] [项目...] -- <命令>  在多个项目中执行命令
+  quickstart group [名称]          按依赖顺序启动项目组，未指定名称时列出项目组
+  quickstart start [--after 项目]... <项目>  等待指定项目就绪后直接启动项目的服务
+  quickstart focus [stop|log]       显示专注状态，stop 提前结束专注，log 列出最近的专注记录
+  quickstart report [文件]         生成问题报告 zip，包含日志、隐藏密钥后的配置和环境信息`)
+}
+
+// 列出所有启动器实例正在运行的服务及其就绪状态
+func runPS() error {
+	records := runningServices()
+	if len(records) == 0 {
+		fmt.Println("没有正在运行的服务。")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "项目\tPID\t运行时长\t状态\t命令")
+	for _, record := range records {
+		status := "运行中"
+		if record.Health != nil {
+			if record.Health.check() {
+				status = "✔ 就绪 " + record.Health.target()
+			} else {
+				status = "✘ 未就绪 " + record.Health.target()
+			}
+		}
+		uptime := time.Since(record.Started).Round(time.Second)
+		fmt.Fprintf(w, "%s\t%d\t%v\t%s\t%s\n", record.Project, record.PID, uptime, status, strings.Join(record.Command, " "))
+	}
+	return w.Flush()
+}
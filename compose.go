@@ -0,0 +1,21 @@
+package main
+
+import "context"
+
+// Docker Compose 的配置文件，按 docker compose 查找的顺序排列
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// 查找当前目录下的 Docker Compose 配置文件，不存在时返回空字符串
+func composeFile() string {
+	for _, name := range composeFiles {
+		if fileExists(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// 列出 docker compose up 启动方式
+func composeActions(_ context.Context) []launchAction {
+	return []launchAction{{Name: "docker compose up", Command: []string{"docker", "compose", "up"}}}
+}
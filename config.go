@@ -0,0 +1,620 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const configFile = "config.json"
+
+// Config 结构体用于存储配置信息
+type Config struct {
+	// Version 为配置文件的格式版本，读取旧版本的配置时自动升级
+	Version    int             `json:"version,omitempty"`
+	ProjectDir string          `json:"projectDir"`
+	SubDir     []string        `json:"subDir"`
+	Remarks    []Remark        `json:"remarks"`
+	Projects   []ProjectConfig `json:"projects,omitempty"`
+	// Editor 为打开项目的编辑器命令，默认为 code
+	Editor string `json:"editor,omitempty"`
+	// Editors 为 Editor 不可用时依次尝试的后备编辑器
+	Editors []string `json:"editors,omitempty"`
+	// Supervise 为服务异常退出后自动重启的默认配置
+	Supervise *SuperviseConfig `json:"supervise,omitempty"`
+	// Notify 为是否在服务就绪或异常退出时发送桌面通知
+	Notify bool `json:"notify,omitempty"`
+	// Sync 为配置同步文件的位置
+	Sync *SyncConfig `json:"sync,omitempty"`
+	// Env 为启动服务时注入的环境变量
+	Env map[string]string `json:"env,omitempty"`
+	// Keys 为绑定到项目的快捷键（a-z），在菜单中按下即可启动对应项目，无需输入编号
+	Keys map[string]string `json:"keys,omitempty"`
+	// Aliases 为项目别名，键为别名，值为项目文件夹名，可在菜单中输入或通过 quickstart <别名> 直接启动
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Groups 为一起启动的项目组，通过 quickstart group <名称> 按依赖顺序启动，可以只写为项目列表
+	Groups map[string]GroupConfig `json:"groups,omitempty"`
+	// Sort 为菜单的排序方式：name（名称）、modified（修改时间）、launched（最近启动）或 manual（按 Order），默认保持目录读取顺序
+	Sort string `json:"sort,omitempty"`
+	// Order 为 manual 排序时项目的顺序，未列出的项目排在最后
+	Order []string `json:"order,omitempty"`
+	// ShowActivity 为是否在菜单中显示项目的最近活动时间（git 最近提交时间或文件夹修改时间）
+	ShowActivity bool `json:"showActivity,omitempty"`
+	// WatchProjectDir 为是否在菜单打开期间监听工作目录，新克隆的项目无需重启即出现在菜单中
+	WatchProjectDir bool `json:"watchProjectDir,omitempty"`
+	// NewDays 为新出现的项目在菜单中显示「新」标记的天数，默认 3 天，小于 0 时不显示
+	NewDays int `json:"newDays,omitempty"`
+	// ArchiveDir 为归档目录，归档的项目会移动到该目录；未配置时归档只在菜单中隐藏项目
+	ArchiveDir string `json:"archiveDir,omitempty"`
+	// Archived 为已归档的项目
+	Archived []string `json:"archived,omitempty"`
+	// Shell 为各系统执行含 shell 语法的命令和打开终端时使用的 shell，键为 windows、linux、darwin
+	Shell map[string]string `json:"shell,omitempty"`
+	// Timeouts 为 git pull、依赖安装等短时命令的超时
+	Timeouts *TimeoutConfig `json:"timeouts,omitempty"`
+	// Mouse 为是否可以在菜单中用鼠标点击选择项目和操作
+	Mouse bool `json:"mouse,omitempty"`
+	// PTY 为是否在伪终端中运行服务，使依赖终端的工具保留颜色、进度条和交互式提示
+	PTY bool `json:"pty,omitempty"`
+	// Stats 为是否记录使用统计（各项目的启动次数和运行时长），只保存在本机，通过 quickstart stats 查看
+	Stats bool `json:"stats,omitempty"`
+	// IdleStop 为服务没有访问（端口上没有连接）、没有输出且项目文件没有变动多少分钟后自动停止，为 0 时不停止
+	IdleStop int `json:"idleStop,omitempty"`
+	// Metrics 为守护进程提供 Prometheus 指标（/metrics）的监听地址，如 127.0.0.1:9464，为空时不提供
+	Metrics string `json:"metrics,omitempty"`
+	// Hooks 为所有项目的服务事件触发的操作，如更新 Slack 状态、切换智能灯
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+	// Schedules 为守护进程按时间启动和停止项目或项目组的定时任务
+	Schedules []ScheduleConfig `json:"schedules,omitempty"`
+	// Hotkey 为托盘模式下呼出项目选择的全局快捷键
+	Hotkey *HotkeyConfig `json:"hotkey,omitempty"`
+	// KubeContext 为 skaffold、tilt、kubectl 使用的 Kubernetes 上下文，默认使用 kubeconfig 中的当前上下文
+	KubeContext string `json:"kubeContext,omitempty"`
+	// Detect 为项目类型检测的顺序和禁用的内置类型，同时包含多种项目标记时用于选择正确的类型
+	Detect *DetectConfig `json:"detect,omitempty"`
+	// Focus 为专注模式，配置后启动项目即开始计时，结束前启动其他项目需要确认
+	Focus *FocusConfig `json:"focus,omitempty"`
+	// Proxy 为 HTTP 代理，启动器自身和所有子进程都会使用，未配置时沿用系统环境中的 HTTP_PROXY 等变量
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+	// Profiles 为按机器或场景覆盖的配置，通过 --profile、QUICKSTART_PROFILE 或 hosts 选择。
+	// 配置档中填写的任意全局字段都会覆盖全局配置，保留原始内容以便写回配置文件时不丢失字段
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+	// Script 为 Starlark 脚本或 .star 脚本文件的路径（相对于配置文件所在目录），可定义 command、launch、badge 钩子
+	Script string `json:"script,omitempty"`
+
+	// ActiveProfile 为当前生效的配置档，不写入配置文件
+	ActiveProfile string `json:"-"`
+}
+
+// ProxyConfig 结构体用于存储 HTTP 代理设置，写入 HTTP_PROXY、HTTPS_PROXY、NO_PROXY 环境变量
+type ProxyConfig struct {
+	// HTTP 为 HTTP 请求的代理地址，如 http://proxy.corp:8080，未配置 https 时 HTTPS 请求同样使用
+	HTTP string `json:"http,omitempty"`
+	// HTTPS 为 HTTPS 请求的代理地址
+	HTTPS string `json:"https,omitempty"`
+	// NoProxy 为不经代理直接访问的主机、域名后缀或网段，如 localhost、.corp.example.com、10.0.0.0/8
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// SyncConfig 结构体用于存储配置同步设置
+type SyncConfig struct {
+	// Path 为同步文件路径，可位于网盘目录或 git 仓库中
+	Path string `json:"path"`
+	// Git 为是否在同步前后对同步文件所在仓库执行 pull 与 commit/push
+	Git bool `json:"git,omitempty"`
+}
+
+// ProjectConfig 结构体用于存储单个项目的启动配置
+type ProjectConfig struct {
+	Name string `json:"name"`
+	// WorkDir 为启动服务时所在的目录，相对于项目目录，默认为项目目录本身
+	WorkDir string `json:"workDir,omitempty"`
+	// EditorDir 为编辑器打开的目录，相对于项目目录，默认为项目目录本身
+	EditorDir string `json:"editorDir,omitempty"`
+	// Remote 为远程项目路径，格式为 user@host:/path，配置后项目会列在菜单中并通过 SSH 启动
+	Remote string `json:"remote,omitempty"`
+	// WSL 为 WSL 项目路径，格式为 <发行版>:/path，配置后项目会列在菜单中并通过 wsl.exe 启动
+	WSL string `json:"wsl,omitempty"`
+	// Command 为服务启动命令，配置后不再自动检测项目类型。含管道、重定向等 shell 语法时通过 shell 执行，
+	// 可使用 {{.ProjectName}}、{{.ProjectPath}}、{{.Port}}、{{env "FOO"}} 等模板变量
+	Command string `json:"command,omitempty"`
+	// Editor 覆盖全局配置的编辑器
+	Editor string `json:"editor,omitempty"`
+	// Open 为编辑器额外打开的目标，相对于项目目录，可以是 .code-workspace 工作区文件或入口文件
+	Open string `json:"open,omitempty"`
+	// ReuseWindow 为是否在已打开的编辑器窗口中打开（code -r）
+	ReuseWindow bool `json:"reuseWindow,omitempty"`
+	// Env 为启动该项目服务时额外注入的环境变量，覆盖全局配置
+	Env map[string]string `json:"env,omitempty"`
+	// Supervise 覆盖全局的自动重启配置
+	Supervise *SuperviseConfig `json:"supervise,omitempty"`
+	// Watch 为需要监听的文件模式，文件变动时重启服务，支持 ** 通配符和 ! 排除
+	Watch []string `json:"watch,omitempty"`
+	// HealthCheck 为服务启动后用于判断是否就绪的检查
+	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
+	// IdleStop 覆盖全局配置的空闲停止分钟数，小于 0 时该项目不自动停止
+	IdleStop int `json:"idleStop,omitempty"`
+	// Hooks 为该项目服务事件触发的操作，在全局配置的操作之后执行
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+	// Services 为项目依赖的数据库等服务，启动项目前检查并启动未运行的服务
+	Services []DependencyConfig `json:"services,omitempty"`
+	// Requires 为项目所需的工具及版本，如 "node >=18"、"go 1.22"、"php ^8.2"，启动项目前检查
+	Requires []string `json:"requires,omitempty"`
+	// FirstRun 为项目首次通过启动器启动时执行的初始化命令，如安装依赖、复制 .env.example、执行数据库迁移，
+	// 全部成功后不再执行。与 command 一样支持 shell 语法和模板变量
+	FirstRun []string `json:"firstRun,omitempty"`
+	// Tags 为项目标签，可通过 quickstart each --tag 或菜单中的批量执行按标签选择项目
+	Tags []string `json:"tags,omitempty"`
+	// Inputs 为启动前询问的参数，可在命令中通过 {{.Inputs.名称}} 引用
+	Inputs []InputConfig `json:"inputs,omitempty"`
+	// PTY 覆盖全局配置的伪终端设置
+	PTY *bool `json:"pty,omitempty"`
+	// Port 为项目服务的端口，可在命令中通过 {{.Port}} 引用，未配置时使用 healthCheck 中的端口
+	Port int `json:"port,omitempty"`
+	// KubeContext 覆盖全局配置的 Kubernetes 上下文
+	KubeContext string `json:"kubeContext,omitempty"`
+	// Detect 覆盖全局配置的项目类型检测顺序和禁用的类型
+	Detect *DetectConfig `json:"detect,omitempty"`
+	// Proxy 为启动该项目服务和命令时使用的代理，填写的字段覆盖全局配置
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+	// Links 为项目相关的链接（本地地址、管理后台、测试环境、CI、看板等），可在操作菜单中用浏览器打开
+	Links []LinkConfig `json:"links,omitempty"`
+	// Script 为该项目的 Starlark 脚本，其中定义的钩子优先于全局脚本
+	Script string `json:"script,omitempty"`
+
+	// 合并了项目内共享配置时为该配置文件的路径
+	source string
+}
+
+// Remark 结构体用于存储菜单中显示在项目后面的备注，以及项目的标签、颜色、置顶和别名
+type Remark struct {
+	Name   string `json:"name"`
+	Remark string `json:"remark"`
+	// Tags 为项目标签，与 projects[].tags 一起用于 quickstart each --tag
+	Tags []string `json:"tags,omitempty"`
+	// Color 为备注在菜单中的颜色：red、green、yellow、blue、magenta、cyan
+	Color string `json:"color,omitempty"`
+	// Pinned 为是否在菜单中置顶
+	Pinned bool `json:"pinned,omitempty"`
+	// Aliases 为项目别名，与 aliases 中指向该项目的别名相同
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// 备注是否只有项目名，没有任何内容
+func (r Remark) empty() bool {
+	return r.Remark == "" && len(r.Tags) == 0 && r.Color == "" && !r.Pinned && len(r.Aliases) == 0
+}
+
+// GroupConfig 结构体用于存储项目组
+type GroupConfig struct {
+	// Projects 为组内的项目
+	Projects []GroupMember `json:"projects"`
+	// Terminal 为在新终端窗口中按布局启动时使用的终端：wt（Windows Terminal）或 tmux，为空时在当前终端中启动
+	Terminal string `json:"terminal,omitempty"`
+	// Layout 为窗格布局：columns（左右并排，默认）、rows（上下排列）或 main-left（第一个窗格在左，其余在右侧上下排列）
+	Layout string `json:"layout,omitempty"`
+	// Panes 为项目服务之后追加的窗格，如查看日志
+	Panes []PaneConfig `json:"panes,omitempty"`
+	// Workspace 为是否在启动前生成包含组内所有项目的多根工作区文件并用编辑器打开，代替逐个打开项目
+	Workspace bool `json:"workspace,omitempty"`
+}
+
+// UnmarshalJSON 兼容只写为项目列表的项目组
+func (g *GroupConfig) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		*g = GroupConfig{}
+		return json.Unmarshal(data, &g.Projects)
+	}
+	type plain GroupConfig
+	return json.Unmarshal(data, (*plain)(g))
+}
+
+// PaneConfig 结构体用于存储项目组布局中追加的窗格
+type PaneConfig struct {
+	// Title 为窗格标题，默认为命令
+	Title string `json:"title,omitempty"`
+	// Project 为命令执行的项目，默认在工作目录中执行
+	Project string `json:"project,omitempty"`
+	// Command 为窗格中执行的命令，如 tail -f logs/app.log
+	Command string `json:"command"`
+}
+
+// GroupMember 结构体用于存储项目组中的一个项目
+type GroupMember struct {
+	// Project 为项目文件夹名或别名
+	Project string `json:"project"`
+	// After 为需要先启动并就绪的项目，配置了 healthCheck 的项目在检查通过后视为就绪，否则启动即视为就绪
+	After []string `json:"after,omitempty"`
+}
+
+// InputConfig 结构体用于存储启动前询问的参数
+type InputConfig struct {
+	// Name 为参数名，在命令中通过 {{.Inputs.名称}} 引用
+	Name string `json:"name"`
+	// Prompt 为询问时显示的提示，默认为参数名
+	Prompt string `json:"prompt,omitempty"`
+	// Options 为可选值，配置后只能从中选择
+	Options []string `json:"options,omitempty"`
+	// Default 为没有上次输入的值时的默认值
+	Default string `json:"default,omitempty"`
+}
+
+// LinkConfig 结构体用于存储项目链接
+type LinkConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// OpenOnLaunch 为是否在启动服务后自动打开，配置了 healthCheck 时在服务就绪后打开
+	OpenOnLaunch bool `json:"openOnLaunch,omitempty"`
+}
+
+// DependencyConfig 结构体用于存储项目依赖的服务，Container 与 Start 任选其一
+type DependencyConfig struct {
+	// Name 为服务名称，如 mysql、redis、elasticsearch
+	Name string `json:"name"`
+	// Container 为 docker 容器名，通过 docker 检查运行状态并启动
+	Container string `json:"container,omitempty"`
+	// Start 为启动命令，在后台运行，输出写入状态目录下的 logs 目录
+	Start string `json:"start,omitempty"`
+	// Stop 为 quickstart down 时的停止命令，如 docker compose down；未配置时结束启动命令的进程树
+	Stop string `json:"stop,omitempty"`
+	// Port 为本机端口，可以建立 TCP 连接时视为运行中，默认按服务名称使用常见端口
+	Port int `json:"port,omitempty"`
+	// Timeout 为等待启动的最长秒数，默认为 30
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// ScheduleConfig 结构体用于存储一个定时任务，Project 与 Group 任选其一
+type ScheduleConfig struct {
+	// Project 为定时启动的项目名称或别名
+	Project string `json:"project,omitempty"`
+	// Group 为定时启动的项目组，按依赖顺序逐批启动
+	Group string `json:"group,omitempty"`
+	// Start 为启动时间的 cron 表达式（分 时 日 月 周），如 "45 8 * * 1-5" 为工作日 8:45
+	Start string `json:"start,omitempty"`
+	// Stop 为停止时间的 cron 表达式，为空时不自动停止
+	Stop string `json:"stop,omitempty"`
+}
+
+// HotkeyConfig 结构体用于存储呼出项目选择的全局快捷键
+type HotkeyConfig struct {
+	// Keys 为快捷键，如 ctrl+alt+space；修饰键可用 ctrl、alt（option）、shift、super（win、cmd）
+	Keys string `json:"keys,omitempty"`
+	// Terminal 为打开项目选择的终端命令，启动器的命令追加在其后，如 "kitty -e"；为空时按系统选择终端
+	Terminal string `json:"terminal,omitempty"`
+}
+
+// HooksConfig 结构体用于存储服务事件触发的操作
+type HooksConfig struct {
+	// OnLaunch 在服务进程启动时执行，自动重启时不再执行
+	OnLaunch []HookConfig `json:"onLaunch,omitempty"`
+	// OnReady 在服务就绪时执行，没有就绪检查时为启动时
+	OnReady []HookConfig `json:"onReady,omitempty"`
+	// OnCrash 在服务异常退出时执行，每次异常退出都会执行
+	OnCrash []HookConfig `json:"onCrash,omitempty"`
+	// OnStop 在服务结束、不再重启时执行
+	OnStop []HookConfig `json:"onStop,omitempty"`
+}
+
+// HookConfig 结构体用于存储一个事件触发的操作，URL 与 Command 任选其一
+type HookConfig struct {
+	// URL 为接收事件的地址，以 JSON 请求体 POST 事件信息
+	URL string `json:"url,omitempty"`
+	// Command 为执行的命令，支持 {{.ProjectName}} 等模板变量，事件信息通过标准输入的 JSON 和 QUICKSTART_EVENT 等环境变量传入
+	Command string `json:"command,omitempty"`
+}
+
+// HealthCheckConfig 结构体用于存储服务就绪检查配置，URL 与 Port 任选其一
+type HealthCheckConfig struct {
+	// URL 返回 2xx/3xx 状态码时视为就绪
+	URL string `json:"url,omitempty"`
+	// Port 为本机端口，可以建立 TCP 连接时视为就绪
+	Port int `json:"port,omitempty"`
+	// Timeout 为等待就绪的最长秒数，默认为 60
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// TimeoutConfig 结构体用于存储短时命令的超时秒数
+type TimeoutConfig struct {
+	// Git 为 git pull 等 git 命令的超时，默认为 120
+	Git int `json:"git,omitempty"`
+	// Install 为依赖安装的超时，默认为 900
+	Install int `json:"install,omitempty"`
+}
+
+// DetectConfig 结构体用于存储项目类型检测的顺序，类型名与检测到的项目类型相同（如 webman、WEB、Laravel），不区分大小写
+type DetectConfig struct {
+	// Order 为优先检测的类型，按顺序排在其他类型之前，未列出的类型保持原来的顺序
+	Order []string `json:"order,omitempty"`
+	// Disable 为不再检测的类型
+	Disable []string `json:"disable,omitempty"`
+}
+
+// FocusConfig 结构体用于存储专注模式设置
+type FocusConfig struct {
+	// Minutes 为每次专注的分钟数，默认为 25
+	Minutes int `json:"minutes,omitempty"`
+}
+
+// SuperviseConfig 结构体用于存储服务异常退出后的自动重启配置
+type SuperviseConfig struct {
+	Enabled bool `json:"enabled"`
+	// MaxRetries 为连续重启的最大次数，默认为 5
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// Backoff 为首次重启前等待的秒数，之后每次翻倍，默认为 1
+	Backoff int `json:"backoff,omitempty"`
+	// MaxBackoff 为重启等待的最大秒数，默认为 60
+	MaxBackoff int `json:"maxBackoff,omitempty"`
+}
+
+func readConfig() (*Config, error) {
+	// 检测配置文件是否存在
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		// 如果配置文件不存在，则创建一个默认的配置文件,工作目录为程序所在目录（包管理器安装时为用户主目录）
+		projectDir, err := defaultProjectDir()
+		if err != nil {
+			return nil, err
+		}
+
+		defaultConfig := &Config{
+			ProjectDir: projectDir,
+			SubDir:     nil, // 默认为空
+		}
+		// 创建并写入配置文件
+		if err := writeConfig(defaultConfig); err != nil {
+			return nil, fmt.Errorf("无法创建配置文件: %v", err)
+		}
+		return defaultConfig, nil
+	}
+
+	// 读取配置文件，旧版本的配置先升级到当前版本
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = migrateConfigFile(data); err != nil {
+		return nil, err
+	}
+
+	// 解析配置文件内容到 Config 结构体
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func writeConfig(config *Config) error {
+	// 写入的配置为当前格式，不降低较新版本程序写入的版本号
+	if config.Version < configVersion {
+		config.Version = configVersion
+	}
+	// 创建配置文件
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(configPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// 编码配置信息并写入配置文件
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
+
+// 读取配置文件并应用配置档，用于启动项目；需要写回配置文件时应使用 readConfig
+func loadConfig() (*Config, error) {
+	config, _, err := loadLayers()
+	if err != nil {
+		return nil, err
+	}
+	applyProxyEnv(config)
+	return config, nil
+}
+
+// 命令行 --profile 指定的配置档
+var profileFlag string
+
+// 选择生效的配置档：优先使用 --profile，其次是 QUICKSTART_PROFILE 环境变量，最后按配置档中的 hosts 匹配主机名，
+// 多个配置档匹配时使用名称排在最前的一个，都没有时返回空字符串
+func selectProfile(config *Config) string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+	if name := os.Getenv("QUICKSTART_PROFILE"); name != "" {
+		return name
+	}
+	host, _ := os.Hostname()
+	for _, name := range sortedKeys(config.Profiles) {
+		var profile struct {
+			// Hosts 为自动启用该配置档的主机名
+			Hosts []string `json:"hosts"`
+		}
+		json.Unmarshal(config.Profiles[name], &profile)
+		for _, h := range profile.Hosts {
+			if strings.EqualFold(h, host) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// 获取启动项目服务时的环境变量，项目配置覆盖全局配置，配置了项目代理时一并注入。值为 op://、vault:// 等密钥引用时在此时读取，
+// 读取失败的变量不会注入
+func projectEnv(ctx context.Context, config *Config, name string) []string {
+	env := os.Environ()
+	add := func(vars map[string]string) {
+		for _, k := range sortedKeys(vars) {
+			v, err := resolveSecret(ctx, vars[k])
+			if err != nil {
+				fmt.Printf("无法读取环境变量 %s 的密钥 %s: %v\n", k, vars[k], err)
+				continue
+			}
+			env = append(env, k+"="+v)
+		}
+	}
+	add(config.Env)
+	if project := findProject(config, name); project != nil {
+		add(project.Env)
+		// 全局代理已写入启动器的环境变量，这里只追加项目自己的代理设置
+		if project.Proxy != nil {
+			env = append(env, mergeProxy(config.Proxy, project.Proxy).env()...)
+		}
+	}
+	return env
+}
+
+// 获取项目生效的自动重启配置，项目配置优先于全局配置
+func superviseConfig(config *Config, name string) SuperviseConfig {
+	supervise := SuperviseConfig{}
+	if config.Supervise != nil {
+		supervise = *config.Supervise
+	}
+	if project := findProject(config, name); project != nil && project.Supervise != nil {
+		supervise = *project.Supervise
+	}
+	if supervise.MaxRetries <= 0 {
+		supervise.MaxRetries = 5
+	}
+	if supervise.Backoff <= 0 {
+		supervise.Backoff = 1
+	}
+	if supervise.MaxBackoff <= 0 {
+		supervise.MaxBackoff = 60
+	}
+	return supervise
+}
+
+// 获取项目需要监听的文件模式
+func projectWatch(config *Config, name string) []string {
+	if project := findProject(config, name); project != nil {
+		return project.Watch
+	}
+	return nil
+}
+
+// 判断项目服务是否在伪终端中运行，项目配置优先于全局配置
+func projectPTY(config *Config, name string) bool {
+	if project := findProject(config, name); project != nil && project.PTY != nil {
+		return *project.PTY
+	}
+	return config.PTY
+}
+
+// 获取项目的就绪检查配置，未配置时返回 nil
+func projectHealthCheck(config *Config, name string) *HealthCheckConfig {
+	if project := findProject(config, name); project != nil && project.HealthCheck != nil {
+		if project.HealthCheck.URL != "" || project.HealthCheck.Port != 0 {
+			return project.HealthCheck
+		}
+	}
+	return nil
+}
+
+// 获取项目配置的链接
+func projectLinks(config *Config, name string) []LinkConfig {
+	if project := findProject(config, name); project != nil {
+		return project.Links
+	}
+	return nil
+}
+
+// 获取项目使用的 Kubernetes 上下文，项目配置优先
+func projectKubeContext(config *Config, name string) string {
+	if project := findProject(config, name); project != nil && project.KubeContext != "" {
+		return project.KubeContext
+	}
+	return config.KubeContext
+}
+
+// 获取项目类型检测的配置，项目配置优先，都未配置时返回 nil
+func projectDetectConfig(config *Config, name string) *DetectConfig {
+	if project := findProject(config, name); project != nil && project.Detect != nil {
+		return project.Detect
+	}
+	return config.Detect
+}
+
+// 获取状态目录下的文件路径
+func statePath(elem ...string) string {
+	return filepath.Join(append([]string{stateDir}, elem...)...)
+}
+
+// 将项目别名解析为项目文件夹名，不是别名时原样返回
+func resolveAlias(config *Config, name string) string {
+	if target, ok := projectAliases(config)[name]; ok {
+		return target
+	}
+	return name
+}
+
+// 所有项目别名，键为别名，值为项目文件夹名。包括 aliases 和 remarks 中的别名，同名时 aliases 优先
+func projectAliases(config *Config) map[string]string {
+	aliases := make(map[string]string, len(config.Aliases))
+	for _, r := range config.Remarks {
+		for _, alias := range r.Aliases {
+			aliases[alias] = r.Name
+		}
+	}
+	for alias, name := range config.Aliases {
+		aliases[alias] = name
+	}
+	return aliases
+}
+
+// 查找指定项目的启动配置，未配置时返回 nil
+func findProject(config *Config, name string) *ProjectConfig {
+	for i := range config.Projects {
+		if config.Projects[i].Name == name {
+			return &config.Projects[i]
+		}
+	}
+	return nil
+}
+
+// 配置文件变动的信号，由 watchConfig 发送
+var configChanged = make(chan struct{}, 1)
+
+// 在后台轮询配置文件的修改时间，变动时向 configChanged 发送信号
+func watchConfig() {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return
+	}
+	modified := info.ModTime()
+	go func() {
+		for range time.Tick(time.Second) {
+			info, err := os.Stat(configPath)
+			if err != nil || info.ModTime().Equal(modified) {
+				continue
+			}
+			modified = info.ModTime()
+			select {
+			case configChanged <- struct{}{}:
+			default:
+			}
+		}
+	}()
+}
+
+// 重新读取配置文件并替换 config 的内容，配置文件有误时保留原配置
+func reloadConfig(config *Config) {
+	if !fileExists(configPath) {
+		return
+	}
+	fresh, err := loadConfig()
+	if err != nil {
+		fmt.Println("配置文件有误，继续使用原配置:", err)
+		return
+	}
+	*config = *fresh
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 启动器写入钥匙串的条目使用的账户名
+const keychainAccount = "quickstart"
+
+// 设置配置文件中的环境变量：quickstart config set [--project 项目] [--secret] <名称> [值]。
+// --secret 时在提示中输入值（不回显），值写入系统钥匙串，配置文件中只保存 keychain:// 引用，启动时自动读取
+func runConfigSet(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("用法: quickstart config set [--project 项目] [--secret] <名称> [值]")
+	secret := false
+	project := ""
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--secret":
+			secret = true
+		case arg == "--project":
+			if i+1 >= len(args) {
+				return usage
+			}
+			i++
+			project = args[i]
+		case strings.HasPrefix(arg, "--project="):
+			project = strings.TrimPrefix(arg, "--project=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("未知的参数: %s", arg)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(rest) == 0 || len(rest) > 2 {
+		return usage
+	}
+	name := rest[0]
+	if strings.ContainsAny(name, "= ") {
+		return fmt.Errorf("环境变量名 %s 无效", name)
+	}
+	if secret && len(rest) == 2 {
+		// 命令行参数会留在 shell 历史中
+		return fmt.Errorf("密钥不应写在命令行中，请去掉值，在提示时输入")
+	}
+
+	if project != "" {
+		config, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("无法读取配置文件: %v", err)
+		}
+		c, ok, suggestions, err := matchProject(config, project)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			setExitCode(exitNotFound)
+			return fmt.Errorf("未找到项目 %s%s", project, notFoundHint(suggestions))
+		}
+		project = c.Name
+	}
+
+	var value string
+	var err error
+	switch {
+	case len(rest) == 2:
+		value = rest[1]
+	case secret:
+		value, err = readSecret(ctx, fmt.Sprintf("请输入 %s 的值（不回显）: ", name))
+	default:
+		value, err = readLine(ctx, fmt.Sprintf("请输入 %s 的值: ", name))
+	}
+	if err == io.EOF {
+		fmt.Println("已取消")
+		setExitCode(exitCanceled)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret {
+		// Windows 凭据管理器以目标名区分条目，每个密钥使用单独的 service
+		service := "quickstart:" + name
+		if project != "" {
+			service = "quickstart:" + project + ":" + name
+		}
+		if err := storeKeychainSecret(ctx, service, keychainAccount, value); err != nil {
+			return err
+		}
+		value = "keychain://" + service + "/" + keychainAccount
+	}
+
+	raw, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	env := &raw.Env
+	target := "全局配置"
+	if project != "" {
+		p := findProject(raw, project)
+		if p == nil {
+			raw.Projects = append(raw.Projects, ProjectConfig{Name: project})
+			p = &raw.Projects[len(raw.Projects)-1]
+		}
+		env = &p.Env
+		target = "项目 " + project
+	}
+	if *env == nil {
+		*env = make(map[string]string)
+	}
+	(*env)[name] = value
+	if err := writeConfig(raw); err != nil {
+		return fmt.Errorf("无法写入配置文件: %v", err)
+	}
+	if secret {
+		fmt.Printf("已将 %s 保存到系统钥匙串，env 中记录为 %s（%s）\n", name, value, target)
+	} else {
+		fmt.Printf("已设置 env.%s（%s）\n", name, target)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// exportFile 为导出的可移植配置文件格式
+type exportFile struct {
+	Version  string    `json:"quickstart"`
+	Exported time.Time `json:"exported"`
+	Config   *Config   `json:"config"`
+}
+
+// 执行 config 子命令
+func runConfigCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: quickstart config <export [文件]|import <文件>|sync|show [--effective] [项目]|set [--secret] <名称> [值]>")
+	}
+	switch args[0] {
+	case "export":
+		file := ""
+		if len(args) > 1 {
+			file = args[1]
+		}
+		return exportConfig(file)
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("用法: quickstart config import <文件>")
+		}
+		return importConfig(args[1])
+	case "sync":
+		return syncConfig(ctx)
+	case "show":
+		return runConfigShow(args[1:])
+	case "set":
+		return runConfigSet(ctx, args[1:])
+	default:
+		return fmt.Errorf("未知的 config 子命令: %s", args[0])
+	}
+}
+
+// 将配置编码为导出文件内容
+func encodeExport(config *Config) ([]byte, error) {
+	ver, _, _ := buildInfo()
+	return json.MarshalIndent(exportFile{Version: ver, Exported: time.Now(), Config: config}, "", "  ")
+}
+
+// 读取导出文件，兼容直接导入普通的 config.json。导入的配置可能来自旧版本，先升级到当前格式
+func decodeExport(data []byte) (*Config, error) {
+	var export struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &export); err == nil && len(export.Config) > 0 && string(export.Config) != "null" {
+		data = export.Config
+	}
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析导入文件: %v", err)
+	}
+	var config Config
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, fmt.Errorf("无法解析导入文件: %v", err)
+	}
+	return &config, nil
+}
+
+// 导出配置到文件，未指定文件时输出到标准输出
+func exportConfig(file string) error {
+	config, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	data, err := encodeExport(config)
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return err
+	}
+	fmt.Println("配置已导出到", file)
+	return nil
+}
+
+// 从文件导入配置，原配置备份为 config.json.bak
+func importConfig(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	imported, err := decodeExport(data)
+	if err != nil {
+		return err
+	}
+	if err := applyImport(imported); err != nil {
+		return err
+	}
+	fmt.Printf("已从 %s 导入配置，原配置已备份为 %s.bak\n", file, configPath)
+	return nil
+}
+
+// 写入导入的配置。导入的工作目录在本机不存在时保留本机的工作目录
+func applyImport(imported *Config) error {
+	current, err := readConfig()
+	if err == nil {
+		if _, statErr := os.Stat(imported.ProjectDir); statErr != nil && current.ProjectDir != "" {
+			fmt.Printf("导入的工作目录 %s 在本机不存在，保留 %s\n", imported.ProjectDir, current.ProjectDir)
+			imported.ProjectDir = current.ProjectDir
+		}
+		// 同步设置属于本机，不随导入覆盖
+		if imported.Sync == nil {
+			imported.Sync = current.Sync
+		}
+	}
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := os.WriteFile(configPath+".bak", data, 0644); err != nil {
+			return fmt.Errorf("无法备份配置文件: %v", err)
+		}
+	}
+	return writeConfig(imported)
+}
+
+// 计算配置内容的哈希，用于判断同步双方是否有修改
+func configHash(config *Config) string {
+	data, _ := json.Marshal(config)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// 与同步文件双向同步配置：只有一方修改时以修改方为准，双方都修改时询问用户
+func syncConfig(ctx context.Context) error {
+	config, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	if config.Sync == nil || config.Sync.Path == "" {
+		return fmt.Errorf("未配置同步文件，请在配置文件中设置 sync.path")
+	}
+	sync := config.Sync
+	dir := filepath.Dir(sync.Path)
+
+	if sync.Git && offline() {
+		return fmt.Errorf("离线模式下无法通过 git 同步配置")
+	}
+	if sync.Git {
+		if err := runGit(ctx, config, dir, "pull", "--ff-only"); err != nil {
+			return fmt.Errorf("无法拉取同步仓库: %v", err)
+		}
+	}
+
+	// 状态文件记录上次同步后本机配置和同步文件的哈希
+	localHash := configHash(config)
+	lastHashFile := statePath("sync.hash")
+	var last struct {
+		Local  string `json:"local"`
+		Remote string `json:"remote"`
+	}
+	readJSONFile(lastHashFile, &last)
+
+	var remote *Config
+	remoteHash := ""
+	if data, err := os.ReadFile(sync.Path); err == nil {
+		if remote, err = decodeExport(data); err != nil {
+			return err
+		}
+		remoteHash = configHash(remote)
+	}
+
+	localChanged := localHash != last.Local
+	remoteChanged := remote != nil && remoteHash != last.Remote
+	pull := false
+	switch {
+	case remote != nil && remoteHash == localHash:
+		fmt.Println("配置已是最新")
+	case remote == nil || localChanged && !remoteChanged:
+	case remoteChanged && !localChanged:
+		pull = true
+	case !remoteChanged && !localChanged:
+		fmt.Println("配置已是最新")
+	default:
+		pull = !confirm(ctx, "本机配置和同步文件都已修改，是否用本机配置覆盖同步文件？(Y/n): ")
+	}
+	push := remote == nil || localChanged && !pull && remoteHash != localHash
+
+	if pull {
+		if err := applyImport(remote); err != nil {
+			return err
+		}
+		fmt.Println("已从同步文件更新本机配置")
+		if config, err = readConfig(); err != nil {
+			return err
+		}
+	}
+	if push {
+		data, err := encodeExport(config)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(sync.Path, data, 0644); err != nil {
+			return err
+		}
+		remoteHash = configHash(config)
+		fmt.Println("已将本机配置写入同步文件", sync.Path)
+		if sync.Git {
+			name := filepath.Base(sync.Path)
+			if err := runGit(ctx, config, dir, "add", name); err != nil {
+				return err
+			}
+			host, _ := os.Hostname()
+			if err := runGit(ctx, config, dir, "commit", "-m", "quickstart: sync config from "+host, "--", name); err != nil {
+				return err
+			}
+			if err := runGit(ctx, config, dir, "push"); err != nil {
+				return fmt.Errorf("无法推送同步仓库: %v", err)
+			}
+		}
+	}
+
+	last.Local, last.Remote = configHash(config), remoteHash
+	data, err := json.Marshal(last)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(lastHashFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(lastHashFile, data, 0644)
+}
+
+// 在指定目录中执行 git 命令，超时或按下 Ctrl+C 时结束
+func runGit(ctx context.Context, config *Config, dir string, args ...string) error {
+	return runStep(ctx, stepTimeout(config, "git"), nil, append([]string{"git", "-C", dir}, args...)...)
+}
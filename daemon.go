@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-quickStart/ipc"
+)
+
+// 每个服务保留的最近输出行数，StreamLogs 时先返回这些输出
+const daemonLogLines = 1000
+
+// logBuffer 保存服务最近的输出，并转发给正在读取日志的客户端
+type logBuffer struct {
+	mu      sync.Mutex
+	lines   []ipc.LogLine
+	partial []byte
+	subs    map[chan ipc.LogLine]struct{}
+	done    chan struct{}
+}
+
+func newLogBuffer() *logBuffer {
+	return &logBuffer{subs: make(map[chan ipc.LogLine]struct{}), done: make(chan struct{})}
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.partial = append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := ipc.LogLine{Time: time.Now(), Text: string(bytes.TrimRight(b.partial[:i], "\r"))}
+		b.partial = b.partial[i+1:]
+		b.lines = append(b.lines, line)
+		if len(b.lines) > daemonLogLines {
+			b.lines = b.lines[len(b.lines)-daemonLogLines:]
+		}
+		// 客户端读取过慢时丢弃新的输出，不阻塞服务
+		for sub := range b.subs {
+			select {
+			case sub <- line:
+			default:
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// 订阅输出，返回已有的输出和新输出的通道，服务退出后 done 关闭
+func (b *logBuffer) subscribe() (history []ipc.LogLine, lines chan ipc.LogLine, done <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines = make(chan ipc.LogLine, 256)
+	b.subs[lines] = struct{}{}
+	return append([]ipc.LogLine(nil), b.lines...), lines, b.done
+}
+
+func (b *logBuffer) unsubscribe(lines chan ipc.LogLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, lines)
+}
+
+// daemonService 为守护进程启动的一个服务
+type daemonService struct {
+	info     ipc.Service
+	svc      *service
+	logs     *logBuffer
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// 停止服务并等待退出
+func (s *daemonService) shutdown() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.logs.done:
+	case <-time.After(10 * time.Second):
+	}
+}
+
+// 服务是否仍在运行
+func (s *daemonService) running() bool {
+	select {
+	case <-s.logs.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// 服务是否已就绪，没有就绪检查的服务启动即就绪
+func (s *daemonService) ready() bool {
+	select {
+	case <-s.svc.ready:
+		return s.svc.readyOK
+	default:
+		return false
+	}
+}
+
+// daemon 为后台运行的守护进程，通过本地套接字接受控制请求
+type daemon struct {
+	ctx      context.Context // 守护进程的上下文，Ctrl+C 或收到结束信号时取消，其中运行的服务随之停止
+	mu       sync.Mutex
+	services map[string]*daemonService
+	metrics  *daemonMetrics
+}
+
+// 执行 daemon 子命令：quickstart daemon [--metrics 地址]。在状态目录下的套接字上监听 ipc 请求，
+// 指定了 --metrics 或配置了 metrics 时同时在该地址上提供 Prometheus 指标，Ctrl+C 或收到结束信号时停止所有服务后退出。
+// quickstart daemon install/uninstall 注册或移除登录时自动启动的守护进程
+func runDaemon(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "install":
+			return installDaemon(args[1:])
+		case "uninstall":
+			return uninstallDaemon()
+		}
+	}
+	metricsAddr := ""
+	if config, err := loadConfig(); err == nil {
+		metricsAddr = config.Metrics
+	}
+	if addr, err := parseDaemonArgs(args); err != nil {
+		return err
+	} else if addr != "" {
+		metricsAddr = addr
+	}
+
+	path, err := ipc.SocketPath()
+	if err != nil {
+		return fmt.Errorf("无法确定套接字路径: %v", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("守护进程已在运行: %s", path)
+	}
+	// 上次异常退出时留下的套接字文件会导致监听失败
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("无法监听 %s: %v", path, err)
+	}
+	defer os.Remove(path)
+	fmt.Printf("守护进程已启动，监听 %s，Ctrl+C 停止\n", path)
+
+	d := &daemon{ctx: ctx, services: make(map[string]*daemonService), metrics: newDaemonMetrics()}
+	if metricsAddr != "" {
+		closeMetrics, err := d.serveMetrics(metricsAddr)
+		if err != nil {
+			return err
+		}
+		defer closeMetrics()
+	}
+	go d.runSchedules()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			break
+		}
+		go d.serve(conn)
+	}
+	d.mu.Lock()
+	services := make([]*daemonService, 0, len(d.services))
+	for _, svc := range d.services {
+		services = append(services, svc)
+	}
+	d.mu.Unlock()
+	for _, svc := range services {
+		svc.shutdown()
+	}
+	fmt.Println("守护进程已停止")
+	return nil
+}
+
+// 解析 daemon 子命令的参数，返回 --metrics 指定的地址
+func parseDaemonArgs(args []string) (metricsAddr string, err error) {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--metrics":
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("用法: quickstart daemon [--metrics 地址]")
+			}
+			i++
+			metricsAddr = args[i]
+		case strings.HasPrefix(arg, "--metrics="):
+			metricsAddr = strings.TrimPrefix(arg, "--metrics=")
+		default:
+			return "", fmt.Errorf("未知的参数: %s", arg)
+		}
+	}
+	return metricsAddr, nil
+}
+
+// 处理一个连接上的请求，直到连接关闭。请求在单独的协程中读取，
+// 以便在持续返回日志时也能发现客户端已断开
+func (d *daemon) serve(conn net.Conn) {
+	defer conn.Close()
+	encoder := json.NewEncoder(conn)
+	requests := make(chan []byte)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(requests)
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			select {
+			case requests <- append([]byte(nil), scanner.Bytes()...):
+			case <-stop:
+				return
+			}
+		}
+	}()
+	for line := range requests {
+		var req ipc.Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			encoder.Encode(ipc.Response{Error: fmt.Sprintf("无法解析请求: %v", err)})
+			continue
+		}
+		if req.Type == ipc.StreamLogs {
+			if err := d.streamLogs(encoder, requests, req.Project); err != nil {
+				return
+			}
+			continue
+		}
+		if err := encoder.Encode(d.handle(req)); err != nil {
+			return
+		}
+	}
+}
+
+// 处理 StreamLogs 以外的请求
+func (d *daemon) handle(req ipc.Request) ipc.Response {
+	switch req.Type {
+	case ipc.ListProjects:
+		projects, err := d.listProjects()
+		if err != nil {
+			return ipc.Response{Error: err.Error()}
+		}
+		return ipc.Response{Projects: projects}
+	case ipc.Launch:
+		info, err := d.launch(req.Project)
+		if err != nil {
+			return ipc.Response{Error: err.Error()}
+		}
+		return ipc.Response{Service: info}
+	case ipc.Stop:
+		svc := d.service(req.Project)
+		if svc == nil || !svc.running() {
+			return ipc.Response{Error: fmt.Sprintf("%s 没有由守护进程启动的服务在运行", req.Project)}
+		}
+		svc.shutdown()
+		return ipc.Response{Service: &svc.info}
+	default:
+		return ipc.Response{Error: fmt.Sprintf("未知的请求类型: %s", req.Type)}
+	}
+}
+
+// 查找项目的服务
+func (d *daemon) service(name string) *daemonService {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.services[name]
+}
+
+// 列出工作目录中的项目，标记由守护进程启动且仍在运行的项目
+func (d *daemon) listProjects() ([]ipc.Project, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	items, err := discoverProjects(config)
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]ipc.Project, 0, len(items))
+	for _, item := range items {
+		svc := d.services[item.Name]
+		projects = append(projects, ipc.Project{
+			Name:    item.Name,
+			Path:    item.Path,
+			Remark:  item.Remark,
+			Aliases: item.Aliases,
+			Running: svc != nil && svc.running(),
+			New:     item.New,
+		})
+	}
+	return projects, nil
+}
+
+// 在后台启动项目的服务。启动命令按项目配置或第一个匹配的项目类型确定，不询问用户
+func (d *daemon) launch(name string) (*ipc.Service, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	name = resolveAlias(config, name)
+
+	// 项目检测基于当前目录，持有锁期间切换目录
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if svc := d.services[name]; svc != nil && svc.running() {
+		return nil, fmt.Errorf("%s 已在运行", name)
+	}
+	if project := findProject(config, name); project != nil && (project.Remote != "" || project.WSL != "") {
+		return nil, fmt.Errorf("守护进程不支持启动远程或 WSL 项目: %s", name)
+	}
+	path := filepath.Join(config.ProjectDir, name)
+	if err := os.Chdir(path); err != nil {
+		return nil, fmt.Errorf("无法进入项目目录: %v", err)
+	}
+	if project := findProject(config, name); project != nil && project.WorkDir != "" {
+		if err := os.Chdir(project.WorkDir); err != nil {
+			return nil, fmt.Errorf("无法进入启动目录 %s: %v", project.WorkDir, err)
+		}
+	}
+	command := autoLaunchCommand(d.ctx, config, name)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("未检测到 %s 的启动命令", name)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &daemonService{logs: newLogBuffer(), stop: make(chan struct{})}
+	svc := newService(d.ctx, config, name, wrapCommand(batchCommand(command)))
+	svc.Path = path
+	svc.Dir = dir
+	svc.Output = entry.logs
+	svc.Stop = entry.stop
+	svc.ready = make(chan struct{})
+	entry.svc = svc
+	entry.info = ipc.Service{Project: name, Dir: dir, Command: svc.Command, Started: time.Now()}
+	d.services[name] = entry
+	recordLaunch(name)
+	d.metrics.launched(svc, entry.info.Started)
+	go func() {
+		if err := svc.run(d.ctx); err != nil {
+			fmt.Fprintf(entry.logs, "服务已退出: %v\n", err)
+			d.metrics.crashed(name)
+		}
+		close(entry.logs.done)
+	}()
+	fmt.Printf("已启动 %s: %v\n", name, svc.Command)
+	return &entry.info, nil
+}
+
+// 依次返回服务已有的输出和新输出，服务退出后发送 Done。客户端断开时（requests 关闭）结束并取消订阅，
+// 期间连接被占用，收到的其他请求不处理
+func (d *daemon) streamLogs(encoder *json.Encoder, requests <-chan []byte, name string) error {
+	svc := d.service(name)
+	if svc == nil {
+		return encoder.Encode(ipc.Response{Error: fmt.Sprintf("%s 没有由守护进程启动的服务", name)})
+	}
+	history, lines, done := svc.logs.subscribe()
+	defer svc.logs.unsubscribe(lines)
+	for i := range history {
+		if err := encoder.Encode(ipc.Response{Log: &history[i]}); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case line := <-lines:
+			if err := encoder.Encode(ipc.Response{Log: &line}); err != nil {
+				return err
+			}
+		case <-done:
+			for len(lines) > 0 {
+				line := <-lines
+				if err := encoder.Encode(ipc.Response{Log: &line}); err != nil {
+					return err
+				}
+			}
+			return encoder.Encode(ipc.Response{Done: true})
+		case _, ok := <-requests:
+			if !ok {
+				return io.EOF
+			}
+		}
+	}
+}
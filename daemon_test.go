@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"go-quickStart/ipc"
+)
+
+// 订阅服务输出的客户端数
+func (b *logBuffer) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+func TestStreamLogsClientGone(t *testing.T) {
+	logs := newLogBuffer()
+	d := &daemon{services: map[string]*daemonService{"api": {logs: logs}}}
+	server, client := net.Pipe()
+	served := make(chan struct{})
+	go func() {
+		d.serve(server)
+		close(served)
+	}()
+
+	if err := json.NewEncoder(client).Encode(ipc.Request{Type: ipc.StreamLogs, Project: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	logs.Write([]byte("ready\n"))
+	var resp ipc.Response
+	if err := json.NewDecoder(bufio.NewReader(client)).Decode(&resp); err != nil || resp.Log == nil || resp.Log.Text != "ready" {
+		t.Fatalf("第一行输出 = %+v, %v", resp, err)
+	}
+
+	// 服务没有新的输出时客户端断开，同样应结束并取消订阅
+	client.Close()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("客户端断开后连接没有结束")
+	}
+	if n := logs.subscribers(); n != 0 {
+		t.Errorf("客户端断开后仍有 %d 个订阅", n)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go-quickStart/ipc"
+)
+
+// launchd 代理和 Windows 启动项使用的名称
+const daemonLabel = "com.quickstart.daemon"
+
+// systemd 用户服务的文件名
+const daemonUnit = "quickstart.service"
+
+// Windows 登录时执行的启动项所在的注册表键
+const runKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`
+
+// 执行 daemon install：将守护进程注册为登录时自动启动的用户级服务。Linux 为 systemd 用户服务，
+// macOS 为 launchd 代理，Windows 为当前用户的启动项。args 为传给守护进程的参数，如 --metrics
+func installDaemon(args []string) error {
+	if _, err := parseDaemonArgs(args); err != nil {
+		return fmt.Errorf("%v\n用法: quickstart daemon install [--metrics 地址]", err)
+	}
+	command, err := daemonCommand(args)
+	if err != nil {
+		return err
+	}
+	// 已有守护进程在运行时只注册，下次登录时再启动，避免与正在运行的守护进程争用套接字
+	running := false
+	if client, err := ipc.Dial(); err == nil {
+		client.Close()
+		running = true
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		err = installSystemdUnit(command, running)
+	case "darwin":
+		err = installLaunchAgent(command, running)
+	case "windows":
+		err = installRunEntry(command, running)
+	default:
+		return fmt.Errorf("%s 暂不支持注册守护进程，请在登录脚本中执行 quickstart daemon", runtime.GOOS)
+	}
+	if err != nil {
+		return err
+	}
+	if running {
+		fmt.Println("已有守护进程在运行，注册的守护进程将在下次登录时启动")
+	} else {
+		fmt.Println("守护进程已启动，之后登录时自动启动")
+	}
+	return nil
+}
+
+// 执行 daemon uninstall：停止并移除 daemon install 注册的守护进程
+func uninstallDaemon() error {
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		err = uninstallSystemdUnit()
+	case "darwin":
+		err = uninstallLaunchAgent()
+	case "windows":
+		err = uninstallRunEntry()
+	default:
+		return fmt.Errorf("%s 暂不支持注册守护进程", runtime.GOOS)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("已移除自动启动的守护进程")
+	return nil
+}
+
+// 守护进程的完整命令。使用当前执行文件的真实路径，并保留命令行指定的配置档
+func daemonCommand(args []string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("无法获取当前执行文件的路径: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	command := []string{exe}
+	if profileFlag != "" {
+		command = append(command, "--profile", profileFlag)
+	}
+	return append(append(command, "daemon"), args...), nil
+}
+
+// 执行注册守护进程所需的系统命令，失败时附带命令的输出
+func runServiceCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return fmt.Errorf("%s %s: %v", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// systemd 用户服务文件的路径
+func systemdUnitPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定配置目录: %v", err)
+	}
+	return filepath.Join(configDir, "systemd", "user", daemonUnit), nil
+}
+
+// 写入 systemd 用户服务并启用。服务不继承登录 shell 的环境变量，
+// 写入当前的 PATH 以便守护进程找到 npm、go 等命令
+func installSystemdUnit(command []string, running bool) error {
+	path, err := systemdUnitPath()
+	if err != nil {
+		return err
+	}
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = systemdEscape(strconv.Quote(arg))
+	}
+	unit := fmt.Sprintf(`[Unit]
+Description=QuickStart 守护进程
+
+[Service]
+ExecStart=%s
+Environment=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`, strings.Join(quoted, " "), systemdEscape(strconv.Quote("PATH="+os.Getenv("PATH"))))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("已写入 %s\n", path)
+	if err := runServiceCommand("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	if running {
+		return runServiceCommand("systemctl", "--user", "enable", daemonUnit)
+	}
+	return runServiceCommand("systemctl", "--user", "enable", "--now", daemonUnit)
+}
+
+// 转义 systemd 服务文件中的 % 和 $，避免被当作说明符和变量展开
+func systemdEscape(s string) string {
+	return strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+}
+
+// 停用并删除 systemd 用户服务
+func uninstallSystemdUnit() error {
+	path, err := systemdUnitPath()
+	if err != nil {
+		return err
+	}
+	if !fileExists(path) {
+		return fmt.Errorf("未注册守护进程: %s 不存在", path)
+	}
+	if err := runServiceCommand("systemctl", "--user", "disable", "--now", daemonUnit); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return runServiceCommand("systemctl", "--user", "daemon-reload")
+}
+
+// launchd 代理配置文件的路径
+func launchAgentPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定用户目录: %v", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", daemonLabel+".plist"), nil
+}
+
+// 写入 launchd 代理并加载。登录时启动，异常退出后重新启动，输出写入状态目录下的 daemon.log
+func installLaunchAgent(command []string, running bool) error {
+	path, err := launchAgentPath()
+	if err != nil {
+		return err
+	}
+	var args strings.Builder
+	for _, arg := range command {
+		fmt.Fprintf(&args, "\t\t<string>%s</string>\n", html.EscapeString(arg))
+	}
+	logPath := html.EscapeString(statePath("daemon.log"))
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>%s</string>
+	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, daemonLabel, args.String(), html.EscapeString(os.Getenv("PATH")), logPath, logPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("已写入 %s\n", path)
+	if running {
+		return nil
+	}
+	// 重新注册时先卸载旧的代理，否则 load 会提示已加载
+	exec.Command("launchctl", "unload", path).Run()
+	return runServiceCommand("launchctl", "load", "-w", path)
+}
+
+// 卸载并删除 launchd 代理
+func uninstallLaunchAgent() error {
+	path, err := launchAgentPath()
+	if err != nil {
+		return err
+	}
+	if !fileExists(path) {
+		return fmt.Errorf("未注册守护进程: %s 不存在", path)
+	}
+	exec.Command("launchctl", "unload", "-w", path).Run()
+	return os.Remove(path)
+}
+
+// 守护进程在 Windows 上的启动命令：通过 PowerShell 在隐藏的窗口中运行，登录时不会弹出控制台窗口
+func windowsDaemonCommand(command []string) []string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	}
+	return []string{"powershell", "-NoProfile", "-WindowStyle", "Hidden", "-Command", "& " + strings.Join(quoted, " ")}
+}
+
+// 在当前用户的启动项中注册守护进程并立即在后台启动
+func installRunEntry(command []string, running bool) error {
+	args := windowsDaemonCommand(command)
+	value := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t\"") {
+			arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+		}
+		value[i] = arg
+	}
+	if err := runServiceCommand("reg", "add", runKey, "/v", daemonLabel, "/t", "REG_SZ", "/d", strings.Join(value, " "), "/f"); err != nil {
+		return err
+	}
+	fmt.Printf("已添加启动项 %s\\%s\n", runKey, daemonLabel)
+	if running {
+		return nil
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	// 守护进程不随当前终端退出
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("无法启动守护进程: %v", err)
+	}
+	return cmd.Process.Release()
+}
+
+// 删除当前用户启动项中的守护进程。已在运行的守护进程继续运行，需要在任务管理器中结束
+func uninstallRunEntry() error {
+	if err := runServiceCommand("reg", "delete", runKey, "/v", daemonLabel, "/f"); err != nil {
+		return fmt.Errorf("未注册守护进程: %v", err)
+	}
+	fmt.Println("正在运行的守护进程不会停止，可在任务管理器中结束")
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 常见服务的默认端口，依赖服务未配置端口时使用
+var defaultServicePorts = map[string]int{
+	"mysql":         3306,
+	"mariadb":       3306,
+	"postgres":      5432,
+	"postgresql":    5432,
+	"redis":         6379,
+	"mongodb":       27017,
+	"mongo":         27017,
+	"elasticsearch": 9200,
+	"rabbitmq":      5672,
+	"memcached":     11211,
+	"kafka":         9092,
+}
+
+// 依赖服务用于判断是否运行的端口，无法确定时返回 0
+func (d *DependencyConfig) port() int {
+	if d.Port > 0 {
+		return d.Port
+	}
+	return defaultServicePorts[strings.ToLower(d.Name)]
+}
+
+// 等待依赖服务启动的最长时间
+func (d *DependencyConfig) timeout() time.Duration {
+	if d.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(d.Timeout) * time.Second
+}
+
+// 检查依赖服务是否运行：配置了容器时查询容器状态，否则检查端口。known 为 false 表示无法判断
+func (d *DependencyConfig) running(ctx context.Context) (running, known bool) {
+	if d.Container != "" {
+		out, err := queryOutput(ctx, "docker", "inspect", "-f", "{{.State.Running}}", d.Container)
+		return err == nil && strings.TrimSpace(string(out)) == "true", true
+	}
+	if port := d.port(); port > 0 {
+		return (&HealthCheckConfig{Port: port}).check(), true
+	}
+	return false, false
+}
+
+// 依赖服务的运行状态，用于项目信息
+func (d *DependencyConfig) status(ctx context.Context) string {
+	running, known := d.running(ctx)
+	switch {
+	case !known:
+		return "未知"
+	case running:
+		return "运行中"
+	default:
+		return "未运行"
+	}
+}
+
+// 启动依赖服务：容器通过 docker start 启动，启动命令在后台运行，启动器退出后继续运行
+func (d *DependencyConfig) start(ctx context.Context, config *Config, folder string) error {
+	if d.Container != "" {
+		if _, err := queryOutput(ctx, "docker", "start", d.Container); err != nil {
+			return fmt.Errorf("无法启动容器 %s: %v", d.Container, err)
+		}
+		recordDependency(startedDependency{Project: folder, Name: d.Name, Container: d.Container, Started: time.Now()})
+		return nil
+	}
+	if d.Start == "" {
+		return fmt.Errorf("未配置容器或启动命令")
+	}
+	logPath := statePath("logs", d.Name+".log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return err
+	}
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	start, err := expandCommand(config, folder, d.Start)
+	if err != nil {
+		return err
+	}
+	command := batchCommand(commandArgs(config, start))
+	cmd := exec.Command(command[0], command[1:]...)
+	setBatchCmdLine(cmd)
+	cmd.Env = projectEnv(ctx, config, folder)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	// 放入独立的进程组，停止项目服务时按下的 Ctrl+C 不会结束依赖服务
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	fmt.Printf("%s 的输出写入 %s\n", d.Name, logPath)
+	stop := d.Stop
+	if stop == "" {
+		stop = defaultStopCommand(start)
+	} else if stop, err = expandCommand(config, folder, stop); err != nil {
+		return err
+	}
+	dir, _ := os.Getwd()
+	recordDependency(startedDependency{Project: folder, Name: d.Name, PID: cmd.Process.Pid, Stop: stop, Dir: dir, Started: time.Now()})
+	return cmd.Process.Release()
+}
+
+// startedDependency 为启动器启动的依赖服务，quickstart down 时按启动的相反顺序停止
+type startedDependency struct {
+	Project   string    `json:"project"`
+	Name      string    `json:"name"`
+	Container string    `json:"container,omitempty"`
+	PID       int       `json:"pid,omitempty"`
+	Stop      string    `json:"stop,omitempty"` // 停止命令，为空时结束 PID 的进程树
+	Dir       string    `json:"dir,omitempty"`  // 停止命令执行的目录
+	Started   time.Time `json:"started"`
+}
+
+// 记录启动的依赖服务的文件路径
+func dependenciesPath() string {
+	return statePath("dependencies.json")
+}
+
+// 读取启动器启动的依赖服务
+func startedDependencies() []startedDependency {
+	var deps []startedDependency
+	readJSONFile(dependenciesPath(), &deps)
+	return deps
+}
+
+// 保存启动器启动的依赖服务，为空时删除记录文件
+func writeStartedDependencies(deps []startedDependency) error {
+	if len(deps) == 0 {
+		err := os.Remove(dependenciesPath())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.MarshalIndent(deps, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dependenciesPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dependenciesPath(), data, 0644)
+}
+
+// 记录启动的依赖服务，同一项目的同名服务只保留最近一次
+func recordDependency(dep startedDependency) {
+	deps := startedDependencies()
+	kept := deps[:0]
+	for _, d := range deps {
+		if d.Project != dep.Project || d.Name != dep.Name {
+			kept = append(kept, d)
+		}
+	}
+	if err := writeStartedDependencies(append(kept, dep)); err != nil {
+		logf("无法记录依赖服务 %s: %v", dep.Name, err)
+	}
+}
+
+// 根据常见的后台启动命令推断停止命令：docker compose up 对应 down，tmux new-session -s 对应 kill-session，
+// 无法推断时返回空字符串
+func defaultStopCommand(start string) string {
+	args := splitCommand(start)
+	for i := 0; i+1 < len(args); i++ {
+		switch {
+		case args[i] == "docker-compose" && args[i+1] == "up":
+			return strings.Join(args[:i+1], " ") + " down"
+		case args[i] == "docker" && args[i+1] == "compose":
+			for j := i + 2; j < len(args); j++ {
+				if args[j] == "up" {
+					return strings.Join(args[:j], " ") + " down"
+				}
+			}
+		case args[i] == "tmux" && (args[i+1] == "new-session" || args[i+1] == "new"):
+			for j := i + 2; j+1 < len(args); j++ {
+				if args[j] == "-s" {
+					return "tmux kill-session -t " + args[j+1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// 等待依赖服务运行，超时返回 false
+func (d *DependencyConfig) wait(ctx context.Context) bool {
+	deadline := time.Now().Add(d.timeout())
+	for {
+		if running, _ := d.running(ctx); running {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(healthInterval)
+	}
+}
+
+// 启动项目前检查依赖服务，启动未运行的服务。有服务启动失败时询问是否继续，返回是否继续启动项目
+func ensureDependencies(ctx context.Context, config *Config, folder string) bool {
+	project := findProject(config, folder)
+	if project == nil || len(project.Services) == 0 {
+		return true
+	}
+	ok := true
+	for i := range project.Services {
+		d := &project.Services[i]
+		running, known := d.running(ctx)
+		if !known {
+			fmt.Printf("依赖服务 %s 未配置端口或容器，无法检查运行状态，已跳过\n", d.Name)
+			continue
+		}
+		if running {
+			fmt.Printf("✔ 依赖服务 %s 运行中\n", d.Name)
+			continue
+		}
+		if d.Container == "" && d.Start == "" {
+			fmt.Printf("✘ 依赖服务 %s 未运行，且未配置启动方式\n", d.Name)
+			ok = false
+			continue
+		}
+		fmt.Printf("正在启动依赖服务 %s\n", d.Name)
+		if err := d.start(ctx, config, folder); err != nil {
+			fmt.Printf("✘ 无法启动依赖服务 %s: %v\n", d.Name, err)
+			ok = false
+			continue
+		}
+		if !d.wait(ctx) {
+			fmt.Printf("✘ 依赖服务 %s 在 %v 内未启动\n", d.Name, d.timeout())
+			ok = false
+			continue
+		}
+		fmt.Printf("✔ 依赖服务 %s 已启动\n", d.Name)
+	}
+	return ok || confirm(ctx, "部分依赖服务未运行，是否继续启动项目？(Y/n): ")
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// launchAction 表示项目的一种启动方式
+type launchAction struct {
+	Name    string   // 菜单中显示的名称
+	Command []string // 启动命令及参数
+	Env     []string // 追加的环境变量
+	Process string   // Procfile 中的进程名，同一项目同时运行多个进程时用于区分输出
+	// Group 为同时启动的多个进程，非空时忽略 Command
+	Group []launchAction
+}
+
+// detector 用于识别项目类型并给出可选的启动方式，检测均基于当前目录
+type detector struct {
+	Name    string                                   // 项目类型，用于提示信息
+	Service string                                   // 服务名称，用于提示信息
+	Match   func() bool                              // 判断当前目录是否为该类型项目
+	Actions func(ctx context.Context) []launchAction // 可选的启动方式
+	Install func() *installStep                      // 缺少依赖时返回安装步骤，无需安装时返回 nil
+}
+
+// installStep 表示启动前需要执行的依赖安装
+type installStep struct {
+	Reason  string   // 需要安装的原因，用于提示信息
+	Command []string // 安装命令及参数
+	Done    func()   // 安装成功后的回调，可为 nil
+}
+
+// 内置的项目类型检测，按顺序匹配。同时匹配多种类型时由用户选择，并记住该项目的选择
+var detectors = []detector{
+	{
+		Name:    "Procfile",
+		Service: "Procfile",
+		Match:   isProcfileProject,
+		Actions: procfileActions,
+	},
+	{
+		Name:    "monorepo",
+		Service: "web",
+		Match:   isMonorepo,
+		Actions: monorepoActions,
+		Install: nodeInstall,
+	},
+	{
+		Name:    "Flutter",
+		Service: "Flutter",
+		Match:   isFlutterProject,
+		Actions: flutterActions,
+	},
+	{
+		Name:    "React Native",
+		Service: "React Native",
+		Match:   isReactNativeProject,
+		Actions: reactNativeActions,
+		Install: nodeInstall,
+	},
+	{
+		Name:    "Laravel",
+		Service: "Laravel",
+		Match:   func() bool { return fileExists("artisan") },
+		Actions: laravelActions,
+		Install: composerInstall,
+	},
+	{
+		Name:    "WEB",
+		Service: "web",
+		Match:   func() bool { return fileExists("package.json") },
+		Actions: func(_ context.Context) []launchAction {
+			return []launchAction{{Name: "npm run serve", Command: []string{"npm", "run", "serve"}}}
+		},
+		Install: nodeInstall,
+	},
+	{
+		Name:    "webman",
+		Service: "webman",
+		Match:   func() bool { return fileExists("webman") },
+		Actions: webmanActions,
+		Install: composerInstall,
+	},
+	{
+		Name:    "PHP",
+		Service: "php",
+		Match:   func() bool { return fileExists("composer.json") },
+		Actions: phpActions,
+		Install: composerInstall,
+	},
+	{
+		Name:    "Rust",
+		Service: "cargo",
+		Match:   func() bool { return fileExists("Cargo.toml") },
+		Actions: rustActions,
+	},
+	{
+		Name:    "Java",
+		Service: "Java",
+		Match:   isJavaProject,
+		Actions: javaActions,
+	},
+	{
+		Name:    "Go",
+		Service: "go",
+		Match:   func() bool { return fileExists("go.mod") },
+		Actions: goActions,
+	},
+	{
+		Name:    ".NET",
+		Service: "dotnet",
+		Match:   func() bool { return len(csprojFiles()) > 0 },
+		Actions: dotnetActions,
+	},
+	{
+		Name:    "Kubernetes",
+		Service: "Kubernetes",
+		Match:   isKubernetesProject,
+		Actions: kubernetesActions,
+	},
+	{
+		Name:    "Docker Compose",
+		Service: "docker compose",
+		Match:   func() bool { return composeFile() != "" },
+		Actions: composeActions,
+	},
+}
+
+// 按配置调整检测顺序：去掉禁用的类型，Order 中的类型按顺序排在最前，其余保持原来的顺序
+func arrangeDetectors(list []detector, detect *DetectConfig) []detector {
+	if detect == nil {
+		return list
+	}
+	rank := func(d detector) int {
+		for i, name := range detect.Order {
+			if strings.EqualFold(name, d.Name) {
+				return i
+			}
+		}
+		return len(detect.Order)
+	}
+	var result []detector
+	for _, d := range list {
+		disabled := false
+		for _, name := range detect.Disable {
+			disabled = disabled || strings.EqualFold(name, d.Name)
+		}
+		if !disabled {
+			result = append(result, d)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return rank(result[i]) < rank(result[j]) })
+	return result
+}
+
+// 是否为内置的项目类型，不区分大小写
+func isDetectorName(name string) bool {
+	for _, d := range detectors {
+		if strings.EqualFold(name, d.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// 不询问用户，确定当前目录中项目的启动命令：脚本的 command 钩子给出命令或配置了启动命令时直接使用，
+// 否则使用记住的或第一个匹配的项目类型的第一个单命令启动方式
+func autoLaunchCommand(ctx context.Context, config *Config, folder string) []string {
+	if command, ok, err := scriptCommand(config, folder); err != nil {
+		fmt.Println(err)
+		return nil
+	} else if ok {
+		return command
+	}
+	if project := findProject(config, folder); project != nil && project.Command != "" {
+		command, err := expandCommand(config, folder, project.Command)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		return commandArgs(config, command)
+	}
+	ctx = withKubeContext(ctx, config, folder)
+	d := preferredDetector(folder, matchDetectors(projectDetectors(ctx, config, folder)))
+	if d == nil {
+		return nil
+	}
+	// 同时启动多个进程的启动方式无法以单个命令运行，跳过
+	for _, action := range d.Actions(ctx) {
+		if len(action.Command) > 0 {
+			return action.Command
+		}
+	}
+	return nil
+}
+
+// 检测当前目录的项目类型并启动对应服务，脚本的 command 钩子给出命令或配置了启动命令时直接使用，未识别的项目不做任何操作
+func launchServer(ctx context.Context, folder, path string, config *Config) {
+	if command, ok, err := scriptCommand(config, folder); err != nil {
+		failf(exitConfigError, "%v", err)
+		return
+	} else if ok {
+		startService(ctx, folder, path, config, "项目", launchAction{Command: command})
+		return
+	}
+	if project := findProject(config, folder); project != nil && project.Command != "" {
+		command, err := expandCommand(config, folder, project.Command)
+		if err != nil {
+			failf(exitConfigError, "%v", err)
+			return
+		}
+		startService(ctx, folder, path, config, "项目", launchAction{Command: commandArgs(config, command)})
+		return
+	}
+
+	ctx = withKubeContext(ctx, config, folder)
+	stopDiscovery := timePhase("discovery")
+	matched := matchDetectors(projectDetectors(ctx, config, folder))
+	stopDiscovery()
+	if len(matched) == 0 {
+		return
+	}
+	d, err := chooseDetector(ctx, folder, matched)
+	if err != nil {
+		setExitCode(exitCanceled)
+		return
+	}
+	fmt.Printf("检测到 %s 为 %s 项目\n", folder, d.Name)
+	stopDiscovery = timePhase("discovery")
+	actions := d.Actions(ctx)
+	stopDiscovery()
+	if len(actions) == 0 {
+		failf(exitLaunchFailed, "未找到可用的 %s 启动命令", d.Service)
+		return
+	}
+	action, err := chooseAction(ctx, folder, actions)
+	if err != nil {
+		setExitCode(exitCanceled)
+		return
+	}
+
+	// 缺少依赖时询问是否先安装
+	if d.Install != nil {
+		// 依赖检查和安装计入耗时，询问是否安装的等待时间不计入
+		stop := timePhase("install")
+		step := d.Install()
+		stop()
+		if step != nil && offline() {
+			fmt.Printf("%s，离线模式，已跳过 %s\n", step.Reason, strings.Join(step.Command, " "))
+		} else if step != nil && confirmAlways(ctx, folder, alwaysInstall, fmt.Sprintf("%s，是否先执行 %s？(Y/n/a 总是): ", step.Reason, strings.Join(step.Command, " "))) {
+			stop = timePhase("install")
+			err := runStep(ctx, stepTimeout(config, "install"), projectEnv(ctx, config, folder), wrapCommand(step.Command)...)
+			stop()
+			if err == errStepCanceled {
+				fmt.Println("依赖安装已取消")
+				setExitCode(exitCanceled)
+				return
+			}
+			if err != nil {
+				fmt.Println("依赖安装失败:", err)
+			} else if step.Done != nil {
+				step.Done()
+			}
+		}
+	}
+
+	startService(ctx, folder, path, config, d.Service, action)
+}
+
+// 倒计时后在当前目录启动服务，按项目配置开启自动重启、文件监听和就绪检查
+func startService(ctx context.Context, folder, path string, config *Config, name string, action launchAction) {
+	if len(action.Group) > 0 {
+		startProcessGroup(ctx, folder, path, config, name, action.Group)
+		return
+	}
+	if len(action.Command) == 0 {
+		return
+	}
+	svc := newService(ctx, config, folder, wrapCommand(batchCommand(action.Command)))
+	svc.Path = path
+	svc.Process = action.Process
+	svc.LaunchEnv = action.Env
+	svc.Env = append(svc.Env, action.Env...)
+	svc.Timing = currentLaunch
+	fmt.Printf("5秒后启动 %s 服务，Ctrl+C 停止\n", name)
+	if !waitLaunch(ctx) {
+		return
+	}
+	if err := svc.run(ctx); err != nil {
+		failf(exitLaunchFailed, "无法启动 %s 服务: %v", name, err)
+	}
+}
+
+// 有多个启动方式时列出菜单供用户选择并记住选择，再次启动时直接回车即使用上次的启动方式。输入结束时返回 io.EOF
+func chooseAction(ctx context.Context, folder string, actions []launchAction) (launchAction, error) {
+	if len(actions) == 1 {
+		return actions[0], nil
+	}
+	names := make([]string, len(actions))
+	for i, action := range actions {
+		names[i] = action.Name
+	}
+	fmt.Println("启动方式：")
+	choice, err := chooseRemembered(ctx, "请输入启动方式编号", names, projectPreferences(folder).Action)
+	if err != nil {
+		return launchAction{}, err
+	}
+	updatePreferences(folder, func(p *projectPreference) { p.Action = names[choice-1] })
+	return actions[choice-1], nil
+}
+
+// 筛选出匹配当前目录的项目类型，保持检测顺序
+func matchDetectors(list []detector) []detector {
+	var matched []detector
+	for _, d := range list {
+		if d.Match() {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+// 在匹配的项目类型中取该项目记住的类型，没有记录或该类型已不再匹配时取第一个，没有匹配时返回 nil
+func preferredDetector(folder string, matched []detector) *detector {
+	if len(matched) == 0 {
+		return nil
+	}
+	remembered := projectPreferences(folder).Detector
+	for i := range matched {
+		if matched[i].Name == remembered {
+			return &matched[i]
+		}
+	}
+	return &matched[0]
+}
+
+// 同时匹配多种项目类型时列出菜单供用户选择并记住选择，之后直接使用记住的类型。输入结束时返回 io.EOF
+func chooseDetector(ctx context.Context, folder string, matched []detector) (*detector, error) {
+	if len(matched) == 1 {
+		return &matched[0], nil
+	}
+	names := make([]string, len(matched))
+	for i, d := range matched {
+		names[i] = d.Name
+	}
+	remembered := projectPreferences(folder).Detector
+	for i := range matched {
+		if matched[i].Name == remembered {
+			fmt.Printf("%s 同时为 %s 项目，按上次的选择启动，可在操作菜单中清除记住的选择\n", folder, strings.Join(names, "、"))
+			return &matched[i], nil
+		}
+	}
+
+	fmt.Printf("%s 同时为以下类型的项目：\n", folder)
+	choice, err := chooseRemembered(ctx, "请输入项目类型编号（之后会记住该选择）", names, "")
+	if err != nil {
+		return nil, err
+	}
+	updatePreferences(folder, func(p *projectPreference) { p.Detector = names[choice-1] })
+	return &matched[choice-1], nil
+}
+
+// 询问用户是否继续，直接回车视为同意
+func confirm(ctx context.Context, prompt string) bool {
+	answer, err := readLine(ctx, prompt)
+	answer = strings.ToLower(answer)
+	return err == nil && (answer == "" || answer == "y" || answer == "yes")
+}
+
+// 判断文件或目录是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// 读取 JSON 文件并解析到 v
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// packageJSON 为 package.json 中用到的字段
+type packageJSON struct {
+	Name         string            `json:"name"`
+	Scripts      map[string]string `json:"scripts"`
+	Dependencies map[string]string `json:"dependencies"`
+	Workspaces   json.RawMessage   `json:"workspaces"`
+}
+
+// 按优先级选出用于启动开发服务的脚本名称
+func devScript(scripts map[string]string) string {
+	for _, name := range []string{"dev", "serve", "start"} {
+		if strings.TrimSpace(scripts[name]) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// 按空白拆分命令字符串，支持单引号、双引号，非 Windows 系统下支持反斜杠转义
+func splitCommand(command string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+		escaped bool
+	)
+	for _, r := range command {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'' && runtime.GOOS != "windows":
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// 环境检查中检测的开发工具及其版本参数
+var doctorTools = []struct {
+	Name string
+	Args []string
+	Hint string
+}{
+	{"git", []string{"--version"}, "https://git-scm.com/downloads"},
+	{"node", []string{"--version"}, "https://nodejs.org/"},
+	{"npm", []string{"--version"}, "随 Node.js 一起安装"},
+	{"pnpm", []string{"--version"}, "npm install -g pnpm"},
+	{"yarn", []string{"--version"}, "npm install -g yarn"},
+	{"php", []string{"--version"}, "https://www.php.net/downloads"},
+	{"composer", []string{"--version"}, "https://getcomposer.org/download/"},
+	{"go", []string{"version"}, "https://go.dev/dl/"},
+	{"cargo", []string{"--version"}, "https://rustup.rs/"},
+	{"java", []string{"-version"}, "https://adoptium.net/"},
+	{"dotnet", []string{"--version"}, "https://dotnet.microsoft.com/download"},
+}
+
+// 检查运行环境并给出修复建议，存在错误时返回 error
+func runDoctor(ctx context.Context) error {
+	ver, _, _ := buildInfo()
+	fmt.Printf("quickstart %s\n\n", ver)
+	problems := 0
+	ok := func(format string, args ...any) { fmt.Printf("✔ "+format+"\n", args...) }
+	warn := func(format string, args ...any) { fmt.Printf("! "+format+"\n", args...) }
+	fail := func(format string, args ...any) {
+		problems++
+		fmt.Printf("✘ "+format+"\n", args...)
+	}
+
+	// 配置文件
+	config, err := loadConfig()
+	if err != nil {
+		fail("配置文件 %s 无效: %v\n  请检查 JSON 格式（不支持注释），或删除后重新运行自动生成", configPath, err)
+		config = &Config{}
+	} else {
+		ok("配置文件 %s", configPath)
+		if config.ActiveProfile != "" {
+			ok("配置档 %s", config.ActiveProfile)
+		}
+	}
+
+	// 脚本
+	scripts := []string{config.Script}
+	for _, project := range config.Projects {
+		scripts = append(scripts, project.Script)
+	}
+	for _, script := range scripts {
+		if script == "" {
+			continue
+		}
+		if _, err := loadScript(script); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	// 项目类型检测，插件识别的类型无法预先确定，有插件时不检查
+	if len(listPlugins()) == 0 {
+		fields, detects := []string{"detect"}, []*DetectConfig{config.Detect}
+		for _, project := range config.Projects {
+			fields = append(fields, "projects["+project.Name+"].detect")
+			detects = append(detects, project.Detect)
+		}
+		for i, detect := range detects {
+			if detect == nil {
+				continue
+			}
+			for _, name := range append(append([]string{}, detect.Order...), detect.Disable...) {
+				if !isDetectorName(name) {
+					warn("%s 中的 %s 不是内置的项目类型", fields[i], name)
+				}
+			}
+		}
+	}
+
+	// 工作目录
+	if config.ProjectDir != "" {
+		if entries, err := os.ReadDir(config.ProjectDir); err != nil {
+			fail("工作目录 %s 无法访问: %v\n  请在配置文件中修改 projectDir", config.ProjectDir, err)
+		} else {
+			ok("工作目录 %s（%d 项）", config.ProjectDir, len(entries))
+			for _, project := range config.Projects {
+				if project.Remote == "" && project.WSL == "" && !fileExists(config.ProjectDir+string(os.PathSeparator)+project.Name) {
+					warn("projects 中的 %s 在工作目录中不存在", project.Name)
+				}
+			}
+		}
+	}
+
+	// 编辑器
+	editor := config.Editor
+	if editor == "" {
+		editor = "code"
+	}
+	if command, found := editorCommand(editor); found {
+		ok("编辑器 %s（%s）", editor, command)
+	} else {
+		var installed []string
+		for _, candidate := range append(config.Editors, knownEditors...) {
+			if _, found := editorCommand(candidate); found && !contains(candidate, installed) {
+				installed = append(installed, candidate)
+			}
+		}
+		if len(installed) > 0 {
+			warn("编辑器 %s 不在 PATH 中，将使用 %s\n  VS Code 可在命令面板中执行 \"Shell Command: Install 'code' command in PATH\"", editor, installed[0])
+		} else {
+			fail("未找到任何编辑器\n  请安装 VS Code 并将 code 加入 PATH，或在配置文件中修改 editor")
+		}
+	}
+
+	// 开发工具
+	fmt.Println()
+	for _, tool := range doctorTools {
+		if _, err := exec.LookPath(tool.Name); err != nil {
+			warn("%-8s 未安装  %s", tool.Name, tool.Hint)
+			continue
+		}
+		ok("%-8s %s", tool.Name, toolVersion(ctx, tool.Name, tool.Args...))
+	}
+
+	// 插件
+	if plugins := listPlugins(); len(plugins) > 0 {
+		fmt.Println()
+		for _, plugin := range plugins {
+			ok("插件 %s（%s）", pluginName(plugin), plugin)
+		}
+	}
+
+	fmt.Println()
+	if problems > 0 {
+		return fmt.Errorf("发现 %d 个问题", problems)
+	}
+	fmt.Println("环境检查通过")
+	return nil
+}
+
+// 获取工具版本输出的第一行，超时或失败时返回空字符串
+func toolVersion(ctx context.Context, name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text())
+	}
+	return ""
+}
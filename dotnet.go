@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// 查找当前目录下的 .csproj 文件
+func csprojFiles() []string {
+	files, _ := filepath.Glob("*.csproj")
+	return files
+}
+
+// .NET 项目使用 dotnet watch run 启动，存在多个项目文件时逐个列出
+func dotnetActions(_ context.Context) []launchAction {
+	files := csprojFiles()
+	if len(files) == 1 {
+		return []launchAction{
+			{Name: "dotnet watch run", Command: []string{"dotnet", "watch", "run"}},
+			{Name: "dotnet run", Command: []string{"dotnet", "run"}},
+		}
+	}
+	var actions []launchAction
+	for _, file := range files {
+		actions = append(actions, launchAction{
+			Name:    "dotnet watch run --project " + file,
+			Command: []string{"dotnet", "watch", "run", "--project", file},
+		})
+	}
+	return actions
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-quickStart/ipc"
+)
+
+// 依赖服务停止命令的超时，docker compose down 等命令可能需要较长时间
+const downStepTimeout = 2 * time.Minute
+
+// 执行 down 子命令：停止所有启动器实例和守护进程运行的服务，再停止启动器启动的依赖服务（容器、
+// docker compose、tmux 会话等），均按启动的相反顺序停止，即先停止依赖其他服务的项目，最后打印汇总
+func runDown(ctx context.Context) error {
+	config, err := loadConfig()
+	if err != nil {
+		config = &Config{}
+	}
+	records := runningServices()
+	deps := startedDependencies()
+	if len(records) == 0 && len(deps) == 0 {
+		fmt.Println("没有需要停止的服务。")
+		return nil
+	}
+
+	stopped, failed := 0, 0
+	result := func(ok bool, format string, args ...any) {
+		if ok {
+			stopped++
+			fmt.Printf("✔ "+format+"\n", args...)
+		} else {
+			failed++
+			fmt.Printf("✘ "+format+"\n", args...)
+		}
+	}
+
+	if len(records) > 0 {
+		fmt.Println("服务：")
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Started.After(records[j].Started) })
+		client, _ := ipc.Dial()
+		if client != nil {
+			defer client.Close()
+		}
+		for _, r := range records {
+			if r.LauncherPID == os.Getpid() {
+				continue
+			}
+			if err := stopRecord(client, r); err != nil {
+				result(false, "%s（pid %d）: %v", r.Project, r.PID, err)
+			} else {
+				result(true, "%s（pid %d）", r.Project, r.PID)
+			}
+		}
+	}
+
+	if len(deps) > 0 {
+		fmt.Println("依赖服务：")
+		var remaining []startedDependency
+		for i := len(deps) - 1; i >= 0; i-- {
+			d := deps[i]
+			how, err := stopDependency(ctx, config, d)
+			if err != nil {
+				result(false, "%s（%s）: %v", d.Name, d.Project, err)
+				remaining = append([]startedDependency{d}, remaining...)
+				continue
+			}
+			result(true, "%s（%s，%s）", d.Name, d.Project, how)
+		}
+		if err := writeStartedDependencies(remaining); err != nil {
+			fmt.Printf("无法更新依赖服务记录: %v\n", err)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		setExitCode(exitLaunchFailed)
+		return fmt.Errorf("已停止 %d 项，%d 项未能停止", stopped, failed)
+	}
+	fmt.Printf("已停止 %d 项\n", stopped)
+	return nil
+}
+
+// 停止运行记录对应的服务：守护进程运行的服务通过守护进程停止，其他启动器实例连同其服务一起结束，
+// 避免启动器在服务退出后自动重启
+func stopRecord(client *ipc.Client, r serviceRecord) error {
+	if !processAlive(r.PID) {
+		return nil
+	}
+	if client != nil && client.Stop(r.Project) == nil && !processAlive(r.PID) {
+		return nil
+	}
+	stopProcessTree(r.LauncherPID)
+	if !stopProcessTree(r.PID) {
+		return fmt.Errorf("进程仍在运行")
+	}
+	return nil
+}
+
+// 停止启动器启动的依赖服务，返回停止的方式
+func stopDependency(ctx context.Context, config *Config, d startedDependency) (string, error) {
+	switch {
+	case d.Container != "":
+		if running, err := queryOutput(ctx, "docker", "inspect", "-f", "{{.State.Running}}", d.Container); err == nil && strings.TrimSpace(string(running)) == "false" {
+			return "容器已停止", nil
+		}
+		if _, err := queryOutput(ctx, "docker", "stop", d.Container); err != nil {
+			return "", fmt.Errorf("无法停止容器 %s: %v", d.Container, err)
+		}
+		return "docker stop " + d.Container, nil
+	case d.Stop != "":
+		if d.Dir != "" {
+			if err := os.Chdir(d.Dir); err != nil {
+				return "", fmt.Errorf("无法进入目录 %s: %v", d.Dir, err)
+			}
+		}
+		if err := runStep(ctx, downStepTimeout, projectEnv(ctx, config, d.Project), wrapCommand(batchCommand(commandArgs(config, d.Stop)))...); err != nil {
+			return "", fmt.Errorf("%s 执行失败: %v", d.Stop, err)
+		}
+		return d.Stop, nil
+	case !processAlive(d.PID):
+		return "已退出", nil
+	case !stopProcessTree(d.PID):
+		return "", fmt.Errorf("进程 %d 仍在运行", d.PID)
+	}
+	return fmt.Sprintf("pid %d", d.PID), nil
+}
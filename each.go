@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 并行执行时同时运行的最大项目数
+const eachParallelLimit = 8
+
+// eachResult 为在一个项目中执行命令的结果
+type eachResult struct {
+	Project string
+	Err     error
+	Skipped string // 跳过的原因，为空表示已执行
+	Elapsed time.Duration
+}
+
+// 执行 each 子命令：quickstart each [--tag 标签]... [--parallel] [项目...] -- <命令>，
+// 在选中的每个项目目录中执行命令，未指定项目和标签时为所有项目，结束后汇总结果
+func runEachCommand(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("用法: quickstart each [--tag 标签]... [--parallel] [项目...] -- <命令>")
+	var (
+		tags     []string
+		names    []string
+		parallel bool
+		command  []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--":
+			command = args[i+1:]
+			i = len(args)
+		case arg == "--tag" && i+1 < len(args):
+			i++
+			tags = append(tags, args[i])
+		case strings.HasPrefix(arg, "--tag="):
+			tags = append(tags, strings.TrimPrefix(arg, "--tag="))
+		case arg == "--parallel" || arg == "-p":
+			parallel = true
+		case strings.HasPrefix(arg, "-"):
+			return usage
+		default:
+			names = append(names, arg)
+		}
+	}
+	if len(command) == 0 {
+		return usage
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	discovered, err := discoverProjects(config)
+	if err != nil {
+		return err
+	}
+	var projects []string
+	for _, p := range discovered {
+		if len(names) == 0 && len(tags) == 0 || p.hasTag(tags) {
+			projects = append(projects, p.Name)
+		}
+	}
+	for _, name := range names {
+		name = resolveAlias(config, name)
+		if findDiscovered(discovered, name) == nil {
+			setExitCode(exitNotFound)
+			return fmt.Errorf("项目 %s 不存在", name)
+		}
+		if !contains(name, projects) {
+			projects = append(projects, name)
+		}
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("没有带有标签 %s 的项目", strings.Join(tags, "、"))
+	}
+	return runEach(ctx, config, projects, command, parallel)
+}
+
+// 在每个项目目录中依次或并行执行命令，打印汇总，有项目失败时返回 error。
+// command 只有一项时视为命令字符串，支持模板变量和 shell 语法；否则作为参数列表直接执行
+func runEach(ctx context.Context, config *Config, projects []string, command []string, parallel bool) error {
+	results := make([]eachResult, len(projects))
+	if parallel {
+		width := 0
+		for _, name := range projects {
+			width = max(width, len(name))
+		}
+		var (
+			mu    sync.Mutex
+			wg    sync.WaitGroup
+			limit = make(chan struct{}, eachParallelLimit)
+		)
+		for i, name := range projects {
+			color := prefixColors[i%len(prefixColors)]
+			out := &prefixWriter{mu: &mu, out: processStdout, prefix: "\x1b[" + color + "m" + name + strings.Repeat(" ", width-len(name)) + " |\x1b[0m "}
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+				results[i] = runInProject(ctx, config, name, command, out)
+				out.flush()
+			}(i, name)
+		}
+		wg.Wait()
+	} else {
+		for i, name := range projects {
+			printBanner(os.Stdout, name)
+			results[i] = runInProject(ctx, config, name, command, processStdout)
+		}
+	}
+
+	fmt.Println()
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			fmt.Printf("- %s 已跳过：%s\n", r.Project, r.Skipped)
+		case r.Err != nil:
+			failed++
+			fmt.Printf("✘ %s（%v）: %v\n", r.Project, r.Elapsed.Round(100*time.Millisecond), r.Err)
+		default:
+			fmt.Printf("✔ %s（%v）\n", r.Project, r.Elapsed.Round(100*time.Millisecond))
+		}
+	}
+	if failed > 0 {
+		setExitCode(exitLaunchFailed)
+		return fmt.Errorf("%d/%d 个项目执行失败", failed, len(projects))
+	}
+	return nil
+}
+
+// 在项目目录中执行命令，输出写入 out，ctx 被取消时结束命令。远程项目和 WSL 项目不在本机目录中，跳过
+func runInProject(ctx context.Context, config *Config, name string, command []string, out io.Writer) eachResult {
+	result := eachResult{Project: name}
+	if project := findProject(config, name); project != nil && (project.Remote != "" || project.WSL != "") {
+		result.Skipped = "远程或 WSL 项目"
+		return result
+	}
+	args := command
+	if len(command) == 1 {
+		line, err := expandCommand(config, name, command[0])
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		args = commandArgs(config, line)
+	}
+	args = batchCommand(args)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setBatchCmdLine(cmd)
+	cmd.Dir = filepath.Join(config.ProjectDir, name)
+	cmd.Env = projectEnv(ctx, config, name)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	start := time.Now()
+	result.Err = cmd.Run()
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// 在菜单中选择多个项目并输入要执行的命令。项目可输入编号（1 3 5-7）、名称、别名或 #标签，逗号或空格分隔
+func eachFromMenu(ctx context.Context, config *Config, items []*Project) error {
+	var projects []string
+	for len(projects) == 0 {
+		input, err := readLine(ctx, "请输入项目编号（如 1,3,5-7，#标签 选择带有该标签的项目，all 为全部）: ")
+		if err == io.EOF || input == quitInput {
+			return nil
+		}
+		if projects, err = selectFolders(config, items, input); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Printf("已选择：%s\n", strings.Join(projects, "、"))
+	line, err := readLine(ctx, "请输入要在这些项目中执行的命令: ")
+	if err == io.EOF || line == "" {
+		return nil
+	}
+	parallel := false
+	if answer, _ := readLine(ctx, "是否并行执行？(y/N): "); strings.ToLower(answer) == "y" || strings.ToLower(answer) == "yes" {
+		parallel = true
+	}
+	err = runEach(ctx, config, projects, []string{line}, parallel)
+	readLine(ctx, "按回车返回菜单")
+	return err
+}
+
+// 解析菜单中输入的项目选择，子级目录不参与
+func selectFolders(config *Config, items []*Project, input string) ([]string, error) {
+	var projects []string
+	add := func(p *Project) {
+		if !p.SubDir && !contains(p.Name, projects) {
+			projects = append(projects, p.Name)
+		}
+	}
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' || r == '，' }) {
+		switch {
+		case field == "all":
+			for _, p := range items {
+				add(p)
+			}
+		case strings.HasPrefix(field, "#"):
+			for _, p := range items {
+				if p.hasTag([]string{field[1:]}) {
+					add(p)
+				}
+			}
+		case findDiscovered(items, resolveAlias(config, field)) != nil:
+			add(findDiscovered(items, resolveAlias(config, field)))
+		default:
+			from, to, isRange := strings.Cut(field, "-")
+			first, err1 := strconv.Atoi(from)
+			last, err2 := first, error(nil)
+			if isRange {
+				last, err2 = strconv.Atoi(to)
+			}
+			if err1 != nil || err2 != nil || first < 1 || last > len(items) || first > last {
+				return nil, fmt.Errorf("无效的选择 %s，请输入 1 到 %d 之间的编号", field, len(items))
+			}
+			for i := first; i <= last; i++ {
+				add(items[i-1])
+			}
+		}
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("没有选中任何项目")
+	}
+	return projects, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// 常见编辑器命令，在配置的编辑器都不可用时按此顺序检测
+var knownEditors = []string{"code", "code-insiders", "cursor", "subl", "jetbrains", "nvim"}
+
+// 本次运行中用户选择的编辑器，避免重复询问
+var chosenEditor string
+
+// 确定要使用的编辑器命令。依次尝试项目或全局配置的编辑器及其后备列表，
+// 均不可用时检测已安装的常见编辑器，有多个时询问用户
+func resolveEditor(ctx context.Context, config *Config, project *ProjectConfig) (string, error) {
+	editor := config.Editor
+	if project != nil && project.Editor != "" {
+		editor = project.Editor
+	}
+	if editor == "" {
+		editor = "code"
+	}
+	candidates := append([]string{editor}, config.Editors...)
+	for _, candidate := range candidates {
+		if command, ok := editorCommand(candidate); ok {
+			if candidate != editor {
+				fmt.Printf("未找到编辑器 %s，使用 %s\n", editor, candidate)
+			}
+			return command, nil
+		}
+	}
+
+	if chosenEditor != "" {
+		return editorCommandOrSelf(chosenEditor), nil
+	}
+	var installed []string
+	for _, candidate := range knownEditors {
+		if _, ok := editorCommand(candidate); ok && !contains(candidate, candidates) {
+			installed = append(installed, candidate)
+		}
+	}
+	switch len(installed) {
+	case 0:
+		return "", fmt.Errorf("未找到编辑器 %s，请安装后将其加入 PATH，或在配置文件中修改 editor", editor)
+	case 1:
+		fmt.Printf("未找到编辑器 %s，使用已安装的 %s\n", editor, installed[0])
+		chosenEditor = installed[0]
+	default:
+		fmt.Printf("未找到编辑器 %s，检测到以下已安装的编辑器：\n", editor)
+		for i, candidate := range installed {
+			fmt.Printf("%d. %s\n", i+1, candidate)
+		}
+		for {
+			choice, err := getUserChoice(ctx, "请输入要使用的编辑器编号: ", len(installed))
+			if err == io.EOF {
+				return "", fmt.Errorf("未选择编辑器")
+			}
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			chosenEditor = installed[choice-1]
+			break
+		}
+	}
+	return editorCommandOrSelf(chosenEditor), nil
+}
+
+// 将编辑器名称解析为可执行的命令，jetbrains 表示根据项目类型选择 JetBrains IDE
+func editorCommand(editor string) (string, bool) {
+	if editor == "jetbrains" {
+		command := jetbrainsIDE()
+		return command, command != ""
+	}
+	if _, err := exec.LookPath(editor); err != nil {
+		return "", false
+	}
+	return editor, true
+}
+
+// 解析编辑器命令，解析失败时原样返回
+func editorCommandOrSelf(editor string) string {
+	if command, ok := editorCommand(editor); ok {
+		return command
+	}
+	return editor
+}
+
+// 判断编辑器是否为 VS Code 系列，它们支持相同的命令行参数
+func isVSCodeLike(editor string) bool {
+	switch strings.TrimSuffix(filepath.Base(editor), filepath.Ext(editor)) {
+	case "code", "code-insiders", "cursor", "codium":
+		return true
+	}
+	return false
+}
+
+// 生成打开项目的编辑器参数。配置了工作区文件时打开工作区，
+// 配置了入口文件时同时打开目录和文件
+func editorArgs(editor, editorDir string, project *ProjectConfig) []string {
+	var args []string
+	if project != nil && project.ReuseWindow && isVSCodeLike(editor) {
+		args = append(args, "-r")
+	}
+	switch {
+	case project == nil || project.Open == "":
+		args = append(args, editorDir)
+	case strings.HasSuffix(project.Open, ".code-workspace"):
+		args = append(args, project.Open)
+	default:
+		args = append(args, editorDir, project.Open)
+	}
+	return args
+}
+
+// 打开项目的编辑器。本地项目以当前目录为项目根目录，远程项目和 WSL 项目通过 VS Code 远程扩展打开
+func openEditor(ctx context.Context, config *Config, project *ProjectConfig) error {
+	editor, err := resolveEditor(ctx, config, project)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	switch {
+	case project != nil && project.Remote != "":
+		host, path, err := parseRemote(project.Remote)
+		if err != nil {
+			return err
+		}
+		if !isVSCodeLike(editor) {
+			fmt.Printf("编辑器 %s 不支持打开远程项目，已跳过\n", editor)
+			return nil
+		}
+		args = []string{"--remote", "ssh-remote+" + host, path}
+	case project != nil && project.WSL != "":
+		distro, path, err := parseWSLProject(project.WSL)
+		if err != nil {
+			return err
+		}
+		if !isVSCodeLike(editor) {
+			fmt.Printf("编辑器 %s 不支持打开 WSL 项目，已跳过\n", editor)
+			return nil
+		}
+		args = []string{"--remote", "wsl+" + distro, path}
+	default:
+		editorDir := "."
+		if project != nil && project.EditorDir != "" {
+			editorDir = project.EditorDir
+		}
+		args = editorArgs(editor, editorDir, project)
+		// 项目位于 WSL 中时通过 Remote-WSL 打开，避免编辑器经由网络路径访问文件
+		if distro, linuxDir, ok := wslLocation(editorDir); ok && isVSCodeLike(editor) {
+			args = []string{"--remote", "wsl+" + distro, linuxDir}
+		}
+	}
+
+	cmd := exec.Command(editor, args...)
+	cmd.Stdout = processStdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// 打开项目的编辑器，失败时提示并询问是否继续后续的启动步骤，返回是否继续
+func openEditorOrContinue(ctx context.Context, config *Config, folder string, project *ProjectConfig) bool {
+	err := openEditor(ctx, config, project)
+	if err == nil {
+		return true
+	}
+	fmt.Println("无法打开编辑器:", err)
+	return confirmAlways(ctx, folder, alwaysSkipEditor, "是否跳过编辑器继续启动？(Y/n/a 总是): ")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 服务事件
+const (
+	eventLaunch = "launch" // 服务进程启动
+	eventReady  = "ready"  // 服务就绪，没有就绪检查时为启动
+	eventCrash  = "crash"  // 服务异常退出
+	eventStop   = "stop"   // 服务结束，不再重启
+)
+
+// 单个事件钩子的最长执行时间，超时后放弃，不影响服务
+const hookTimeout = 10 * time.Second
+
+// 正在执行的事件钩子，启动器退出前等待执行完
+var pendingHooks sync.WaitGroup
+
+// eventHook 为解析后的事件钩子，URL 与 Command 任选其一
+type eventHook struct {
+	Event   string
+	URL     string
+	Command []string
+}
+
+// serviceEvent 为事件钩子收到的事件信息，URL 钩子作为 POST 的 JSON 请求体，命令钩子从标准输入读取
+type serviceEvent struct {
+	Event   string    `json:"event"`
+	Project string    `json:"project"`
+	Process string    `json:"process,omitempty"`
+	Path    string    `json:"path"`
+	Time    time.Time `json:"time"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// 获取项目的事件钩子：先执行全局配置的钩子，再执行项目配置的钩子。命令中的模板变量在此展开
+func projectHooks(config *Config, name string) []eventHook {
+	var hooks []eventHook
+	add := func(h *HooksConfig) {
+		if h == nil {
+			return
+		}
+		for _, group := range []struct {
+			event string
+			list  []HookConfig
+		}{
+			{eventLaunch, h.OnLaunch},
+			{eventReady, h.OnReady},
+			{eventCrash, h.OnCrash},
+			{eventStop, h.OnStop},
+		} {
+			for _, hook := range group.list {
+				resolved := eventHook{Event: group.event, URL: hook.URL}
+				if hook.Command != "" {
+					command, err := expandCommand(config, name, hook.Command)
+					if err != nil {
+						fmt.Println(err)
+						continue
+					}
+					resolved.Command = commandArgs(config, command)
+				}
+				if resolved.URL != "" || len(resolved.Command) > 0 {
+					hooks = append(hooks, resolved)
+				}
+			}
+		}
+	}
+	add(config.Hooks)
+	if project := findProject(config, name); project != nil {
+		add(project.Hooks)
+	}
+	return hooks
+}
+
+// 在后台执行服务在该事件上的钩子，失败时输出原因，不影响服务
+func (s *service) emit(event string, err error) {
+	e := serviceEvent{Event: event, Project: s.Project, Process: s.Process, Path: s.Path, Time: time.Now()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	for _, hook := range s.Hooks {
+		if hook.Event != event {
+			continue
+		}
+		pendingHooks.Add(1)
+		go func(hook eventHook) {
+			defer pendingHooks.Done()
+			if err := hook.run(e); err != nil {
+				fmt.Fprintf(s.output(), "%s 事件钩子执行失败: %v\n", event, err)
+				logf("%s 的 %s 事件钩子执行失败: %v", s.label(), event, err)
+			}
+		}(hook)
+	}
+}
+
+// 执行钩子：向 URL POST 事件的 JSON，或运行命令，事件信息同时通过 QUICKSTART_EVENT 等环境变量传入
+func (h eventHook) run(e serviceEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	if h.URL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("%s 返回 %s", h.URL, resp.Status)
+		}
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"QUICKSTART_EVENT="+e.Event,
+		"QUICKSTART_PROJECT="+e.Project,
+		"QUICKSTART_PROJECT_PATH="+e.Path,
+		"QUICKSTART_ERROR="+e.Error,
+	)
+	cmd.Stdin = bytes.NewReader(data)
+	// 放入独立的进程组，Ctrl+C 结束服务时不会同时结束 stop 事件钩子
+	setProcessGroup(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %v: %s", strings.Join(h.Command, " "), err, msg)
+		}
+		return fmt.Errorf("%s: %v", strings.Join(h.Command, " "), err)
+	}
+	return nil
+}
+
+// 等待正在执行的事件钩子，最多等待 hookTimeout
+func waitHooks() {
+	done := make(chan struct{})
+	go func() {
+		pendingHooks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(hookTimeout):
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync/atomic"
+	"syscall"
+)
+
+// 进程退出码，供脚本区分配置错误、启动失败和用户取消
+const (
+	exitConfigError   = 1   // 配置文件无法读取或无效，其他未分类的错误同样使用该退出码
+	exitNotFound      = 2   // 指定的项目不存在
+	exitLaunchFailed  = 3   // 服务、启动命令或 each 中的命令执行失败
+	exitInternalError = 70  // 程序内部错误（panic），与 sysexits.h 的 EX_SOFTWARE 一致
+	exitCanceled      = 130 // 用户取消启动，与 shell 中 Ctrl+C 结束命令的退出码一致
+)
+
+// 程序结束时使用的退出码，只保留第一次记录的结果
+var exitCode atomic.Int32
+
+// 记录程序结束时的退出码，已记录过时不再覆盖
+func setExitCode(code int) {
+	exitCode.CompareAndSwap(0, int32(code))
+}
+
+// 将错误信息输出到标准错误并记录退出码，--quiet 时同样显示
+func failf(code int, format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	setExitCode(code)
+}
+
+// 命令返回错误时的退出码，未记录具体原因时为 exitConfigError
+func errorExitCode() int {
+	if code := exitCode.Load(); code != 0 {
+		return int(code)
+	}
+	return exitConfigError
+}
+
+// 程序内部错误时将调用栈写入启动器日志，并显示简短的说明代替调用栈，返回 exitInternalError
+func reportPanic(r any) int {
+	logf("程序内部错误: %v\n%s", r, debug.Stack())
+	fmt.Fprintf(os.Stderr, "\n程序遇到内部错误，已退出: %v\n", r)
+	if stateDir != "" {
+		fmt.Fprintf(os.Stderr, "详细信息已写入 %s\n", launcherLogPath())
+	}
+	fmt.Fprintln(os.Stderr, "可执行 quickstart report 生成问题报告，附加到 issue 中反馈")
+	return exitInternalError
+}
+
+// 正在自行处理 Ctrl+C 的前台命令和服务数，大于 0 时 Ctrl+C 只结束它们，不取消启动器的根上下文
+var interruptClaims atomic.Int32
+
+// 由调用方自行处理 Ctrl+C，返回接收中断的通道和结束处理的函数
+func claimInterrupt() (<-chan os.Signal, func()) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	interruptClaims.Add(1)
+	return interrupt, func() {
+		signal.Stop(interrupt)
+		interruptClaims.Add(-1)
+	}
+}
+
+// 创建启动器的根上下文，收到 SIGTERM 或在没有前台命令、服务自行处理时按下 Ctrl+C 时取消，
+// 等待中的提示、查询命令和服务随之结束。取消后再次收到信号时立即退出
+func rootContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			if sig == os.Interrupt && interruptClaims.Load() > 0 {
+				continue
+			}
+			if ctx.Err() != nil {
+				os.Exit(exitCanceled)
+			}
+			cancel()
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
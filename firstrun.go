@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 首次启动记录文件路径，记录每个项目的 firstRun 命令全部执行成功的时间
+func firstRunPath() string {
+	return statePath("firstrun.json")
+}
+
+// 项目首次通过启动器启动时依次执行 firstRun 命令，如安装依赖、复制 .env.example、执行数据库迁移。
+// 全部成功后记录到状态中，之后不再执行；有命令失败时询问是否继续启动，下次启动时重新执行。返回是否继续启动
+func runFirstRun(ctx context.Context, config *Config, folder string) bool {
+	project := findProject(config, folder)
+	if project == nil || len(project.FirstRun) == 0 {
+		return true
+	}
+	done := make(map[string]time.Time)
+	readJSONFile(firstRunPath(), &done)
+	if _, ok := done[folder]; ok {
+		return true
+	}
+	// 初始化命令通常需要下载依赖，离线时留到下次启动
+	if skipOffline("首次启动的初始化命令，联网后下次启动时执行") {
+		return true
+	}
+
+	fmt.Printf("首次启动 %s，执行初始化命令\n", folder)
+	for i, command := range project.FirstRun {
+		command, err := expandCommand(config, folder, command)
+		if err != nil {
+			fmt.Printf("✘ %v\n", err)
+			return confirm(ctx, "初始化命令有误，是否继续启动项目？(Y/n): ")
+		}
+		fmt.Printf("[%d/%d] %s\n", i+1, len(project.FirstRun), command)
+		err = runStep(ctx, stepTimeout(config, "install"), projectEnv(ctx, config, folder), wrapCommand(commandArgs(config, command))...)
+		if err == errStepCanceled {
+			fmt.Println("已取消")
+			return false
+		}
+		if err != nil {
+			fmt.Printf("✘ %s 执行失败: %v\n", command, err)
+			return confirm(ctx, "初始化未完成，下次启动时会重新执行，是否继续启动项目？(Y/n): ")
+		}
+	}
+
+	done[folder] = time.Now()
+	if data, err := json.MarshalIndent(done, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(firstRunPath()), 0755); err == nil {
+			os.WriteFile(firstRunPath(), data, 0644)
+		}
+	}
+	fmt.Println("✔ 初始化完成")
+	return true
+}
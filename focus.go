@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 专注的默认分钟数
+const defaultFocusMinutes = 25
+
+// quickstart focus log 显示的最近记录数
+const focusLogLimit = 20
+
+// focusSession 为一次专注，进行中的专注保存在 focus.json 中，结束后追加到 focus.jsonl
+type focusSession struct {
+	Project   string    `json:"project"`
+	Started   time.Time `json:"started"`
+	Ends      time.Time `json:"ends"`
+	Completed bool      `json:"completed"` // 是否到时结束，提前结束或被其他项目打断时为 false
+	Ended     time.Time `json:"ended"`     // 实际结束的时间
+}
+
+// 进行中的专注文件路径
+func focusPath() string {
+	return statePath("focus.json")
+}
+
+// 专注记录文件路径，每行一条 JSON 记录，只追加不改写
+func focusLogPath() string {
+	return statePath("focus.jsonl")
+}
+
+// 获取专注的分钟数
+func focusMinutes(config *Config) int {
+	if config.Focus != nil && config.Focus.Minutes > 0 {
+		return config.Focus.Minutes
+	}
+	return defaultFocusMinutes
+}
+
+// 读取进行中的专注，已到时的专注记录为完成后返回 nil
+func activeFocus() *focusSession {
+	var session focusSession
+	if readJSONFile(focusPath(), &session) != nil || session.Project == "" {
+		return nil
+	}
+	if !time.Now().Before(session.Ends) {
+		finishFocus(session, true)
+		return nil
+	}
+	return &session
+}
+
+// 结束专注并记录，completed 为是否到时结束。专注已被其他进程结束或替换时不重复记录
+func finishFocus(session focusSession, completed bool) {
+	var current focusSession
+	if readJSONFile(focusPath(), &current) != nil || !current.Started.Equal(session.Started) {
+		return
+	}
+	os.Remove(focusPath())
+	session.Completed = completed
+	session.Ended = time.Now()
+	if completed {
+		session.Ended = session.Ends
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(focusLogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// 为项目开始专注，到时后发送桌面通知。启动器在专注结束前退出时，下次运行时再记录为完成
+func startFocus(config *Config, project string) {
+	now := time.Now()
+	session := focusSession{Project: project, Started: now, Ends: now.Add(time.Duration(focusMinutes(config)) * time.Minute)}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(focusPath()), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(focusPath(), data, 0644); err != nil {
+		return
+	}
+	fmt.Printf("开始专注 %s，%d 分钟后（%s）结束\n", project, focusMinutes(config), session.Ends.Format("15:04"))
+	time.AfterFunc(time.Until(session.Ends), func() {
+		// 专注已提前结束或被其他项目打断时不再通知
+		var current focusSession
+		if readJSONFile(focusPath(), &current) != nil || !current.Started.Equal(session.Started) {
+			return
+		}
+		finishFocus(session, true)
+		notify("专注结束", fmt.Sprintf("%s 的 %d 分钟专注已完成，休息一下吧", project, focusMinutes(config)))
+	})
+}
+
+// 开启专注模式时，启动项目前检查进行中的专注：专注于其他项目时需确认才启动，确认后当前专注记为中断；
+// 没有进行中的专注时为该项目开始专注。返回是否继续启动
+func checkFocus(ctx context.Context, config *Config, project string) bool {
+	if config.Focus == nil {
+		return true
+	}
+	session := activeFocus()
+	if session != nil && session.Project == project {
+		return true
+	}
+	if session != nil {
+		fmt.Printf("正在专注 %s，还剩 %s\n", session.Project, formatDuration(time.Until(session.Ends)))
+		answer, err := readLine(ctx, fmt.Sprintf("启动 %s 将中断本次专注，是否仍然启动？(y/N): ", project))
+		if answer = strings.ToLower(answer); err != nil || answer != "y" && answer != "yes" {
+			fmt.Println("已取消，专注结束后再启动吧")
+			return false
+		}
+		finishFocus(*session, false)
+	}
+	startFocus(config, project)
+	return true
+}
+
+// 专注的状态，用于菜单顶部，没有进行中的专注时返回空字符串
+func focusStatus() string {
+	session := activeFocus()
+	if session == nil {
+		return ""
+	}
+	return fmt.Sprintf("专注 %s 还剩 %d 分钟", session.Project, int(time.Until(session.Ends).Minutes())+1)
+}
+
+// 读取所有专注记录，忽略无法解析的行
+func readFocusLog() []focusSession {
+	f, err := os.Open(focusLogPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var sessions []focusSession
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var session focusSession
+		if json.Unmarshal(scanner.Bytes(), &session) == nil && session.Project != "" {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// 执行 focus 子命令：显示进行中的专注和今天的专注次数，stop 提前结束专注，log 列出最近的专注记录
+func runFocusCommand(args []string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	session := activeFocus()
+	switch {
+	case len(args) == 0:
+		if config.Focus == nil {
+			fmt.Println(`未开启专注模式，可在配置文件中设置 "focus": {"minutes": 25}`)
+		}
+		if session != nil {
+			fmt.Printf("正在专注 %s，已进行 %s，还剩 %s\n", session.Project, formatDuration(time.Since(session.Started)), formatDuration(time.Until(session.Ends)))
+		} else {
+			fmt.Println("当前没有进行中的专注")
+		}
+		today := periodStart(time.Now(), false)
+		count, total := 0, time.Duration(0)
+		for _, s := range readFocusLog() {
+			if s.Completed && !s.Started.Before(today) {
+				count++
+				total += s.Ends.Sub(s.Started)
+			}
+		}
+		if count == 0 {
+			fmt.Println("今天还没有完成的专注")
+		} else {
+			fmt.Printf("今天已完成 %d 次专注，共 %s\n", count, formatDuration(total))
+		}
+		return nil
+	case len(args) == 1 && args[0] == "stop":
+		if session == nil {
+			fmt.Println("当前没有进行中的专注")
+			return nil
+		}
+		finishFocus(*session, false)
+		fmt.Printf("已结束 %s 的专注，专注了 %s\n", session.Project, formatDuration(time.Since(session.Started)))
+		return nil
+	case len(args) == 1 && args[0] == "log":
+		sessions := readFocusLog()
+		if len(sessions) == 0 {
+			fmt.Println("还没有专注记录。")
+			return nil
+		}
+		if len(sessions) > focusLogLimit {
+			sessions = sessions[len(sessions)-focusLogLimit:]
+		}
+		for _, s := range sessions {
+			result := "✔ 完成"
+			if !s.Completed {
+				result = "✘ 中断"
+			}
+			fmt.Printf("%s  %s  %s  %s\n", s.Started.Local().Format("2006-01-02 15:04"), result, formatDuration(s.Ended.Sub(s.Started)), s.Project)
+		}
+		return nil
+	default:
+		return fmt.Errorf("用法: quickstart focus [stop|log]")
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// forgeRepo 为 git 远程仓库所在的代码托管平台及仓库路径
+type forgeRepo struct {
+	Kind string // github、gitlab，无法识别的平台为空
+	Host string // 平台的主机名
+	Path string // 仓库路径，如 owner/repo，GitLab 可包含子组
+}
+
+// forgePullRequest 为代码托管平台上的 PR（GitLab 为 MR）
+type forgePullRequest struct {
+	Number int
+	Title  string
+	Branch string // 源分支
+	URL    string
+}
+
+// 解析 git 远程地址，支持 https://host/owner/repo.git、git@host:owner/repo.git 和 ssh://git@host/owner/repo.git
+func parseForgeRemote(remote string) (forgeRepo, bool) {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(at, "/") {
+		host, path, ok = strings.Cut(rest, ":")
+		if !ok {
+			return forgeRepo{}, false
+		}
+	} else {
+		return forgeRepo{}, false
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return forgeRepo{}, false
+	}
+	repo := forgeRepo{Host: host, Path: path}
+	switch {
+	case strings.Contains(host, "github"):
+		repo.Kind = "github"
+	case strings.Contains(host, "gitlab"):
+		repo.Kind = "gitlab"
+	}
+	return repo, true
+}
+
+// 仓库的网页地址
+func (r forgeRepo) webURL() string {
+	return "https://" + r.Host + "/" + r.Path
+}
+
+// 当前分支的 PR 列表的网页地址，未安装 gh、glab 时使用
+func (r forgeRepo) branchPullsURL(branch string) string {
+	if r.Kind == "gitlab" {
+		return r.webURL() + "/-/merge_requests?source_branch=" + url.QueryEscape(branch)
+	}
+	return r.webURL() + "/pulls?q=" + url.QueryEscape("is:pr head:"+branch)
+}
+
+// 平台对应的命令行工具，无法识别的平台为空
+func (r forgeRepo) cli() string {
+	switch r.Kind {
+	case "github":
+		return "gh"
+	case "gitlab":
+		return "glab"
+	}
+	return ""
+}
+
+// PR 在平台上的叫法
+func (r forgeRepo) pullName() string {
+	if r.Kind == "gitlab" {
+		return "MR"
+	}
+	return "PR"
+}
+
+// 列出指派给当前用户或请求当前用户评审的未关闭 PR，通过 gh 或 glab 查询，使用其登录状态或 GH_TOKEN、GITLAB_TOKEN
+func (r forgeRepo) myPullRequests(ctx context.Context) ([]forgePullRequest, error) {
+	var queries [][]string
+	switch r.Kind {
+	case "github":
+		fields := "number,title,headRefName,url"
+		queries = [][]string{
+			{"gh", "pr", "list", "--state", "open", "--search", "assignee:@me", "--json", fields},
+			{"gh", "pr", "list", "--state", "open", "--search", "review-requested:@me", "--json", fields},
+		}
+	case "gitlab":
+		queries = [][]string{
+			{"glab", "mr", "list", "--assignee=@me", "--output", "json"},
+			{"glab", "mr", "list", "--reviewer=@me", "--output", "json"},
+		}
+	default:
+		return nil, fmt.Errorf("不支持 %s 上的仓库", r.Host)
+	}
+	var result []forgePullRequest
+	seen := make(map[int]bool)
+	for _, query := range queries {
+		out, err := queryOutput(ctx, query[0], query[1:]...)
+		if err != nil {
+			return nil, fmt.Errorf("%s 执行失败: %v", strings.Join(query[:3], " "), commandError(err))
+		}
+		pulls, err := r.decodePullRequests(out)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 %s 的输出: %v", query[0], err)
+		}
+		for _, pr := range pulls {
+			if !seen[pr.Number] {
+				seen[pr.Number] = true
+				result = append(result, pr)
+			}
+		}
+	}
+	return result, nil
+}
+
+// 解析 gh pr list --json 或 glab mr list --output json 的输出
+func (r forgeRepo) decodePullRequests(out []byte) ([]forgePullRequest, error) {
+	var pulls []forgePullRequest
+	if r.Kind == "gitlab" {
+		var items []struct {
+			IID          int    `json:"iid"`
+			Title        string `json:"title"`
+			SourceBranch string `json:"source_branch"`
+			WebURL       string `json:"web_url"`
+		}
+		if err := json.Unmarshal(out, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			pulls = append(pulls, forgePullRequest{Number: item.IID, Title: item.Title, Branch: item.SourceBranch, URL: item.WebURL})
+		}
+		return pulls, nil
+	}
+	var items []struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		HeadRefName string `json:"headRefName"`
+		URL         string `json:"url"`
+	}
+	if err := json.Unmarshal(out, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		pulls = append(pulls, forgePullRequest{Number: item.Number, Title: item.Title, Branch: item.HeadRefName, URL: item.URL})
+	}
+	return pulls, nil
+}
+
+// 命令失败时附带标准错误的内容，便于看出未登录等原因
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+	}
+	return err
+}
+
+// 代码托管平台的操作：打开仓库页面、打开当前分支的 PR、列出指派给我的 PR。
+// 根据 origin 的地址识别 GitHub 和 GitLab，安装了 gh、glab 时通过它们查询和打开
+func forgeActions(ctx context.Context, config *Config, folder, path string) error {
+	remote := gitOutput(ctx, "remote", "get-url", "origin")
+	if remote == "" {
+		fmt.Println("项目没有 origin 远程仓库")
+		return nil
+	}
+	repo, ok := parseForgeRemote(remote)
+	if !ok {
+		return fmt.Errorf("无法识别远程仓库地址 %s", remote)
+	}
+	pull := repo.pullName()
+	options := []string{"打开仓库页面", "打开当前分支的 " + pull, "列出指派给我的 " + pull}
+	fmt.Printf("%s（%s）：\n", repo.Path, repo.Host)
+	for i, option := range options {
+		fmt.Printf("%d. %s\n", i+1, option)
+	}
+	var choice int
+	for {
+		var err error
+		choice, err = getUserChoice(ctx, "请输入操作编号: ", len(options))
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			break
+		}
+		fmt.Println(err)
+	}
+
+	switch choice {
+	case 1:
+		return openURL(repo.webURL())
+	case 2:
+		branch := gitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+		if _, err := exec.LookPath(repo.cli()); repo.cli() != "" && err == nil && !skipOffline("通过 "+repo.cli()+" 查找 "+pull) {
+			args := []string{"pr", "view", "--web"}
+			if repo.Kind == "gitlab" {
+				args = []string{"mr", "view", "--web"}
+			}
+			if err := runStep(ctx, queryTimeout, nil, append([]string{repo.cli()}, args...)...); err == nil {
+				return nil
+			}
+			fmt.Printf("未找到分支 %s 的 %s，打开 %s 列表\n", branch, pull, pull)
+		}
+		return openURL(repo.branchPullsURL(branch))
+	}
+
+	if repo.cli() == "" {
+		return fmt.Errorf("不支持 %s 上的仓库", repo.Host)
+	}
+	if _, err := exec.LookPath(repo.cli()); err != nil {
+		return fmt.Errorf("未找到 %s，请安装后执行 %s auth login", repo.cli(), repo.cli())
+	}
+	if skipOffline("查询 " + pull) {
+		return nil
+	}
+	pulls, err := repo.myPullRequests(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pulls) == 0 {
+		fmt.Printf("没有指派给我或请求我评审的 %s\n", pull)
+		return nil
+	}
+	for i, pr := range pulls {
+		fmt.Printf("%d. #%d %s  · %s\n", i+1, pr.Number, pr.Title, pr.Branch)
+	}
+	input, err := readLine(ctx, "输入编号在浏览器中打开，编号后加 c 切换到该分支后启动（如 1c），直接回车返回: ")
+	if err != nil || input == "" {
+		return nil
+	}
+	checkout := strings.HasSuffix(input, "c")
+	n, err := parseChoice(strings.TrimSuffix(input, "c"), 1, len(pulls))
+	if err != nil {
+		return err
+	}
+	pr := pulls[n-1]
+	if !checkout {
+		return openURL(pr.URL)
+	}
+	// launchBranch 从项目的上级目录进入项目，服务结束回到操作菜单时仍在项目目录中
+	defer os.Chdir(path)
+	if err := os.Chdir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return launchBranch(ctx, config, filepath.Base(path), pr.Branch)
+}
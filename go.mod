@@ -1,3 +1,15 @@
 module go-quickStart
 
 go 1.21.6
+
+require (
+	fyne.io/systray v1.12.2
+	github.com/jezek/xgb v1.3.1
+	go.starlark.net v0.0.0-20250225190231-0d3f41d403af
+	golang.design/x/hotkey v0.4.1
+)
+
+require (
+	github.com/godbus/dbus/v5 v5.1.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+)
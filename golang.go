@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// Go 项目默认 go run .，存在 cmd 目录时为其中每个命令提供启动方式
+func goActions(_ context.Context) []launchAction {
+	var actions []launchAction
+	if fileExists("main.go") || len(cmdDirs()) == 0 {
+		actions = append(actions, launchAction{Name: "go run .", Command: []string{"go", "run", "."}})
+	}
+	for _, dir := range cmdDirs() {
+		target := "./" + filepath.ToSlash(dir)
+		actions = append(actions, launchAction{Name: "go run " + target, Command: []string{"go", "run", target}})
+	}
+	return actions
+}
+
+// 查找 cmd 目录下的命令目录
+func cmdDirs() []string {
+	var dirs []string
+	matches, _ := filepath.Glob(filepath.Join("cmd", "*", "main.go"))
+	for _, match := range matches {
+		dirs = append(dirs, filepath.Dir(match))
+	}
+	return dirs
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 在新终端窗口中启动项目组时，等待依赖的项目出现在运行记录中的最长时间，包括启动前的倒计时和启动前检查
+const groupStartTimeout = 2 * time.Minute
+
+// 执行 group 子命令：quickstart group [名称]。未指定名称时列出项目组，
+// 否则按依赖顺序分批启动组内项目的服务，每批在上一批全部就绪后启动，输出带有项目名前缀。
+// 项目组配置了 terminal 时改为在新终端窗口中按布局为每个服务打开一个窗格
+func runGroupCommand(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("用法: quickstart group [名称]")
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	if len(args) == 0 {
+		return listGroups(config)
+	}
+	group, ok := config.Groups[args[0]]
+	if !ok {
+		setExitCode(exitNotFound)
+		return fmt.Errorf("项目组 %s 不存在", args[0])
+	}
+	levels, err := groupLevels(config, group.Projects)
+	if err != nil {
+		setExitCode(exitConfigError)
+		return fmt.Errorf("项目组 %s 配置有误: %v", args[0], err)
+	}
+	if group.Workspace && !openGroupWorkspace(ctx, config, args[0], levels) {
+		setExitCode(exitCanceled)
+		return nil
+	}
+	if group.Terminal != "" {
+		return openGroupLayout(ctx, config, args[0], group, levels)
+	}
+	return startGroup(ctx, config, args[0], levels)
+}
+
+// 列出项目组及其启动顺序
+func listGroups(config *Config) error {
+	if len(config.Groups) == 0 {
+		fmt.Println("未配置项目组，可在配置文件的 groups 中添加")
+		return nil
+	}
+	for _, name := range sortedKeys(config.Groups) {
+		levels, err := groupLevels(config, config.Groups[name].Projects)
+		if err != nil {
+			fmt.Printf("%s: 配置有误: %v\n", name, err)
+			continue
+		}
+		batches := make([]string, len(levels))
+		for i, level := range levels {
+			batches[i] = strings.Join(level, "、")
+		}
+		fmt.Printf("%s: %s\n", name, strings.Join(batches, " → "))
+	}
+	return nil
+}
+
+// 按依赖关系将项目组分为依次启动的批次：没有依赖的项目在第一批，其余项目在其依赖的最后一批之后。
+// 同一批内保持配置中的顺序。依赖不在组内或存在循环依赖时返回错误
+func groupLevels(config *Config, members []GroupMember) ([][]string, error) {
+	after := make(map[string][]string, len(members))
+	var order []string
+	for _, m := range members {
+		name := resolveAlias(config, m.Project)
+		if name == "" {
+			return nil, fmt.Errorf("有项目未填写 project")
+		}
+		if _, ok := after[name]; ok {
+			return nil, fmt.Errorf("项目 %s 重复", name)
+		}
+		deps := make([]string, 0, len(m.After))
+		for _, dep := range m.After {
+			deps = append(deps, resolveAlias(config, dep))
+		}
+		after[name] = deps
+		order = append(order, name)
+	}
+
+	level := make(map[string]int, len(order))
+	visiting := make(map[string]bool)
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if _, ok := level[name]; ok {
+			return nil
+		}
+		path = append(path, name)
+		if visiting[name] {
+			return fmt.Errorf("存在循环依赖 %s", strings.Join(path, " → "))
+		}
+		visiting[name] = true
+		n := 0
+		for _, dep := range after[name] {
+			if _, ok := after[dep]; !ok {
+				return fmt.Errorf("%s 依赖的 %s 不在项目组中", name, dep)
+			}
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+			n = max(n, level[dep]+1)
+		}
+		visiting[name] = false
+		level[name] = n
+		return nil
+	}
+	var levels [][]string
+	for _, name := range order {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range order {
+		for len(levels) <= level[name] {
+			levels = append(levels, nil)
+		}
+		levels[level[name]] = append(levels[level[name]], name)
+	}
+	return levels, nil
+}
+
+// 确定组内每个项目的启动命令并同时运行，每批的服务等待上一批全部就绪后启动
+func startGroup(ctx context.Context, config *Config, name string, levels [][]string) error {
+	var services, previous []*service
+	fmt.Printf("项目组 %s 按以下顺序启动：\n", name)
+	for i, level := range levels {
+		fmt.Printf("%d. %s\n", i+1, strings.Join(level, "、"))
+		var batch []*service
+		for _, project := range level {
+			svc, err := groupService(ctx, config, project)
+			if err != nil {
+				return err
+			}
+			if svc == nil {
+				return nil
+			}
+			svc.After = previous
+			batch = append(batch, svc)
+		}
+		services = append(services, batch...)
+		previous = batch
+	}
+	for _, level := range levels {
+		for _, project := range level {
+			recordLaunch(project)
+		}
+	}
+	fmt.Println("5秒后启动所有服务，Ctrl+C 停止")
+	if !waitLaunch(ctx) {
+		return nil
+	}
+	runServices(ctx, services)
+	return nil
+}
+
+// 在项目的启动目录中完成启动前检查，并按项目配置或第一个匹配的项目类型创建服务，不询问启动方式。
+// 检查未通过时返回 nil
+func groupService(ctx context.Context, config *Config, name string) (*service, error) {
+	project := findProject(config, name)
+	if project != nil && (project.Remote != "" || project.WSL != "") {
+		setExitCode(exitConfigError)
+		return nil, fmt.Errorf("项目组不支持远程或 WSL 项目: %s", name)
+	}
+	path := filepath.Join(config.ProjectDir, name)
+	if err := os.Chdir(path); err != nil {
+		setExitCode(exitNotFound)
+		return nil, fmt.Errorf("无法进入项目目录: %v", err)
+	}
+	if project != nil && project.WorkDir != "" {
+		if err := os.Chdir(project.WorkDir); err != nil {
+			return nil, fmt.Errorf("无法进入启动目录 %s: %v", project.WorkDir, err)
+		}
+	}
+	if !prepareLaunch(ctx, config, name) {
+		return nil, nil
+	}
+	command := autoLaunchCommand(ctx, config, name)
+	if len(command) == 0 {
+		setExitCode(exitLaunchFailed)
+		return nil, fmt.Errorf("未检测到 %s 的启动命令", name)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	svc := newService(ctx, config, name, wrapCommand(batchCommand(command)))
+	svc.Path = path
+	svc.Dir = dir
+	return svc, nil
+}
+
+// 执行 start 子命令：quickstart start [--after 项目]... <项目>。等待 --after 指定的项目运行并就绪后，
+// 不询问启动方式、不打开编辑器直接启动项目的服务。项目组在新终端窗口中启动时每个窗格执行该命令
+func runStartCommand(ctx context.Context, args []string) error {
+	var after []string
+	for len(args) > 0 && args[0] == "--after" {
+		if len(args) < 2 {
+			return fmt.Errorf("--after 需要指定项目")
+		}
+		after = append(after, args[1])
+		args = args[2:]
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("用法: quickstart start [--after 项目]... <项目>")
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	for _, project := range after {
+		if !waitForProject(resolveAlias(config, project)) {
+			setExitCode(exitLaunchFailed)
+			return fmt.Errorf("%s 未能启动，不再启动 %s", project, args[0])
+		}
+	}
+	name := resolveAlias(config, args[0])
+	svc, err := groupService(ctx, config, name)
+	if err != nil || svc == nil {
+		return err
+	}
+	recordLaunch(name)
+	runServices(ctx, []*service{svc})
+	return nil
+}
+
+// 等待其他启动器实例中项目的服务出现在运行记录中，配置了就绪检查时再等待检查通过，返回是否就绪
+func waitForProject(project string) bool {
+	fmt.Printf("等待 %s 启动，Ctrl+C 取消\n", project)
+	deadline := time.Now().Add(groupStartTimeout)
+	for time.Now().Before(deadline) {
+		for _, record := range runningServices() {
+			if record.Project != project {
+				continue
+			}
+			if record.Health == nil {
+				return true
+			}
+			return watchHealth(project, record.Health, nil)
+		}
+		time.Sleep(healthInterval)
+	}
+	fmt.Printf("✘ %s 在 %v 内未启动\n", project, groupStartTimeout)
+	return false
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// 菜单标题栏中显示版本的工具
+var headerTools = []string{"node", "go"}
+
+var (
+	headerOnce sync.Once
+	// 工具版本和 git 用户在本次运行中只查询一次，避免每次刷新菜单都启动子进程
+	headerVersions []string
+	headerGitUser  string
+)
+
+// 打印菜单顶部的标题栏：当前配置档、工作目录、正在运行的服务数、git 用户和常用工具版本
+func printMenuHeader(ctx context.Context, config *Config) {
+	headerOnce.Do(func() {
+		var wg sync.WaitGroup
+		versions := make([]string, len(headerTools))
+		for i, tool := range headerTools {
+			if _, err := exec.LookPath(tool); err != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, tool string) {
+				defer wg.Done()
+				args, _ := toolInfo(tool)
+				if version := versionPattern.FindString(toolVersion(ctx, tool, args...)); version != "" {
+					versions[i] = tool + " " + version
+				}
+			}(i, tool)
+		}
+		headerGitUser = gitOutput(ctx, "config", "user.name")
+		wg.Wait()
+		for _, v := range versions {
+			if v != "" {
+				headerVersions = append(headerVersions, v)
+			}
+		}
+	})
+
+	var parts []string
+	if config.ActiveProfile != "" {
+		parts = append(parts, "配置档 "+config.ActiveProfile)
+	}
+	parts = append(parts, "工作目录 "+config.ProjectDir)
+	if offline() {
+		parts = append(parts, "离线模式")
+	}
+	if n := len(runningServices()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个服务运行中", n))
+	}
+	if status := focusStatus(); status != "" {
+		parts = append(parts, status)
+	}
+	if headerGitUser != "" {
+		parts = append(parts, "git "+headerGitUser)
+	}
+	parts = append(parts, headerVersions...)
+	fmt.Println(strings.Join(parts, " · "))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 就绪检查的轮询间隔
+const healthInterval = time.Second
+
+// 描述就绪检查的目标，用于提示信息
+func (h *HealthCheckConfig) target() string {
+	if h.URL != "" {
+		return h.URL
+	}
+	return ":" + strconv.Itoa(h.Port)
+}
+
+// 检查一次服务是否就绪
+func (h *HealthCheckConfig) check() bool {
+	if h.URL != "" {
+		client := http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Get(h.URL)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode < 400
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(h.Port)), 2*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// 等待就绪的最长秒数
+func (h *HealthCheckConfig) timeout() int {
+	if h.Timeout <= 0 {
+		return 60
+	}
+	return h.Timeout
+}
+
+// 轮询直到服务就绪或超时，stop 关闭时提前结束
+func (h *HealthCheckConfig) wait(stop <-chan struct{}) bool {
+	deadline := time.Now().Add(time.Duration(h.timeout()) * time.Second)
+	for time.Now().Before(deadline) {
+		if h.check() {
+			return true
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(healthInterval):
+		}
+	}
+	return false
+}
+
+// 等待服务就绪并打印结果，返回是否就绪
+func watchHealth(project string, h *HealthCheckConfig, stop <-chan struct{}) bool {
+	start := time.Now()
+	if h.wait(stop) {
+		fmt.Printf("✔ %s 已就绪 %s（%.1fs）\n", project, h.target(), time.Since(start).Seconds())
+		return true
+	}
+	select {
+	case <-stop:
+	default:
+		fmt.Printf("✘ %s 在 %d 秒内未就绪 %s\n", project, h.timeout(), h.target())
+	}
+	return false
+}
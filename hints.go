@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// activityEntry 为缓存的项目最近活动时间
+type activityEntry struct {
+	// Stamp 为计算时 .git/logs/HEAD 或文件夹的修改时间，变化后重新计算
+	Stamp time.Time `json:"stamp"`
+	Time  time.Time `json:"time"`
+}
+
+// 缓存文件路径
+func activityCachePath() string {
+	return statePath("cache", "activity.json")
+}
+
+// 获取文件夹最近一次活动的时间：git 仓库为最近一次提交的时间，否则为文件夹的修改时间。
+// 各文件夹并发计算，结果缓存到状态目录，仓库或文件夹没有变化时直接使用缓存
+func folderActivity(ctx context.Context, dir string, projects []*Project) map[string]time.Time {
+	cache := make(map[string]activityEntry)
+	readJSONFile(activityCachePath(), &cache)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		limit   = make(chan struct{}, 8)
+		result  = make(map[string]time.Time)
+		changed bool
+	)
+	for _, p := range projects {
+		path := filepath.Join(dir, p.Name)
+		stamp, ok := activityStamp(path)
+		if !ok {
+			continue
+		}
+		if entry, ok := cache[path]; ok && entry.Stamp.Equal(stamp) {
+			result[p.Name] = entry.Time
+			continue
+		}
+		wg.Add(1)
+		go func(name, path string, stamp time.Time) {
+			defer wg.Done()
+			limit <- struct{}{}
+			t := lastCommitTime(ctx, path)
+			<-limit
+			if t.IsZero() {
+				t = stamp
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			result[name] = t
+			cache[path] = activityEntry{Stamp: stamp, Time: t}
+			changed = true
+		}(p.Name, path, stamp)
+	}
+	wg.Wait()
+
+	if changed {
+		if data, err := json.MarshalIndent(cache, "", "  "); err == nil {
+			if err := os.MkdirAll(filepath.Dir(activityCachePath()), 0755); err == nil {
+				os.WriteFile(activityCachePath(), data, 0644)
+			}
+		}
+	}
+	return result
+}
+
+// 获取用于判断缓存是否失效的时间，git 仓库使用 .git/logs/HEAD 的修改时间
+func activityStamp(path string) (time.Time, bool) {
+	if info, err := os.Stat(filepath.Join(path, ".git", "logs", "HEAD")); err == nil {
+		return info.ModTime(), true
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// 获取 git 仓库最近一次提交的时间，不是 git 仓库时返回零值
+func lastCommitTime(ctx context.Context, path string) time.Time {
+	if !fileExists(filepath.Join(path, ".git")) {
+		return time.Time{}
+	}
+	cmd := exec.CommandContext(ctx, "git", "log", "-1", "--format=%ct")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}
+	}
+	var sec int64
+	if _, err := fmt.Sscan(strings.TrimSpace(string(out)), &sec); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// 将时间格式化为相对当前的描述，如“3 天前”
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "刚刚"
+	case d < time.Hour:
+		return fmt.Sprintf("%d 分钟前", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d 小时前", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%d 天前", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%d 个月前", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%d 年前", int(d.Hours()/24/365))
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 启动记录文件路径，记录每个项目最近一次启动的时间
+func historyPath() string {
+	return statePath("history.json")
+}
+
+// 读取各项目最近一次启动的时间
+func launchHistory() map[string]time.Time {
+	history := make(map[string]time.Time)
+	readJSONFile(historyPath(), &history)
+	return history
+}
+
+// 记录项目的启动时间
+func recordLaunch(name string) {
+	history := launchHistory()
+	history[name] = time.Now()
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(historyPath()), 0755); err != nil {
+		return
+	}
+	os.WriteFile(historyPath(), data, 0644)
+}
+
+// 获取最近一次启动的项目，从未启动过项目时返回空字符串
+func lastLaunched() string {
+	var (
+		last string
+		at   time.Time
+	)
+	for name, t := range launchHistory() {
+		if t.After(at) || t.Equal(at) && name < last {
+			last, at = name, t
+		}
+	}
+	return last
+}
+
+// 执行 last 子命令，直接启动最近一次启动的项目
+func runLastCommand(ctx context.Context) error {
+	name := lastLaunched()
+	if name == "" {
+		setExitCode(exitNotFound)
+		return fmt.Errorf("还没有启动过任何项目")
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	return runProjectByName(ctx, config, name)
+}
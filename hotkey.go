@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 快捷键中修饰键的写法，统一为 ctrl、alt、shift、super
+var hotkeyModifierNames = map[string]string{
+	"ctrl": "ctrl", "control": "ctrl",
+	"alt": "alt", "option": "alt", "opt": "alt",
+	"shift": "shift",
+	"super": "super", "win": "super", "cmd": "super", "command": "super", "meta": "super",
+}
+
+// 解析快捷键，如 ctrl+alt+space、cmd+shift+p，返回规范化的修饰键和按键。按键可用 a-z、0-9、space、f1-f12，至少需要一个修饰键
+func parseHotkey(spec string) (mods []string, key string, err error) {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(spec, " ", "")), "+")
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := hotkeyModifierNames[part]
+		if !ok {
+			return nil, "", fmt.Errorf("快捷键 %s 中的修饰键 %s 无效，可用 ctrl、alt、shift、super", spec, part)
+		}
+		if !contains(mod, mods) {
+			mods = append(mods, mod)
+		}
+	}
+	key = parts[len(parts)-1]
+	valid := len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= '0' && key[0] <= '9') || key == "space"
+	if n := strings.TrimPrefix(key, "f"); !valid && n != key {
+		for i := 1; i <= 12; i++ {
+			valid = valid || n == fmt.Sprint(i)
+		}
+	}
+	if !valid {
+		return nil, "", fmt.Errorf("快捷键 %s 中的按键 %s 无效，可用 a-z、0-9、space、f1-f12", spec, key)
+	}
+	if len(mods) == 0 {
+		return nil, "", fmt.Errorf("快捷键 %s 至少需要一个修饰键", spec)
+	}
+	return mods, key, nil
+}
+
+// 注册配置的全局快捷键，按下时在新终端窗口中打开项目选择菜单
+func startHotkey(config *Config) error {
+	mods, key, err := parseHotkey(config.Hotkey.Keys)
+	if err != nil {
+		return err
+	}
+	return registerHotkey(mods, key, func() {
+		if err := openPickerWindow(config); err != nil {
+			notify("QuickStart", fmt.Sprintf("无法打开终端窗口: %v", err))
+		}
+	})
+}
+
+// 在新终端窗口中运行启动器，显示项目选择菜单。配置了 hotkey.terminal 时使用该命令，否则按系统选择终端
+func openPickerWindow(config *Config) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("无法获取当前执行文件的路径: %v", err)
+	}
+	launcher := []string{exe}
+	if config.ActiveProfile != "" {
+		launcher = append(launcher, "--profile", config.ActiveProfile)
+	}
+
+	var cmd *exec.Cmd
+	switch {
+	case config.Hotkey.Terminal != "":
+		args := append(strings.Fields(config.Hotkey.Terminal), launcher...)
+		cmd = exec.Command(args[0], args[1:]...)
+	case runtime.GOOS == "windows":
+		if _, err := exec.LookPath("wt"); err == nil {
+			cmd = exec.Command("wt", append([]string{"-w", "new", "--title", "QuickStart"}, launcher...)...)
+		} else {
+			cmd = exec.Command("cmd", append([]string{"/c", "start", "QuickStart"}, launcher...)...)
+		}
+	case runtime.GOOS == "darwin":
+		quoted := make([]string, len(launcher))
+		for i, arg := range launcher {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		script := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Join(quoted, " "))
+		cmd = exec.Command("osascript", "-e", `tell application "Terminal"`, "-e", "activate", "-e", `do script "`+script+`"`, "-e", "end tell")
+	default:
+		for _, candidate := range [][]string{
+			{"x-terminal-emulator", "-e"},
+			{"gnome-terminal", "--"},
+			{"konsole", "-e"},
+			{"kitty"},
+			{"alacritty", "-e"},
+			{"xterm", "-e"},
+		} {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				cmd = exec.Command(candidate[0], append(candidate[1:], launcher...)...)
+				break
+			}
+		}
+		if cmd == nil {
+			return fmt.Errorf("未找到终端程序，请在 hotkey.terminal 中配置，如 \"kitty -e\"")
+		}
+	}
+	cmd.Dir = config.ProjectDir
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build cgo
+
+package main
+
+import "golang.design/x/hotkey"
+
+// 修饰键对应的 Carbon 标志，alt 为 Option 键，super 为 Command 键
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"alt":   hotkey.ModOption,
+	"shift": hotkey.ModShift,
+	"super": hotkey.ModCmd,
+}
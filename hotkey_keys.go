@@ -0,0 +1,43 @@
+//go:build windows || (darwin && cgo)
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.design/x/hotkey"
+)
+
+// 快捷键中按键对应的虚拟键码，各系统的键码不同，使用 hotkey 包按系统定义的常量
+var hotkeyKeys = map[string]hotkey.Key{
+	"a": hotkey.KeyA, "b": hotkey.KeyB, "c": hotkey.KeyC, "d": hotkey.KeyD, "e": hotkey.KeyE,
+	"f": hotkey.KeyF, "g": hotkey.KeyG, "h": hotkey.KeyH, "i": hotkey.KeyI, "j": hotkey.KeyJ,
+	"k": hotkey.KeyK, "l": hotkey.KeyL, "m": hotkey.KeyM, "n": hotkey.KeyN, "o": hotkey.KeyO,
+	"p": hotkey.KeyP, "q": hotkey.KeyQ, "r": hotkey.KeyR, "s": hotkey.KeyS, "t": hotkey.KeyT,
+	"u": hotkey.KeyU, "v": hotkey.KeyV, "w": hotkey.KeyW, "x": hotkey.KeyX, "y": hotkey.KeyY,
+	"z": hotkey.KeyZ,
+	"0": hotkey.Key0, "1": hotkey.Key1, "2": hotkey.Key2, "3": hotkey.Key3, "4": hotkey.Key4,
+	"5": hotkey.Key5, "6": hotkey.Key6, "7": hotkey.Key7, "8": hotkey.Key8, "9": hotkey.Key9,
+	"space": hotkey.KeySpace, "f1": hotkey.KeyF1, "f2": hotkey.KeyF2, "f3": hotkey.KeyF3, "f4": hotkey.KeyF4,
+	"f5": hotkey.KeyF5, "f6": hotkey.KeyF6, "f7": hotkey.KeyF7, "f8": hotkey.KeyF8,
+	"f9": hotkey.KeyF9, "f10": hotkey.KeyF10, "f11": hotkey.KeyF11, "f12": hotkey.KeyF12,
+}
+
+// 注册系统全局快捷键，按下时调用 fire
+func registerHotkey(mods []string, key string, fire func()) error {
+	var modifiers []hotkey.Modifier
+	for _, mod := range mods {
+		modifiers = append(modifiers, hotkeyModifiers[mod])
+	}
+	hk := hotkey.New(modifiers, hotkeyKeys[key])
+	if err := hk.Register(); err != nil {
+		return fmt.Errorf("快捷键 %s 已被其他程序占用: %v", strings.Join(append(mods, key), "+"), err)
+	}
+	go func() {
+		for range hk.Keydown() {
+			fire()
+		}
+	}()
+	return nil
+}
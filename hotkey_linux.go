@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// X11 中修饰键对应的掩码
+var x11Modifiers = map[string]uint16{
+	"ctrl":  xproto.ModMaskControl,
+	"alt":   xproto.ModMask1,
+	"shift": xproto.ModMaskShift,
+	"super": xproto.ModMask4,
+}
+
+// 按键对应的 X11 keysym
+func x11Keysym(key string) xproto.Keysym {
+	switch {
+	case key == "space":
+		return 0x20
+	case len(key) == 1:
+		// a-z 和 0-9 的 keysym 与 ASCII 相同
+		return xproto.Keysym(key[0])
+	default:
+		var n int
+		fmt.Sscanf(key, "f%d", &n)
+		return xproto.Keysym(0xffbe + n - 1)
+	}
+}
+
+// 通过 X11 在根窗口上抓取快捷键，按下时调用 fire。Wayland 桌面不支持全局抓取按键，可在系统设置中将快捷键绑定到在终端中运行 quickstart 的命令
+func registerHotkey(mods []string, key string, fire func()) error {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return fmt.Errorf("无法连接 X11 显示服务，Wayland 桌面请在系统设置中绑定快捷键: %v", err)
+	}
+	setup := xproto.Setup(conn)
+	root := setup.DefaultScreen(conn).Root
+
+	// 查找产生该 keysym 的键码
+	count := byte(setup.MaxKeycode - setup.MinKeycode + 1)
+	mapping, err := xproto.GetKeyboardMapping(conn, setup.MinKeycode, count).Reply()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("无法读取键盘映射: %v", err)
+	}
+	sym := x11Keysym(key)
+	var keycode xproto.Keycode
+	per := int(mapping.KeysymsPerKeycode)
+	for i := 0; i < int(count) && keycode == 0; i++ {
+		for j := 0; j < per; j++ {
+			if mapping.Keysyms[i*per+j] == sym {
+				keycode = setup.MinKeycode + xproto.Keycode(i)
+				break
+			}
+		}
+	}
+	if keycode == 0 {
+		conn.Close()
+		return fmt.Errorf("键盘上没有按键 %s", key)
+	}
+
+	var mask uint16
+	for _, mod := range mods {
+		mask |= x11Modifiers[mod]
+	}
+	// 开启大写锁定或数字锁定（通常为 Mod2）时同样生效
+	for _, lock := range []uint16{0, xproto.ModMaskLock, xproto.ModMask2, xproto.ModMaskLock | xproto.ModMask2} {
+		if err := xproto.GrabKeyChecked(conn, true, root, mask|lock, keycode, xproto.GrabModeAsync, xproto.GrabModeAsync).Check(); err != nil {
+			conn.Close()
+			return fmt.Errorf("快捷键 %s 已被其他程序占用", strings.Join(append(mods, key), "+"))
+		}
+	}
+
+	go func() {
+		defer conn.Close()
+		var last time.Time
+		for {
+			event, err := conn.WaitForEvent()
+			if event == nil && err == nil {
+				return
+			}
+			// 长按时按键自动重复，短时间内只触发一次
+			if _, ok := event.(xproto.KeyPressEvent); ok && time.Since(last) > time.Second {
+				last = time.Now()
+				fire()
+			}
+		}
+	}()
+	return nil
+}
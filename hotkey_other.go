@@ -0,0 +1,10 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package main
+
+import "fmt"
+
+// 其他系统及未启用 cgo 编译的 macOS 不支持全局快捷键
+func registerHotkey(mods []string, key string, fire func()) error {
+	return fmt.Errorf("当前系统不支持全局快捷键，macOS 上需要启用 cgo 编译")
+}
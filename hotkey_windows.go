@@ -0,0 +1,11 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// 修饰键对应的 RegisterHotKey 标志，super 为 Win 键
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"alt":   hotkey.ModAlt,
+	"shift": hotkey.ModShift,
+	"super": hotkey.ModWin,
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 检查服务是否空闲的间隔
+const idleCheckInterval = 15 * time.Second
+
+// idleMonitor 记录服务最近一次有访问、输出或文件变动的时间
+type idleMonitor struct {
+	last atomic.Int64
+}
+
+// 记录一次活动
+func (m *idleMonitor) touch() {
+	m.last.Store(time.Now().UnixNano())
+}
+
+// 服务输出时记录活动
+func (m *idleMonitor) Write(p []byte) (int, error) {
+	m.touch()
+	return len(p), nil
+}
+
+// 自上次活动以来的时长
+func (m *idleMonitor) idle() time.Duration {
+	return time.Since(time.Unix(0, m.last.Load()))
+}
+
+// 获取项目的空闲停止时长，项目配置优先，小于 0 时该项目不停止
+func projectIdleStop(config *Config, name string) time.Duration {
+	minutes := config.IdleStop
+	if project := findProject(config, name); project != nil && project.IdleStop != 0 {
+		minutes = project.IdleStop
+	}
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// 在后台检查服务是否空闲：端口上有连接、有输出或项目文件有变动时视为活动，
+// 超过 IdleStop 没有活动时向返回的通道发送。服务未配置空闲停止时返回 nil
+func watchIdle(svc *service) <-chan struct{} {
+	if svc.IdleStop <= 0 {
+		return nil
+	}
+	svc.idle = &idleMonitor{}
+	svc.idle.touch()
+	patterns := svc.Watch
+	if len(patterns) == 0 {
+		patterns = []string{"**"}
+	}
+	watcher := newFileWatcher(svc.Dir, patterns)
+	idle := make(chan struct{})
+	go func() {
+		for {
+			time.Sleep(idleCheckInterval)
+			if (svc.Port > 0 && hasConnections(svc.Port)) || watcher.changed() != "" {
+				svc.idle.touch()
+			}
+			if svc.idle.idle() < svc.IdleStop {
+				continue
+			}
+			// 服务未在运行（如等待重启）时不发送，避免重新启动后立即被停止
+			select {
+			case idle <- struct{}{}:
+			case <-time.After(idleCheckInterval):
+			}
+			svc.idle.touch()
+		}
+	}()
+	return idle
+}
+
+// 本机端口上是否有已建立的连接，如浏览器打开着页面或有请求正在处理。无法读取连接时视为没有
+func hasConnections(port int) bool {
+	if runtime.GOOS == "linux" {
+		return procConnections(port, "/proc/net/tcp") || procConnections(port, "/proc/net/tcp6")
+	}
+	out, err := exec.Command("netstat", "-an").Output()
+	if err != nil {
+		return false
+	}
+	suffix := strconv.Itoa(port)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		// macOS 的本地地址写为 127.0.0.1.3000，Windows 为 127.0.0.1:3000，都位于倒数第三列
+		if len(fields) < 4 || fields[len(fields)-1] != "ESTABLISHED" {
+			continue
+		}
+		local := fields[len(fields)-3]
+		if strings.HasSuffix(local, ":"+suffix) || strings.HasSuffix(local, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 读取 /proc/net/tcp 格式的连接表，本地端口为 port 且状态为 ESTABLISHED（01）时返回 true
+func procConnections(port int, path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	suffix := fmt.Sprintf(":%04X", port)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 3 && strings.HasSuffix(fields[1], suffix) && fields[3] == "01" {
+			return true
+		}
+	}
+	return false
+}
+
+// 服务因空闲停止后提示并发送通知。在终端中单独运行时等待按键重新启动，返回是否重新启动；
+// 守护进程和多服务视图中的服务直接结束，可在托盘或编辑器插件中重新启动
+func waitResume(ctx context.Context, svc *service) bool {
+	message := fmt.Sprintf("%s 已 %d 分钟没有访问、输出或文件变动，服务已停止", svc.label(), int(svc.IdleStop.Minutes()))
+	logf("%s", message)
+	notify("QuickStart", message)
+	if svc.Stop != nil || svc.Output != nil {
+		fmt.Fprintln(svc.output(), message)
+		return false
+	}
+	restore, ok := enableRawInput()
+	if !ok {
+		fmt.Fprintln(svc.output(), message)
+		return false
+	}
+	defer restore()
+	printBanner(svc.output(), message+"，按任意键重新启动，Ctrl+C 退出")
+	r, err := readRune(ctx, nil)
+	if err != nil || r == 3 || r == 4 {
+		return false
+	}
+	printBanner(svc.output(), "重新启动服务")
+	return true
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// 项目信息中显示的 README 和笔记的最多行数
+const readmeLines = 5
+
+// 显示项目概况：项目类型、README 开头、git 信息、标签和别名、可用脚本、VS Code 任务、配置的命令、所需工具、依赖服务、链接、最近启动时间和运行状态，
+// 本地项目需在项目目录中调用
+func showProjectInfo(ctx context.Context, config *Config, folder, path string) error {
+	p := newProject(config, folder)
+	project := p.Config
+	fmt.Printf("项目：%s\n", folder)
+
+	switch {
+	case project != nil && project.Remote != "":
+		fmt.Printf("远程路径：%s\n", project.Remote)
+	case project != nil && project.WSL != "":
+		fmt.Printf("WSL 路径：%s\n", project.WSL)
+	default:
+		// 子目录中的项目不在工作目录下，以调用时所在的项目目录为准
+		p.Path = path
+		fmt.Printf("路径：%s\n", path)
+		if err := p.resolve(ctx, config); err != nil {
+			return err
+		}
+		if len(p.Types) > 1 {
+			fmt.Printf("类型：%s（同时为 %s 项目）\n", p.Type, strings.Join(p.Types, "、"))
+		} else if p.Type != "" {
+			fmt.Printf("类型：%s\n", p.Type)
+		} else {
+			fmt.Println("类型：未识别")
+		}
+		if p.Git != nil {
+			fmt.Printf("git 分支：%s\n", p.Git.Branch)
+			if p.Git.Remote != "" {
+				fmt.Printf("git 远程：%s\n", p.Git.Remote)
+			}
+		}
+		if scripts := projectScripts(); len(scripts) > 0 {
+			names := make([]string, 0, len(scripts))
+			for _, script := range scripts {
+				names = append(names, script.Name)
+			}
+			fmt.Printf("可用脚本：%s\n", strings.Join(names, "、"))
+		}
+		if tasks := vscodeTasks(config, folder); len(tasks) > 0 {
+			names := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				names = append(names, task.Name)
+			}
+			fmt.Printf("VS Code 任务：%s\n", strings.Join(names, "、"))
+		}
+	}
+
+	if len(p.Tags) > 0 {
+		fmt.Printf("标签：%s\n", strings.Join(p.Tags, "、"))
+	}
+	if len(p.Aliases) > 0 {
+		fmt.Printf("别名：%s\n", strings.Join(p.Aliases, "、"))
+	}
+	if project != nil && project.Command != "" {
+		fmt.Printf("启动命令：%s\n", project.Command)
+	}
+	if project != nil && project.source != "" {
+		fmt.Printf("项目配置：%s\n", project.source)
+	}
+	if project != nil && len(project.Requires) > 0 {
+		statuses := make([]string, 0, len(project.Requires))
+		for _, s := range project.Requires {
+			req, err := parseRequirement(s)
+			if err == nil {
+				_, err = req.check(ctx)
+			}
+			if err != nil {
+				statuses = append(statuses, fmt.Sprintf("%s ✘（%v）", s, err))
+			} else {
+				statuses = append(statuses, s+" ✔")
+			}
+		}
+		fmt.Printf("所需工具：%s\n", strings.Join(statuses, "、"))
+	}
+	if project != nil && len(project.Services) > 0 {
+		statuses := make([]string, 0, len(project.Services))
+		for i := range project.Services {
+			statuses = append(statuses, project.Services[i].Name+" "+project.Services[i].status(ctx))
+		}
+		fmt.Printf("依赖服务：%s\n", strings.Join(statuses, "、"))
+	}
+	if links := projectLinks(config, folder); len(links) > 0 {
+		fmt.Println("链接：")
+		for _, link := range links {
+			fmt.Printf("  %s  %s\n", link.Name, link.URL)
+		}
+	}
+	if t, ok := launchHistory()[folder]; ok {
+		fmt.Printf("最近启动：%s（%s）\n", t.Format("2006-01-02 15:04"), relativeTime(t))
+	} else {
+		fmt.Println("最近启动：从未")
+	}
+	for _, record := range p.Running {
+		fmt.Printf("运行中：PID %d，已运行 %v，%s\n", record.PID, time.Since(record.Started).Round(time.Second), strings.Join(record.Command, " "))
+	}
+	if len(p.Running) == 0 {
+		fmt.Println("运行状态：未运行")
+	}
+
+	if lines := readmeHead(); len(lines) > 0 {
+		fmt.Println("README：")
+		for _, line := range lines {
+			fmt.Println("  " + line)
+		}
+	}
+	if lines := headLines(notesPath(folder)); len(lines) > 0 {
+		fmt.Println("笔记：")
+		for _, line := range lines {
+			fmt.Println("  " + line)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// 执行 info 子命令，显示指定项目的概况
+func runInfoCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: quickstart info <项目>")
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	c, ok, suggestions, err := matchProject(config, args[0])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		setExitCode(exitNotFound)
+		return fmt.Errorf("项目 %s 不存在%s", args[0], notFoundHint(suggestions))
+	}
+	if err := c.enter(config); err != nil {
+		return err
+	}
+	folder := c.Name
+	path := ""
+	if project := findProject(config, folder); project == nil || project.Remote == "" && project.WSL == "" {
+		if err := os.Chdir(folder); err != nil {
+			return err
+		}
+		path, _ = os.Getwd()
+	}
+	return showProjectInfo(ctx, config, folder, path)
+}
+
+// 在当前目录执行 git 命令并返回输出的第一行，失败时返回空字符串
+func gitOutput(ctx context.Context, args ...string) string {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
+
+// 查找当前目录下的 README 文件，未找到时返回空字符串
+func findReadme() string {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if !entry.IsDir() && (name == "readme" || strings.HasPrefix(name, "readme.")) {
+			return entry.Name()
+		}
+	}
+	return ""
+}
+
+// 读取当前目录下 README 开头的几行非空内容
+func readmeHead() []string {
+	return headLines(findReadme())
+}
+
+// 读取文件开头的几行非空内容，文件不存在时返回 nil
+func headLines(path string) []string {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() && len(lines) < readmeLines {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 程序由包管理器安装时所在路径包含的目录名，此时程序目录不适合作为默认工作目录
+var packageManagerDirs = []string{"cellar", "homebrew", "linuxbrew", "scoop", "winget", "chocolatey", "nix"}
+
+// 执行 init 子命令：交互式询问工作目录和编辑器并创建配置文件。
+// 包管理器安装时程序旁边不会有配置文件，首次运行菜单时也会自动进入该向导
+func runInit(ctx context.Context) error {
+	if fileExists(configPath) {
+		answer, err := readLine(ctx, fmt.Sprintf("配置文件 %s 已存在，是否覆盖？原配置会备份为 %s.bak (y/N): ", configPath, configFile))
+		if answer = strings.ToLower(answer); err != nil || answer != "y" && answer != "yes" {
+			fmt.Println("已取消")
+			setExitCode(exitCanceled)
+			return nil
+		}
+		if err := copyFile(configPath, configPath+".bak"); err != nil {
+			return fmt.Errorf("无法备份配置文件: %v", err)
+		}
+	}
+
+	dir, err := askProjectDir(ctx)
+	if err == io.EOF {
+		fmt.Println("已取消")
+		setExitCode(exitCanceled)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	config := &Config{ProjectDir: dir, Editor: askStarterEditor(ctx)}
+	if err := writeConfig(config); err != nil {
+		return fmt.Errorf("无法创建配置文件: %v", err)
+	}
+	fmt.Printf("已创建配置文件 %s\n", configPath)
+	fmt.Println("运行 quickstart 打开项目菜单，其他配置项（备注、快捷键、启动命令等）见 README")
+	return nil
+}
+
+// 询问工作目录，目录不存在时询问是否创建，输入结束时返回 io.EOF
+func askProjectDir(ctx context.Context) (string, error) {
+	suggested, err := os.Getwd()
+	if err != nil {
+		suggested = "."
+	}
+	for {
+		input, err := readLine(ctx, fmt.Sprintf("工作目录，即存放项目的文件夹（直接回车为 %s）: ", suggested))
+		if err != nil {
+			return "", err
+		}
+		if input == "" {
+			input = suggested
+		}
+		if input == "~" || strings.HasPrefix(input, "~/") || strings.HasPrefix(input, `~\`) {
+			if home, err := os.UserHomeDir(); err == nil {
+				input = filepath.Join(home, input[1:])
+			}
+		}
+		dir, err := filepath.Abs(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		info, err := os.Stat(dir)
+		switch {
+		case err == nil && info.IsDir():
+			return dir, nil
+		case err == nil:
+			fmt.Printf("%s 不是文件夹\n", dir)
+		case os.IsNotExist(err) && confirm(ctx, fmt.Sprintf("%s 不存在，是否创建？(Y/n): ", dir)):
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Printf("无法创建 %s: %v\n", dir, err)
+				continue
+			}
+			return dir, nil
+		case !os.IsNotExist(err):
+			fmt.Printf("无法访问 %s: %v\n", dir, err)
+		}
+	}
+}
+
+// 从已安装的编辑器中选择打开项目使用的编辑器，未检测到时使用 code
+func askStarterEditor(ctx context.Context) string {
+	var installed []string
+	for _, editor := range knownEditors {
+		if _, ok := editorCommand(editor); ok {
+			installed = append(installed, editor)
+		}
+	}
+	switch len(installed) {
+	case 0:
+		fmt.Println("未检测到已安装的编辑器，使用 code，可稍后在配置文件中修改 editor")
+		return "code"
+	case 1:
+		fmt.Printf("使用已安装的编辑器 %s\n", installed[0])
+		return installed[0]
+	}
+	fmt.Println("检测到以下已安装的编辑器：")
+	for i, editor := range installed {
+		fmt.Printf("%d. %s\n", i+1, editor)
+	}
+	for {
+		input, err := readLine(ctx, "请选择打开项目使用的编辑器（直接回车为 1）: ")
+		if err != nil || input == "" {
+			return installed[0]
+		}
+		choice, err := parseChoice(input, 1, len(installed))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return installed[choice-1]
+	}
+}
+
+// 首次创建配置文件时的默认工作目录：程序所在目录，与直接下载程序放到工作目录中的用法一致；
+// 由包管理器安装时程序目录不是项目目录，改用用户主目录
+func defaultProjectDir() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("无法获取当前执行文件的路径: %v", err)
+	}
+	if real, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = real
+	}
+	exeDir := filepath.Dir(exePath)
+	if !installedByPackageManager(exeDir) {
+		return exeDir, nil
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return home, nil
+	}
+	return exeDir, nil
+}
+
+// 判断程序目录是否由包管理器管理
+func installedByPackageManager(dir string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(strings.ToLower(dir)), "/") {
+		for _, name := range packageManagerDirs {
+			if part == name {
+				return true
+			}
+		}
+	}
+	return dir == "/usr/bin" || dir == "/usr/local/bin"
+}
+
+// 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 标准输入统一通过该 reader 读取，避免按行读取和按键读取时互相丢失已缓冲的输入。
+// 只能在 readRune 启动的 goroutine 中读取，其他地方通过 readRune 获取输入
+var stdin = bufio.NewReader(os.Stdin)
+
+// 读取一行输入并去除首尾空白，输入结束或 ctx 被取消时返回 io.EOF。
+// 逐个字符通过 readRune 读取，按键模式下被打断后未取走的字符作为这一行的开头
+func readLine(ctx context.Context, prompt string) (string, error) {
+	fmt.Print(prompt)
+	var line []rune
+	for {
+		r, err := readRune(ctx, nil)
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if r == '\n' {
+			break
+		}
+		line = append(line, r)
+	}
+	return strings.TrimSpace(string(line)), nil
+}
+
+// 按键模式下逐个读取的字符
+type runeResult struct {
+	r   rune
+	err error
+}
+
+var (
+	runeCh = make(chan runeResult)
+	// 是否有尚未取走结果的读取
+	runePending bool
+)
+
+// 读取一个字符，interrupt 收到信号时放弃等待并返回 errInterrupted，ctx 被取消时同样放弃等待，视为输入结束返回 io.EOF。
+// 未完成的读取结果留给下一次调用：同一时间最多只有一个 goroutine 读取 stdin，之后的 readRune、readLine 先取走该结果，不会丢失按键
+func readRune(ctx context.Context, interrupt <-chan struct{}) (rune, error) {
+	if !runePending {
+		runePending = true
+		go func() {
+			r, _, err := stdin.ReadRune()
+			runeCh <- runeResult{r, err}
+		}()
+	}
+	select {
+	case res := <-runeCh:
+		runePending = false
+		return res.r, res.err
+	case <-interrupt:
+		return 0, errInterrupted
+	case <-ctx.Done():
+		return 0, io.EOF
+	}
+}
+
+// 等待输入时被打断
+var errInterrupted = errors.New("输入被打断")
+
+// 读取一行输入，在终端中未输入其他内容时按下 isKey 接受的按键会立即返回该按键，无需回车。
+// 标准输入不是终端时按行读取；Ctrl+C 与 Ctrl+D 视为输入结束。
+// 在终端中等待输入时 interrupt 收到信号会放弃已输入的内容并返回 errInterrupted。
+// click 的 Count 大于 0 时开启鼠标点击，点击提示上方的列表项返回其编号
+func readInput(ctx context.Context, prompt string, isKey func(r rune) bool, interrupt <-chan struct{}, click clickList) (string, error) {
+	restore, ok := enableRawInput()
+	if !ok {
+		return readLine(ctx, prompt)
+	}
+	defer restore()
+
+	fmt.Print(prompt)
+	promptRow := 0
+	if click.Count > 0 {
+		// 开启 SGR 格式的鼠标点击上报，并查询提示所在的行，用于换算点击的列表项
+		fmt.Print("\x1b[?1000h\x1b[?1006h\x1b[6n")
+		defer fmt.Print("\x1b[?1000l\x1b[?1006l")
+	}
+	var line []rune
+	for {
+		r, err := readRune(ctx, interrupt)
+		if err != nil {
+			fmt.Println()
+			return "", err
+		}
+		if r == 0x1b {
+			seq, err := readEscape(ctx, interrupt)
+			if err != nil {
+				fmt.Println()
+				return "", err
+			}
+			if row, ok := seq.cursorRow(); ok {
+				promptRow = row
+			} else if row, ok := seq.clickRow(); ok && len(line) == 0 && promptRow > 0 {
+				if n, ok := click.item(promptRow, row); ok {
+					fmt.Println(n)
+					return strconv.Itoa(n), nil
+				}
+			}
+			continue
+		}
+		switch {
+		case r == '\r' || r == '\n':
+			fmt.Println()
+			return strings.TrimSpace(string(line)), nil
+		case r == 3 || r == 4:
+			fmt.Println()
+			return "", io.EOF
+		case r == 127 || r == '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				fmt.Print("\b \b")
+			}
+		case len(line) == 0 && isKey(r):
+			fmt.Println(string(r))
+			return string(r), nil
+		case r >= ' ':
+			line = append(line, r)
+			fmt.Print(string(r))
+		}
+	}
+}
+
+// 读取一行不回显的输入，用于输入密钥；Ctrl+C 与 Ctrl+D 视为输入结束。标准输入不是终端时按行读取
+func readSecret(ctx context.Context, prompt string) (string, error) {
+	restore, ok := enableRawInput()
+	if !ok {
+		return readLine(ctx, prompt)
+	}
+	defer restore()
+
+	fmt.Print(prompt)
+	var line []rune
+	for {
+		r, err := readRune(ctx, nil)
+		if err != nil {
+			fmt.Println()
+			return "", err
+		}
+		switch {
+		case r == 0x1b:
+			// 忽略方向键等转义序列
+			if _, err := readEscape(ctx, nil); err != nil {
+				fmt.Println()
+				return "", err
+			}
+		case r == '\r' || r == '\n':
+			fmt.Println()
+			return string(line), nil
+		case r == 3 || r == 4:
+			fmt.Println()
+			return "", io.EOF
+		case r == 127 || r == '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case r >= ' ':
+			line = append(line, r)
+		}
+	}
+}
+
+// clickList 描述提示上方紧邻的可点击列表，编号从 First 开始，共 Count 项
+type clickList struct {
+	First int
+	Count int
+}
+
+// 根据提示所在的行和点击的行换算列表项编号
+func (c clickList) item(promptRow, row int) (int, bool) {
+	offset := promptRow - row
+	if offset < 1 || offset > c.Count {
+		return 0, false
+	}
+	return c.First + c.Count - offset, true
+}
+
+// escapeSeq 为终端发送的 CSI 转义序列（ESC [ 之后的内容，含结尾字符）
+type escapeSeq string
+
+// 读取 ESC 之后的转义序列，方向键等不处理的序列同样读完后丢弃
+func readEscape(ctx context.Context, interrupt <-chan struct{}) (escapeSeq, error) {
+	r, err := readRune(ctx, interrupt)
+	if err != nil || r != '[' {
+		return "", err
+	}
+	var seq []rune
+	for {
+		r, err := readRune(ctx, interrupt)
+		if err != nil {
+			return "", err
+		}
+		seq = append(seq, r)
+		if r >= 0x40 && r <= 0x7e && r != '[' && r != '<' {
+			return escapeSeq(seq), nil
+		}
+	}
+}
+
+// 解析光标位置报告 <row>;<col>R
+func (s escapeSeq) cursorRow() (int, bool) {
+	if !strings.HasSuffix(string(s), "R") {
+		return 0, false
+	}
+	row, _, _ := strings.Cut(strings.TrimSuffix(string(s), "R"), ";")
+	n, err := strconv.Atoi(row)
+	return n, err == nil
+}
+
+// 解析 SGR 鼠标事件 <<button>;<col>;<row>M，只接受左键按下，滚轮等其他事件忽略
+func (s escapeSeq) clickRow() (int, bool) {
+	if !strings.HasPrefix(string(s), "<") || !strings.HasSuffix(string(s), "M") {
+		return 0, false
+	}
+	fields := strings.Split(strings.TrimSuffix(string(s)[1:], "M"), ";")
+	if len(fields) != 3 || fields[0] != "0" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(fields[2])
+	return n, err == nil
+}
+
+// 获取用户选择的编号
+func getUserChoice(ctx context.Context, prompt string, maxChoice int) (int, error) {
+	return readChoice(ctx, prompt, 1, maxChoice)
+}
+
+// 输入 q 表示退出，与输入结束同样处理
+const quitInput = "q"
+
+// 获取用户输入的编号，编号需在 minChoice 与 maxChoice 之间，输入 q 或输入结束时返回 io.EOF
+func readChoice(ctx context.Context, prompt string, minChoice, maxChoice int) (int, error) {
+	input, err := readLine(ctx, prompt)
+	if err != nil {
+		return 0, err
+	}
+	if input == quitInput {
+		return 0, io.EOF
+	}
+	return parseChoice(input, minChoice, maxChoice)
+}
+
+// 解析用户输入的编号，无效时返回的错误可直接显示，调用方重新提示输入即可
+func parseChoice(input string, minChoice, maxChoice int) (int, error) {
+	choice, err := strconv.Atoi(input)
+	if err != nil || choice < minChoice || choice > maxChoice {
+		return 0, fmt.Errorf("无效的选择，请输入 %d 到 %d 之间的编号，输入 q 退出。", minChoice, maxChoice)
+	}
+	return choice, nil
+}
+
+// 菜单中的功能键，项目快捷键只能是小写字母，不会与之冲突
+const (
+	sortKey    = 'S' // 切换排序方式
+	archiveKey = 'A' // 归档项目
+	eachKey    = 'M' // 在多个项目中批量执行命令
+	downKey    = 'D' // 停止所有服务
+	remarkKey  = 'E' // 编辑项目备注
+	refreshKey = 'r' // 刷新菜单，需回车确认，不能绑定为项目快捷键
+)
+
+// menuCommand 表示用户在菜单中按下了功能键
+type menuCommand rune
+
+func (c menuCommand) Error() string {
+	return fmt.Sprintf("功能键 %c", rune(c))
+}
+
+// 判断按键是否为菜单功能键
+func isMenuCommand(r rune) bool {
+	return r == sortKey || r == archiveKey || r == eachKey || r == downKey || r == remarkKey
+}
+
+// 读取项目菜单的选择，可以输入编号、项目名称或别名后回车，也可以直接按下配置的快捷键，
+// byKey 表示是否通过快捷键选择。最近启动的项目在列表中时，直接回车即启动该项目，与快捷键一样视为 byKey。
+// 按下功能键或输入 r 时返回对应的 menuCommand，输入 q 时返回 io.EOF，配置文件变动时返回 errInterrupted。listed 为列表是否紧邻提示显示，开启 mouse 时可以点击列表项选择
+func readMenuChoice(ctx context.Context, prompt string, projects []*Project, config *Config, minChoice int, listed bool) (choice int, byKey bool, err error) {
+	keys := make(map[rune]int)
+	for i, p := range projects {
+		if p.Key != 0 {
+			keys[p.Key] = i + 1
+		}
+	}
+	last := projectNumber(projects, lastLaunched())
+	if last > 0 {
+		prompt = fmt.Sprintf("%s（直接回车启动 %s）: ", strings.TrimSuffix(prompt, ": "), projects[last-1].Name)
+	}
+	var click clickList
+	if config.Mouse && listed {
+		click = clickList{First: minChoice, Count: len(projects) - minChoice + 1}
+	}
+	input, err := readInput(ctx, prompt, func(r rune) bool {
+		_, ok := keys[r]
+		return ok || isMenuCommand(r)
+	}, configChanged, click)
+	if err != nil {
+		return 0, false, err
+	}
+	switch input {
+	case "":
+		if last > 0 {
+			return last, true, nil
+		}
+	case quitInput:
+		return 0, false, io.EOF
+	case string(refreshKey):
+		return 0, false, menuCommand(refreshKey)
+	}
+	if r := []rune(input); len(r) == 1 {
+		if isMenuCommand(r[0]) {
+			return 0, false, menuCommand(r[0])
+		}
+		if choice, ok := keys[r[0]]; ok {
+			return choice, true, nil
+		}
+	}
+	if choice := projectNumber(projects, resolveAlias(config, input)); choice > 0 {
+		return choice, false, nil
+	}
+	choice, err = parseChoice(input, minChoice, len(projects))
+	return choice, false, err
+}
+
+// 获取项目绑定的快捷键，快捷键须为单个小写字母，q 和 r 保留用于退出和刷新，未绑定时返回 0
+func projectKey(config *Config, name string) rune {
+	for key, project := range config.Keys {
+		if project == name && len(key) == 1 && key[0] >= 'a' && key[0] <= 'z' && key != quitInput && key != string(refreshKey) {
+			return rune(key[0])
+		}
+	}
+	return 0
+}
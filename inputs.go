@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 本次启动时用户输入的参数，键为项目名，由 askInputs 设置。未询问过的项目使用上次输入的值或默认值
+var inputValues = make(map[string]map[string]string)
+
+// 上次输入的参数记录文件路径
+func inputsPath() string {
+	return statePath("inputs.json")
+}
+
+// 读取各项目上次输入的参数
+func savedInputs() map[string]map[string]string {
+	saved := make(map[string]map[string]string)
+	readJSONFile(inputsPath(), &saved)
+	return saved
+}
+
+// 获取项目的参数值：本次输入的值、上次输入的值或默认值
+func projectInputs(config *Config, folder string) map[string]string {
+	values := make(map[string]string)
+	project := findProject(config, folder)
+	if project == nil {
+		return values
+	}
+	saved := savedInputs()[folder]
+	for _, input := range project.Inputs {
+		values[input.Name] = input.Default
+		if v, ok := saved[input.Name]; ok {
+			values[input.Name] = v
+		}
+		if v, ok := inputValues[folder][input.Name]; ok {
+			values[input.Name] = v
+		}
+	}
+	return values
+}
+
+// 启动前依次询问项目声明的参数，直接回车使用上次输入的值或默认值，输入的值会记住供下次使用。
+// 输入 q 或输入结束时返回 false，表示取消启动
+func askInputs(ctx context.Context, config *Config, folder string) bool {
+	project := findProject(config, folder)
+	if project == nil || len(project.Inputs) == 0 {
+		return true
+	}
+	defaults := projectInputs(config, folder)
+	values := make(map[string]string)
+	for _, input := range project.Inputs {
+		value, err := askInput(ctx, input, defaults[input.Name])
+		if err == io.EOF {
+			return false
+		}
+		values[input.Name] = value
+	}
+	inputValues[folder] = values
+
+	saved := savedInputs()
+	saved[folder] = values
+	if data, err := json.MarshalIndent(saved, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(inputsPath()), 0755); err == nil {
+			os.WriteFile(inputsPath(), data, 0644)
+		}
+	}
+	return true
+}
+
+// 询问一个参数。配置了可选值时列出编号，可输入编号或值；否则接受任意输入
+func askInput(ctx context.Context, input InputConfig, current string) (string, error) {
+	prompt := input.Prompt
+	if prompt == "" {
+		prompt = input.Name
+	}
+	if len(input.Options) > 0 {
+		fmt.Printf("%s：\n", prompt)
+		for i, option := range input.Options {
+			fmt.Printf("%d. %s\n", i+1, option)
+		}
+		prompt = "请输入编号或值"
+	}
+	if current != "" {
+		prompt += "（直接回车为 " + current + "）"
+	}
+	for {
+		answer, err := readLine(ctx, prompt+": ")
+		if err == io.EOF || answer == quitInput {
+			return "", io.EOF
+		}
+		if answer == "" {
+			answer = current
+		}
+		if len(input.Options) == 0 {
+			if answer == "" {
+				fmt.Println("请输入一个值，输入 q 取消。")
+				continue
+			}
+			return answer, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(input.Options) {
+			return input.Options[n-1], nil
+		}
+		if contains(answer, input.Options) {
+			return answer, nil
+		}
+		fmt.Printf("无效的选择，可选值为 %s，输入 q 取消。\n", strings.Join(input.Options, "、"))
+	}
+}
@@ -0,0 +1,113 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Client 为守护进程的客户端，同一连接上的请求依次执行，可以在多个 goroutine 中使用
+type Client struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+// Dial 连接默认位置的守护进程
+func Dial() (*Client, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	return DialSocket(path)
+}
+
+// DialSocket 连接指定套接字上的守护进程
+func DialSocket(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接守护进程，请先运行 quickstart daemon: %v", err)
+	}
+	return &Client{conn: conn, encoder: json.NewEncoder(conn), decoder: json.NewDecoder(bufio.NewReader(conn))}, nil
+}
+
+// Close 关闭连接，正在进行的 StreamLogs 会随之结束
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// 发送请求并读取一个响应
+func (c *Client) call(req Request) (*Response, error) {
+	if err := c.encoder.Encode(req); err != nil {
+		return nil, err
+	}
+	return c.receive()
+}
+
+// 读取一个响应，守护进程返回错误时转换为 error
+func (c *Client) receive() (*Response, error) {
+	var resp Response
+	if err := c.decoder.Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// ListProjects 列出工作目录中的项目
+func (c *Client) ListProjects() ([]Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, err := c.call(Request{Type: ListProjects})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Projects, nil
+}
+
+// Launch 在后台启动项目的服务
+func (c *Client) Launch(project string) (*Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, err := c.call(Request{Type: Launch, Project: project})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Service, nil
+}
+
+// Stop 停止项目的服务
+func (c *Client) Stop(project string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.call(Request{Type: Stop, Project: project})
+	return err
+}
+
+// StreamLogs 读取项目服务的输出，每行调用一次 handle，服务退出后返回 nil。
+// 期间连接被占用，需要同时发送其他请求时应另建连接
+func (c *Client) StreamLogs(project string, handle func(LogLine)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.encoder.Encode(Request{Type: StreamLogs, Project: project}); err != nil {
+		return err
+	}
+	for {
+		resp, err := c.receive()
+		if err != nil {
+			return err
+		}
+		if resp.Done {
+			return nil
+		}
+		if resp.Log != nil {
+			handle(*resp.Log)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package ipc 定义 quickstart 守护进程（quickstart daemon）的本地控制协议，并提供 Go 客户端，
+// 供编辑器插件等程序列出项目、启动和停止服务、读取服务输出，无需解析命令行输出。
+//
+// 守护进程监听状态目录下的 Unix 套接字（Windows 10 起同样支持），连接上的每条消息为一行 JSON。
+// 客户端每发送一个 Request，守护进程返回一个 Response；StreamLogs 请求会持续返回日志行，
+// 直到服务退出（Response.Done 为 true）或连接关闭。
+package ipc
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// MessageType 为请求的类型
+type MessageType string
+
+const (
+	// ListProjects 列出工作目录中的项目及其运行状态
+	ListProjects MessageType = "ListProjects"
+	// Launch 在后台启动项目的服务，不打开编辑器
+	Launch MessageType = "Launch"
+	// Stop 停止由守护进程启动的服务
+	Stop MessageType = "Stop"
+	// StreamLogs 先返回服务最近的输出，再持续返回新的输出
+	StreamLogs MessageType = "StreamLogs"
+)
+
+// Request 为客户端发送的请求
+type Request struct {
+	Type    MessageType `json:"type"`
+	Project string      `json:"project,omitempty"`
+}
+
+// Response 为守护进程返回的响应，Error 非空表示请求失败
+type Response struct {
+	Error    string    `json:"error,omitempty"`
+	Projects []Project `json:"projects,omitempty"`
+	Service  *Service  `json:"service,omitempty"`
+	Log      *LogLine  `json:"log,omitempty"`
+	// Done 表示 StreamLogs 的服务已退出，之后不再有日志
+	Done bool `json:"done,omitempty"`
+}
+
+// Project 为工作目录中的一个项目
+type Project struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Remark  string   `json:"remark,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	// Running 为该项目是否有由守护进程启动且仍在运行的服务
+	Running bool `json:"running"`
+	// New 为项目是否最近新出现在工作目录中
+	New bool `json:"new,omitempty"`
+}
+
+// Service 为由守护进程启动的服务
+type Service struct {
+	Project string    `json:"project"`
+	Dir     string    `json:"dir"`
+	Command []string  `json:"command"`
+	Started time.Time `json:"started"`
+}
+
+// LogLine 为服务输出的一行
+type LogLine struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// 守护进程套接字的文件名
+const socketName = "daemon.sock"
+
+// StateDir 返回 quickstart 的状态目录：Windows 为 %LOCALAPPDATA%\quickstart，
+// Linux 为 $XDG_STATE_HOME/quickstart（默认 ~/.local/state/quickstart），macOS 为用户配置目录下的 quickstart/state
+func StateDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "quickstart"), nil
+		}
+	case "darwin":
+	default:
+		if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+			return filepath.Join(dir, "quickstart"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "state", "quickstart"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quickstart", "state"), nil
+}
+
+// SocketPath 返回守护进程监听的套接字路径，位于状态目录下
+func SocketPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketName), nil
+}
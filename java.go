@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+)
+
+// 判断当前目录是否为 Maven 或 Gradle 项目
+func isJavaProject() bool {
+	return fileExists("pom.xml") || fileExists("build.gradle") || fileExists("build.gradle.kts")
+}
+
+// Java 项目优先使用仓库自带的 wrapper 脚本启动 Spring Boot
+func javaActions(_ context.Context) []launchAction {
+	var actions []launchAction
+	if fileExists("pom.xml") {
+		mvn := wrapperCommand("mvnw", "mvn")
+		actions = append(actions, launchAction{Name: mvn + " spring-boot:run", Command: []string{mvn, "spring-boot:run"}})
+	}
+	if fileExists("build.gradle") || fileExists("build.gradle.kts") {
+		gradle := wrapperCommand("gradlew", "gradle")
+		actions = append(actions, launchAction{Name: gradle + " bootRun", Command: []string{gradle, "bootRun"}})
+	}
+	return actions
+}
+
+// 存在 wrapper 脚本时返回其路径，否则返回全局命令
+func wrapperCommand(wrapper, global string) string {
+	if runtime.GOOS == "windows" {
+		wrapper += ".cmd"
+		if !fileExists(wrapper) {
+			wrapper = wrapper[:len(wrapper)-len(".cmd")] + ".bat"
+		}
+	}
+	if fileExists(wrapper) {
+		return "." + string(filepath.Separator) + wrapper
+	}
+	return global
+}
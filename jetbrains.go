@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// 根据项目标志文件选择合适的 JetBrains IDE，按顺序匹配
+var jetbrainsIDEs = []struct {
+	Marker string // 项目标志文件，支持通配符
+	Name   string // Toolbox 生成的启动脚本名称
+}{
+	{"go.mod", "goland"},
+	{"composer.json", "phpstorm"},
+	{"Cargo.toml", "rustrover"},
+	{"*.csproj", "rider"},
+	{"*.sln", "rider"},
+	{"pom.xml", "idea"},
+	{"build.gradle", "idea"},
+	{"build.gradle.kts", "idea"},
+	{"pyproject.toml", "pycharm"},
+	{"requirements.txt", "pycharm"},
+	{"CMakeLists.txt", "clion"},
+	{"package.json", "webstorm"},
+}
+
+// 查找适合当前目录项目的 JetBrains IDE 启动命令，未安装对应 IDE 时退回 IntelliJ IDEA
+func jetbrainsIDE() string {
+	for _, ide := range jetbrainsIDEs {
+		if matches, _ := filepath.Glob(ide.Marker); len(matches) > 0 {
+			if command := jetbrainsLauncher(ide.Name); command != "" {
+				return command
+			}
+			break
+		}
+	}
+	return jetbrainsLauncher("idea")
+}
+
+// 查找 JetBrains IDE 的启动命令，依次尝试 PATH 中的命令和 Toolbox 的脚本目录
+func jetbrainsLauncher(name string) string {
+	names := []string{name}
+	if runtime.GOOS == "windows" {
+		names = []string{name + ".cmd", name + "64.exe", name + ".bat", name}
+	}
+	for _, n := range names {
+		if path, err := exec.LookPath(n); err == nil {
+			return path
+		}
+	}
+
+	dir := toolboxScriptsDir()
+	if dir == "" {
+		return ""
+	}
+	for _, n := range names {
+		path := filepath.Join(dir, n)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// JetBrains Toolbox 生成启动脚本的默认目录
+func toolboxScriptsDir() string {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "JetBrains", "Toolbox", "scripts")
+		}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Application Support", "JetBrains", "Toolbox", "scripts")
+		}
+	default:
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "JetBrains", "Toolbox", "scripts")
+		}
+	}
+	return ""
+}
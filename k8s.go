@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubeContextKey 为 ctx 中保存本次启动使用的 Kubernetes 上下文的键。随 ctx 传给检测到的启动方式，
+// 同一进程中启动多个项目（项目组、守护进程、恢复会话）时互不影响
+type kubeContextKey struct{}
+
+// 返回带有项目 Kubernetes 上下文的 ctx，项目和全局都未配置时原样返回
+func withKubeContext(ctx context.Context, config *Config, folder string) context.Context {
+	if name := projectKubeContext(config, folder); name != "" {
+		return context.WithValue(ctx, kubeContextKey{}, name)
+	}
+	return ctx
+}
+
+// ctx 中的 Kubernetes 上下文，为空时使用 kubeconfig 中的当前上下文
+func kubeContextFrom(ctx context.Context) string {
+	name, _ := ctx.Value(kubeContextKey{}).(string)
+	return name
+}
+
+// Kubernetes 清单所在的目录
+var kubernetesDirs = []string{"k8s", "kubernetes", "deploy"}
+
+// 判断当前目录是否为通过 skaffold、Tilt 或 Kubernetes 清单部署的项目
+func isKubernetesProject() bool {
+	return fileExists("skaffold.yaml") || fileExists("Tiltfile") || kubernetesDir() != ""
+}
+
+// 查找存放 Kubernetes 清单的目录，不存在时返回空字符串
+func kubernetesDir() string {
+	for _, dir := range kubernetesDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() && hasManifests(dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// 判断目录中是否有 YAML 清单
+func hasManifests(dir string) bool {
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// 列出 skaffold dev、tilt up 和 kubectl apply 启动方式，配置了上下文时附加对应参数
+func kubernetesActions(ctx context.Context) []launchAction {
+	kubeContext := kubeContextFrom(ctx)
+	var actions []launchAction
+	if fileExists("skaffold.yaml") {
+		actions = append(actions, kubeAction([]string{"skaffold", "dev"}, "--kube-context", kubeContext))
+	}
+	if fileExists("Tiltfile") {
+		actions = append(actions, kubeAction([]string{"tilt", "up"}, "--context", kubeContext))
+	}
+	if dir := kubernetesDir(); dir != "" {
+		command := []string{"kubectl", "apply", "-f", dir}
+		if fileExists(filepath.Join(dir, "kustomization.yaml")) || fileExists(filepath.Join(dir, "kustomization.yml")) {
+			command = []string{"kubectl", "apply", "-k", dir}
+		}
+		actions = append(actions, kubeAction(command, "--context", kubeContext))
+	}
+	return actions
+}
+
+// 生成 Kubernetes 启动方式，配置了上下文时通过 flag 指定
+func kubeAction(command []string, flag, kubeContext string) launchAction {
+	if kubeContext != "" {
+		command = append(command, flag+"="+kubeContext)
+	}
+	return launchAction{Name: strings.Join(command, " "), Command: command}
+}
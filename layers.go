@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// configLayer 为配置的一层来源。各层按优先级从低到高合并：默认值 < 全局配置 < 配置档 < 项目内的配置 < 命令行参数
+type configLayer struct {
+	Name     string                                // 来源名称，显示在 config show 中
+	Global   map[string]json.RawMessage            // 全局字段
+	Projects map[string]map[string]json.RawMessage // 各项目的字段，键为项目文件夹名
+	Order    []string                              // 项目的顺序
+	Sources  map[string]string                     // 各项目字段的来源，未填写时为 Name，如项目内配置文件的路径
+	All      map[string]json.RawMessage            // 覆盖所有已配置项目的字段，用于命令行参数
+}
+
+// layeredConfig 为合并后的配置，记录每个值来自哪一层
+type layeredConfig struct {
+	global   map[string]json.RawMessage
+	projects map[string]map[string]json.RawMessage
+	order    []string          // 项目出现的顺序
+	sources  map[string]string // 以 . 分隔的字段路径 → 来源，如 env.FOO、projects.api.command
+	layers   []string          // 已合并的各层名称
+}
+
+// 对象按字段递归合并，其他值（包括数组）整体替换
+func mergeValue(dst json.RawMessage, src json.RawMessage, path, source string, sources map[string]string) json.RawMessage {
+	var d, s map[string]json.RawMessage
+	if json.Unmarshal(dst, &d) != nil || json.Unmarshal(src, &s) != nil || d == nil || s == nil {
+		clearSources(sources, path)
+		recordSources(src, path, source, sources)
+		return src
+	}
+	for key, value := range s {
+		if old, ok := d[key]; ok {
+			d[key] = mergeValue(old, value, path+"."+key, source, sources)
+		} else {
+			d[key] = value
+			recordSources(value, path+"."+key, source, sources)
+		}
+	}
+	data, _ := json.Marshal(d)
+	return data
+}
+
+// 记录值中每个叶子字段的来源
+func recordSources(value json.RawMessage, path, source string, sources map[string]string) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(value, &obj) == nil && len(obj) > 0 {
+		for key, v := range obj {
+			recordSources(v, path+"."+key, source, sources)
+		}
+		return
+	}
+	sources[path] = source
+}
+
+// 清除被整体替换的值原有的来源
+func clearSources(sources map[string]string, path string) {
+	for key := range sources {
+		if key == path || strings.HasPrefix(key, path+".") {
+			delete(sources, key)
+		}
+	}
+}
+
+// 合并一层配置
+func (c *layeredConfig) apply(layer configLayer) {
+	c.layers = append(c.layers, layer.Name)
+	merge := func(target map[string]json.RawMessage, patch map[string]json.RawMessage, prefix, source string) {
+		for key, value := range patch {
+			path := strings.TrimPrefix(prefix+"."+key, ".")
+			if old, ok := target[key]; ok {
+				target[key] = mergeValue(old, value, path, source, c.sources)
+			} else {
+				target[key] = value
+				recordSources(value, path, source, c.sources)
+			}
+		}
+	}
+	merge(c.global, layer.Global, "", layer.Name)
+	for _, name := range layer.Order {
+		project, ok := c.projects[name]
+		if !ok {
+			project = map[string]json.RawMessage{}
+			c.projects[name] = project
+			c.order = append(c.order, name)
+		}
+		source := layer.Name
+		if s, ok := layer.Sources[name]; ok {
+			source = s
+		}
+		merge(project, layer.Projects[name], "projects."+name, source)
+	}
+	if len(layer.All) > 0 {
+		for _, name := range c.order {
+			merge(c.projects[name], layer.All, "projects."+name, layer.Name)
+		}
+	}
+}
+
+// 将合并结果解析为 Config
+func (c *layeredConfig) config() (*Config, error) {
+	global := make(map[string]json.RawMessage, len(c.global)+1)
+	for key, value := range c.global {
+		global[key] = value
+	}
+	projects := make([]map[string]json.RawMessage, 0, len(c.order))
+	for _, name := range c.order {
+		project := map[string]json.RawMessage{}
+		for key, value := range c.projects[name] {
+			project[key] = value
+		}
+		project["name"], _ = json.Marshal(name)
+		projects = append(projects, project)
+	}
+	global["projects"], _ = json.Marshal(projects)
+	data, err := json.Marshal(global)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// 内置的默认值，与各处未配置时的取值一致
+func defaultLayer() configLayer {
+	defaults := map[string]any{
+		"editor":    "code",
+		"newDays":   defaultNewDays,
+		"supervise": SuperviseConfig{MaxRetries: 5, Backoff: 1, MaxBackoff: 60},
+		"timeouts":  TimeoutConfig{Git: int(defaultGitTimeout.Seconds()), Install: int(defaultInstallTimeout.Seconds())},
+	}
+	layer := configLayer{Name: "默认值", Global: map[string]json.RawMessage{}}
+	for key, value := range defaults {
+		layer.Global[key], _ = json.Marshal(value)
+	}
+	return layer
+}
+
+// 配置文件中的全局字段和 projects 中的各项目
+func fileLayer(data []byte) (configLayer, error) {
+	layer := configLayer{Name: "全局配置", Global: map[string]json.RawMessage{}, Projects: map[string]map[string]json.RawMessage{}}
+	if err := json.Unmarshal(data, &layer.Global); err != nil {
+		return layer, err
+	}
+	if raw, ok := layer.Global["projects"]; ok {
+		var projects []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &projects); err != nil {
+			return layer, fmt.Errorf("projects 有误: %v", err)
+		}
+		for _, project := range projects {
+			var name string
+			json.Unmarshal(project["name"], &name)
+			delete(project, "name")
+			if _, ok := layer.Projects[name]; !ok {
+				layer.Order = append(layer.Order, name)
+			}
+			layer.Projects[name] = project
+		}
+		delete(layer.Global, "projects")
+	}
+	return layer, nil
+}
+
+// 生效的配置档覆盖的全局字段，hosts 只用于选择配置档
+func profileLayer(data []byte, name string) (configLayer, error) {
+	var raw struct {
+		Profiles map[string]map[string]json.RawMessage `json:"profiles"`
+	}
+	json.Unmarshal(data, &raw)
+	profile, ok := raw.Profiles[name]
+	if !ok {
+		return configLayer{}, fmt.Errorf("配置档 %s 不存在", name)
+	}
+	delete(profile, "hosts")
+	return configLayer{Name: "配置档 " + name, Global: profile}, nil
+}
+
+// 命令行 --editor 和 --env 指定的值
+var (
+	editorFlag string
+	envFlags   = map[string]string{}
+)
+
+// 命令行参数覆盖全局配置和所有项目的配置
+func flagLayer() configLayer {
+	layer := configLayer{Name: "命令行参数", Global: map[string]json.RawMessage{}}
+	if editorFlag != "" {
+		layer.Global["editor"], _ = json.Marshal(editorFlag)
+	}
+	if len(envFlags) > 0 {
+		layer.Global["env"], _ = json.Marshal(envFlags)
+	}
+	layer.All = layer.Global
+	return layer
+}
+
+// 按优先级合并各层配置：默认值 < 全局配置 < 配置档 < 项目内的 .quickstart.json < 命令行参数
+func loadLayers() (*Config, *layeredConfig, error) {
+	// readConfig 在配置文件不存在时创建默认配置
+	base, err := readConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	layered := &layeredConfig{global: map[string]json.RawMessage{}, projects: map[string]map[string]json.RawMessage{}, sources: map[string]string{}}
+	layered.apply(defaultLayer())
+	file, err := fileLayer(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	layered.apply(file)
+	profile := selectProfile(base)
+	if profile != "" {
+		layer, err := profileLayer(data, profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		layered.apply(layer)
+	}
+	// 项目内的配置需要按工作目录和子目录查找，先合并前几层
+	config, err := layered.config()
+	if err != nil {
+		return nil, nil, err
+	}
+	repo := repoLayer(config)
+	layered.apply(repo)
+	layered.apply(flagLayer())
+	if config, err = layered.config(); err != nil {
+		return nil, nil, err
+	}
+	config.ActiveProfile = profile
+	for i := range config.Projects {
+		config.Projects[i].source = repo.Sources[config.Projects[i].Name]
+	}
+	return config, layered, nil
+}
+
+// 显示配置及每个值的来源。effective 为 false 时只显示配置文件中的内容，
+// 指定项目时显示该项目生效的配置，包括从全局配置继承的 editor、env 等字段
+func runConfigShow(args []string) error {
+	effective := false
+	project := ""
+	for _, arg := range args {
+		switch {
+		case arg == "--effective":
+			effective = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("未知的参数: %s", arg)
+		default:
+			project = arg
+		}
+	}
+	config, layered, err := loadLayers()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	if project != "" {
+		c, ok, suggestions, err := matchProject(config, project)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			setExitCode(exitNotFound)
+			return fmt.Errorf("未找到项目 %s%s", project, notFoundHint(suggestions))
+		}
+		project = c.Name
+	}
+
+	leaves := layered.leaves(project)
+	if !effective {
+		// 只保留配置文件中填写的值
+		filtered := leaves[:0]
+		for _, leaf := range leaves {
+			if leaf.source == "全局配置" {
+				filtered = append(filtered, leaf)
+			}
+		}
+		leaves = filtered
+	}
+	if effective {
+		fmt.Printf("优先级：%s\n", strings.Join(layered.layers, " < "))
+	}
+	if project != "" {
+		fmt.Printf("项目 %s：\n", project)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, leaf := range leaves {
+		if effective {
+			fmt.Fprintf(w, "  %s\t%s\t← %s\n", leaf.path, leaf.value, leaf.source)
+		} else {
+			fmt.Fprintf(w, "  %s\t%s\n", leaf.path, leaf.value)
+		}
+	}
+	return w.Flush()
+}
+
+// configLeaf 为配置中的一个值
+type configLeaf struct {
+	path, value, source string
+}
+
+// 项目从全局配置继承、且项目配置中同名字段会覆盖的字段
+var inheritedKeys = []string{"editor", "env", "supervise", "pty", "idleStop", "kubeContext", "proxy"}
+
+// 列出配置中的值，按字段路径排序。未指定项目时为全局字段（不含 projects 和 profiles），
+// 指定项目时为项目生效的字段：项目配置覆盖继承的全局字段，env 和 proxy 按字段合并
+func (c *layeredConfig) leaves(project string) []configLeaf {
+	var leaves []configLeaf
+	add := func(values map[string]json.RawMessage, prefix string, keep func(key string) bool) {
+		flat := map[string]json.RawMessage{}
+		for key, value := range values {
+			if keep(key) {
+				flattenJSON(value, key, flat)
+			}
+		}
+		for path, value := range flat {
+			leaves = append(leaves, configLeaf{path: path, value: compactJSON(value), source: c.sources[strings.TrimPrefix(prefix+"."+path, ".")]})
+		}
+	}
+	if project == "" {
+		add(c.global, "", func(key string) bool { return key != "profiles" })
+	} else {
+		own := c.projects[project]
+		add(c.global, "", func(key string) bool {
+			if !contains(key, inheritedKeys) {
+				return false
+			}
+			_, overridden := own[key]
+			return key == "env" || key == "proxy" || !overridden
+		})
+		// env 中项目配置的变量覆盖全局的同名变量
+		seen := map[string]bool{}
+		add(own, "projects."+project, func(string) bool { return true })
+		for i := len(leaves) - 1; i >= 0; i-- {
+			if seen[leaves[i].path] {
+				leaves = append(leaves[:i], leaves[i+1:]...)
+				continue
+			}
+			seen[leaves[i].path] = true
+		}
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].path < leaves[j].path })
+	return leaves
+}
+
+// 将 JSON 对象展开为以 . 分隔的字段路径，数组和其他值保持原样
+func flattenJSON(value json.RawMessage, path string, out map[string]json.RawMessage) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(value, &obj) == nil && len(obj) > 0 {
+		for key, v := range obj {
+			flattenJSON(v, path+"."+key, out)
+		}
+		return
+	}
+	out[path] = value
+}
+
+// 压缩 JSON 中的空白，便于在一行中显示
+func compactJSON(value json.RawMessage) string {
+	var buf bytes.Buffer
+	if json.Compact(&buf, value) != nil {
+		return string(value)
+	}
+	return buf.String()
+}
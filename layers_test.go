@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLayersMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		profile string
+		repo    map[string]string // 项目名 → 项目内配置的 JSON
+		editor  string            // --editor
+		env     map[string]string // --env
+		check   func(t *testing.T, config *Config)
+		sources map[string]string // 部分字段路径的来源
+	}{
+		{
+			name: "未配置时使用默认值",
+			file: `{"projectDir": "/p"}`,
+			check: func(t *testing.T, config *Config) {
+				if config.Editor != "code" || config.Supervise == nil || config.Supervise.MaxRetries != 5 {
+					t.Errorf("editor = %q, supervise = %+v", config.Editor, config.Supervise)
+				}
+			},
+			sources: map[string]string{"editor": "默认值", "projectDir": "全局配置"},
+		},
+		{
+			name: "全局配置按字段覆盖默认值中的对象",
+			file: `{"editor": "vim", "supervise": {"maxRetries": 2}}`,
+			check: func(t *testing.T, config *Config) {
+				want := SuperviseConfig{MaxRetries: 2, Backoff: 1, MaxBackoff: 60}
+				if config.Editor != "vim" || *config.Supervise != want {
+					t.Errorf("editor = %q, supervise = %+v", config.Editor, *config.Supervise)
+				}
+			},
+			sources: map[string]string{"editor": "全局配置", "supervise.maxRetries": "全局配置", "supervise.backoff": "默认值"},
+		},
+		{
+			name:    "配置档覆盖全局配置，env 按变量合并",
+			file:    `{"editor": "vim", "env": {"A": "1", "B": "1"}, "profiles": {"work": {"hosts": ["h"], "editor": "idea", "env": {"B": "2"}}}}`,
+			profile: "work",
+			check: func(t *testing.T, config *Config) {
+				if config.Editor != "idea" || !reflect.DeepEqual(config.Env, map[string]string{"A": "1", "B": "2"}) {
+					t.Errorf("editor = %q, env = %v", config.Editor, config.Env)
+				}
+			},
+			sources: map[string]string{"env.A": "全局配置", "env.B": "配置档 work", "editor": "配置档 work", "hosts": ""},
+		},
+		{
+			name:    "数组整体替换",
+			file:    `{"subDir": ["a", "b"], "profiles": {"work": {"subDir": ["c"]}}}`,
+			profile: "work",
+			check: func(t *testing.T, config *Config) {
+				if !reflect.DeepEqual(config.SubDir, []string{"c"}) {
+					t.Errorf("subDir = %v", config.SubDir)
+				}
+			},
+			sources: map[string]string{"subDir": "配置档 work"},
+		},
+		{
+			name: "项目内的配置覆盖配置文件中的项目",
+			file: `{"projects": [{"name": "api", "command": "a", "env": {"X": "1"}}, {"name": "web", "command": "w"}]}`,
+			repo: map[string]string{"api": `{"command": "b", "env": {"Y": "2"}}`},
+			check: func(t *testing.T, config *Config) {
+				api, web := findProject(config, "api"), findProject(config, "web")
+				if api.Command != "b" || !reflect.DeepEqual(api.Env, map[string]string{"X": "1", "Y": "2"}) || web.Command != "w" {
+					t.Errorf("api = %+v, web = %+v", *api, *web)
+				}
+			},
+			sources: map[string]string{"projects.api.command": "/src/api/.quickstart.json", "projects.api.env.X": "全局配置", "projects.web.command": "全局配置"},
+		},
+		{
+			name:   "命令行参数覆盖全局和所有项目",
+			file:   `{"editor": "vim", "env": {"E": "g", "K": "g"}, "projects": [{"name": "api", "editor": "idea", "env": {"E": "p"}}]}`,
+			repo:   map[string]string{"api": `{"env": {"E": "r"}}`},
+			editor: "nano",
+			env:    map[string]string{"E": "f"},
+			check: func(t *testing.T, config *Config) {
+				api := findProject(config, "api")
+				if config.Editor != "nano" || api.Editor != "nano" {
+					t.Errorf("editor = %q, api.editor = %q", config.Editor, api.Editor)
+				}
+				if !reflect.DeepEqual(config.Env, map[string]string{"E": "f", "K": "g"}) || !reflect.DeepEqual(api.Env, map[string]string{"E": "f"}) {
+					t.Errorf("env = %v, api.env = %v", config.Env, api.Env)
+				}
+			},
+			sources: map[string]string{"env.E": "命令行参数", "env.K": "全局配置", "projects.api.env.E": "命令行参数", "projects.api.editor": "命令行参数"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldEditor, oldEnv := editorFlag, envFlags
+			t.Cleanup(func() { editorFlag, envFlags = oldEditor, oldEnv })
+			editorFlag, envFlags = tt.editor, tt.env
+
+			layered := &layeredConfig{global: map[string]json.RawMessage{}, projects: map[string]map[string]json.RawMessage{}, sources: map[string]string{}}
+			layered.apply(defaultLayer())
+			file, err := fileLayer([]byte(tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			layered.apply(file)
+			if tt.profile != "" {
+				profile, err := profileLayer([]byte(tt.file), tt.profile)
+				if err != nil {
+					t.Fatal(err)
+				}
+				layered.apply(profile)
+			}
+			repo := configLayer{Name: "项目配置", Projects: map[string]map[string]json.RawMessage{}, Sources: map[string]string{}}
+			for name, data := range tt.repo {
+				project := map[string]json.RawMessage{}
+				if err := json.Unmarshal([]byte(data), &project); err != nil {
+					t.Fatal(err)
+				}
+				repo.Projects[name] = project
+				repo.Order = append(repo.Order, name)
+				repo.Sources[name] = "/src/" + name + "/.quickstart.json"
+			}
+			layered.apply(repo)
+			layered.apply(flagLayer())
+
+			config, err := layered.config()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, config)
+			for path, want := range tt.sources {
+				if got := layered.sources[path]; got != want {
+					t.Errorf("sources[%s] = %q, want %q", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileLayerMissing(t *testing.T) {
+	if _, err := profileLayer([]byte(`{"profiles": {"work": {}}}`), "home"); err == nil {
+		t.Error("profileLayer() 对不存在的配置档没有返回错误")
+	}
+}
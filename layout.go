@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// 项目组窗格的布局
+const (
+	layoutColumns  = "columns"   // 左右并排
+	layoutRows     = "rows"      // 上下排列
+	layoutMainLeft = "main-left" // 第一个窗格在左，其余在右侧上下排列
+)
+
+// terminalPane 为新终端窗口中的一个窗格
+type terminalPane struct {
+	title string
+	dir   string
+	args  []string // 窗格中执行的命令及参数
+}
+
+// 在新终端窗口中按布局打开项目组：每个项目一个窗格，窗格中执行 quickstart start 并等待上一批项目就绪，
+// 之后为配置的附加窗格
+func openGroupLayout(ctx context.Context, config *Config, name string, group GroupConfig, levels [][]string) error {
+	layout := group.Layout
+	if layout == "" {
+		layout = layoutColumns
+	}
+	if layout != layoutColumns && layout != layoutRows && layout != layoutMainLeft {
+		setExitCode(exitConfigError)
+		return fmt.Errorf("项目组 %s 的布局 %s 无效，可选 columns、rows 或 main-left", name, layout)
+	}
+	panes, err := groupPanes(config, group, levels)
+	if err != nil {
+		return err
+	}
+	switch group.Terminal {
+	case "wt":
+		err = openWindowsTerminal(name, layout, panes)
+	case "tmux":
+		err = openTmux(ctx, name, layout, panes)
+	default:
+		setExitCode(exitConfigError)
+		return fmt.Errorf("项目组 %s 的终端 %s 无效，可选 wt 或 tmux", name, group.Terminal)
+	}
+	if err != nil {
+		setExitCode(exitLaunchFailed)
+		return fmt.Errorf("无法打开终端窗口: %v", err)
+	}
+	return nil
+}
+
+// 按启动顺序生成项目组的窗格
+func groupPanes(config *Config, group GroupConfig, levels [][]string) ([]terminalPane, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("无法获取当前执行文件的路径: %v", err)
+	}
+	var panes []terminalPane
+	var previous []string
+	for _, level := range levels {
+		for _, project := range level {
+			args := []string{exe}
+			if config.ActiveProfile != "" {
+				args = append(args, "--profile", config.ActiveProfile)
+			}
+			args = append(args, "start")
+			for _, dep := range previous {
+				args = append(args, "--after", dep)
+			}
+			panes = append(panes, terminalPane{
+				title: project,
+				dir:   filepath.Join(config.ProjectDir, project),
+				args:  append(args, project),
+			})
+		}
+		previous = level
+	}
+	for _, p := range group.Panes {
+		if p.Command == "" {
+			setExitCode(exitConfigError)
+			return nil, fmt.Errorf("项目组的附加窗格未填写 command")
+		}
+		pane := terminalPane{title: p.Title, dir: config.ProjectDir, args: commandArgs(config, p.Command)}
+		if pane.title == "" {
+			pane.title = p.Command
+		}
+		if p.Project != "" {
+			pane.dir = filepath.Join(config.ProjectDir, resolveAlias(config, p.Project))
+		}
+		panes = append(panes, pane)
+	}
+	return panes, nil
+}
+
+// 第 i 个窗格（从 1 开始）的拆分方向和大小，vertical 为左右拆分，size 为新窗格占被拆分窗格的比例，
+// 依次拆分最后一个窗格，使同一方向上的窗格大小相同
+func paneSplit(layout string, i, n int) (vertical bool, size float64) {
+	switch layout {
+	case layoutRows:
+		return false, float64(n-i) / float64(n-i+1)
+	case layoutMainLeft:
+		if i == 1 {
+			return true, 0.5
+		}
+		return false, float64(n-i) / float64(n-i+1)
+	}
+	return true, float64(n-i) / float64(n-i+1)
+}
+
+// 通过 wt 命令行在 Windows Terminal 的新窗口中打开窗格，子命令之间以单独的 ; 参数分隔
+func openWindowsTerminal(name, layout string, panes []terminalPane) error {
+	args := []string{"-w", "new"}
+	for i, pane := range panes {
+		if i == 0 {
+			args = append(args, "new-tab", "--title", name+" - "+pane.title)
+		} else {
+			vertical, size := paneSplit(layout, i, len(panes))
+			direction := "-H"
+			if vertical {
+				direction = "-V"
+			}
+			args = append(args, ";", "split-pane", direction, "-s", fmt.Sprintf("%.2f", size), "--title", pane.title)
+		}
+		args = append(args, "-d", pane.dir)
+		for _, arg := range pane.args {
+			// 参数中的 ; 会被 wt 视为子命令分隔符
+			args = append(args, strings.ReplaceAll(arg, ";", `\;`))
+		}
+	}
+	args = append(args, ";", "move-focus", "first")
+	return exec.Command("wt", args...).Run()
+}
+
+// 在 tmux 中打开窗格：已在 tmux 中时新建窗口，否则新建会话并连接，会话已存在时直接连接
+func openTmux(ctx context.Context, name, layout string, panes []terminalPane) error {
+	session := "quickstart-" + name
+	inside := os.Getenv("TMUX") != ""
+	if !inside {
+		if exec.Command("tmux", "has-session", "-t", "="+session).Run() == nil {
+			fmt.Printf("tmux 会话 %s 已存在，直接连接\n", session)
+			return attachTmux(session)
+		}
+	}
+
+	var window string
+	for i, pane := range panes {
+		var args []string
+		switch {
+		case i > 0:
+			vertical, size := paneSplit(layout, i, len(panes))
+			direction := "-v"
+			if vertical {
+				direction = "-h"
+			}
+			args = []string{"split-window", direction, "-l", fmt.Sprintf("%d%%", int(size*100)), "-t", window}
+		case inside:
+			args = []string{"new-window", "-n", name}
+		default:
+			args = []string{"new-session", "-d", "-s", session, "-n", name}
+		}
+		args = append(args, "-P", "-F", "#{window_id} #{pane_id}", "-c", pane.dir, tmuxCommand(pane.args))
+		out, err := queryOutput(ctx, "tmux", args...)
+		if err != nil {
+			return err
+		}
+		ids := strings.Fields(string(out))
+		if len(ids) != 2 {
+			return fmt.Errorf("无法识别 tmux 的输出: %s", out)
+		}
+		if i == 0 {
+			window = ids[0]
+			// 服务退出后保留窗格，便于查看输出
+			queryOutput(ctx, "tmux", "set-option", "-w", "-t", window, "remain-on-exit", "on")
+			queryOutput(ctx, "tmux", "set-option", "-w", "-t", window, "pane-border-status", "top")
+		}
+		queryOutput(ctx, "tmux", "select-pane", "-t", ids[1], "-T", pane.title)
+	}
+	// main-left 的右侧窗格已按顺序拆分，其余布局使用 tmux 内置布局均分
+	switch layout {
+	case layoutColumns:
+		queryOutput(ctx, "tmux", "select-layout", "-t", window, "even-horizontal")
+	case layoutRows:
+		queryOutput(ctx, "tmux", "select-layout", "-t", window, "even-vertical")
+	}
+	queryOutput(ctx, "tmux", "select-pane", "-t", window+".{top-left}")
+	if inside {
+		return nil
+	}
+	return attachTmux(session)
+}
+
+// 将 tmux 窗格中执行的命令及参数转换为 shell 命令
+func tmuxCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// 在当前终端中连接 tmux 会话
+func attachTmux(session string) error {
+	cmd := exec.Command("tmux", "attach-session", "-t", "="+session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+)
+
+// 随程序发布的许可证全文，包管理器安装时程序旁边没有 LICENSE 文件
+//
+//go:embed LICENSE
+var licenseText string
+
+// 许可证的 SPDX 标识
+const licenseID = "Apache-2.0"
+
+// 打印许可证全文
+func printLicense() {
+	fmt.Print(licenseText)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// alfredItem 为 Alfred Script Filter 的结果项
+type alfredItem struct {
+	UID          string `json:"uid"`
+	Title        string `json:"title"`
+	Subtitle     string `json:"subtitle"`
+	Arg          string `json:"arg"`
+	Autocomplete string `json:"autocomplete"`
+	Match        string `json:"match"`
+}
+
+// 执行 list 子命令，按菜单的排序列出项目，供 Alfred、Raycast、PowerToys Run 等启动器调用 quickstart <项目>。
+// --format 为 text（每行一个项目名，默认）、json 或 alfred（Script Filter JSON）
+func runListCommand(args []string) error {
+	format := "text"
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--format" && i+1 < len(args):
+			i++
+			format = args[i]
+		case strings.HasPrefix(args[i], "--format="):
+			format = strings.TrimPrefix(args[i], "--format=")
+		default:
+			return fmt.Errorf("用法: quickstart list [--format=text|json|alfred]")
+		}
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	items, err := discoverProjects(config)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "text":
+		for _, item := range items {
+			fmt.Println(item.Name)
+		}
+		return nil
+	case "json":
+		return writeJSON(items)
+	case "alfred":
+		result := struct {
+			Items []alfredItem `json:"items"`
+		}{Items: make([]alfredItem, 0, len(items))}
+		for _, item := range items {
+			subtitle := item.Path
+			if item.Remark != "" {
+				subtitle = item.Remark + " · " + item.Path
+			}
+			result.Items = append(result.Items, alfredItem{
+				UID:          item.Name,
+				Title:        item.Name,
+				Subtitle:     subtitle,
+				Arg:          item.Name,
+				Autocomplete: item.Name,
+				Match:        strings.Join(append([]string{item.Name}, item.Aliases...), " "),
+			})
+		}
+		return writeJSON(result)
+	default:
+		return fmt.Errorf("不支持的输出格式: %s，可选 text、json、alfred", format)
+	}
+}
+
+// 以缩进的 JSON 输出到标准输出
+func writeJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 启动器日志超过该大小时轮换，保留一份旧日志
+const launcherLogMaxSize = 1 << 20
+
+// 服务失败时记录的最近输出行数
+const failureTailLines = 200
+
+var launcherLogMu sync.Mutex
+
+// 启动器日志的位置，记录服务的启动、退出和错误，供 quickstart report 打包
+func launcherLogPath() string {
+	return statePath("logs", "quickstart.log")
+}
+
+// 最近一次失败的服务命令及其输出的位置
+func lastFailurePath() string {
+	return statePath("logs", "last-failure.log")
+}
+
+// 向启动器日志追加一行，写入失败时忽略，不影响正常使用
+func logf(format string, args ...any) {
+	launcherLogMu.Lock()
+	defer launcherLogMu.Unlock()
+	path := launcherLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > launcherLogMaxSize {
+		os.Rename(path, path+".1")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintf(file, "%s [%d] %s\n", time.Now().Format("2006-01-02 15:04:05"), os.Getpid(), fmt.Sprintf(format, args...))
+}
+
+// outputTail 保留服务最近的输出行，服务失败时写入 last-failure.log
+type outputTail struct {
+	mu      sync.Mutex
+	lines   []string
+	partial []byte
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.partial = append(t.partial, p...)
+	for {
+		i := bytes.IndexByte(t.partial, '\n')
+		if i < 0 {
+			break
+		}
+		t.lines = append(t.lines, string(bytes.TrimRight(t.partial[:i], "\r")))
+		t.partial = t.partial[i+1:]
+		if len(t.lines) > failureTailLines {
+			t.lines = t.lines[len(t.lines)-failureTailLines:]
+		}
+	}
+	return len(p), nil
+}
+
+// 获取记录的输出，包括末尾没有换行的内容
+func (t *outputTail) text() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	text := strings.Join(t.lines, "\n")
+	if len(t.partial) > 0 {
+		text += "\n" + string(t.partial)
+	}
+	return text
+}
+
+// 记录服务失败：写入启动器日志，并将命令和最近的输出写入 last-failure.log，覆盖上一次的记录
+func recordFailure(s *service, err error) {
+	logf("%s 失败: %v", s.label(), err)
+	var b strings.Builder
+	fmt.Fprintf(&b, "时间: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "项目: %s\n", s.label())
+	fmt.Fprintf(&b, "目录: %s\n", s.Dir)
+	fmt.Fprintf(&b, "命令: %s\n", strings.Join(s.Command, " "))
+	fmt.Fprintf(&b, "错误: %v\n\n", err)
+	if s.captured {
+		fmt.Fprintf(&b, "最近 %d 行输出:\n%s\n", failureTailLines, s.tail.text())
+	} else {
+		b.WriteString("输出直接显示在终端中，未记录。开启 pty 或在多服务视图中运行时可记录输出\n")
+	}
+	path := lastFailurePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(b.String()), 0644)
+}
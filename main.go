@@ -1,63 +1,177 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 )
 
-const configFile = "config.json"
-
-// Config 结构体用于存储配置信息
-type Config struct {
-	ProjectDir string   `json:"projectDir"`
-	SubDir     []string `json:"subDir"`
-	Remarks    []struct {
-		Name   string `json:"name"`
-		Remark string `json:"remark"`
-	} `json:"remarks"`
+func main() {
+	os.Exit(run())
 }
 
-func main() {
-	config, err := readConfig()
+// 运行程序并返回退出码。发生 panic 时记录到启动器日志并显示简短的说明，不在菜单上输出调用栈
+func run() (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			code = reportPanic(r)
+		}
+	}()
+	consoleVT = initConsole()
+	// 子命令、菜单和启动流程共用的上下文，收到 SIGTERM 或未被前台命令处理的 Ctrl+C 时取消，
+	// 结束正在等待的提示、查询命令和服务
+	ctx, stop := rootContext()
+	defer stop()
+	defer func() {
+		if ctx.Err() != nil && code == 0 {
+			code = exitCanceled
+		}
+	}()
+	args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitConfigError
+	}
+	if quietFlag {
+		if err := enableQuiet(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitConfigError
+		}
+	}
+	if err := initPaths(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitConfigError
+	}
+	if len(args) > 0 {
+		if err := runSubcommand(ctx, args); err != nil {
+			logf("quickstart %s: %v", strings.Join(args, " "), err)
+			fmt.Fprintln(os.Stderr, err)
+			return errorExitCode()
+		}
+		return int(exitCode.Load())
+	}
+
+	// 首次在终端中运行时通过向导创建配置文件，而不是直接使用程序所在目录
+	if !fileExists(configPath) && isTerminal(os.Stdin) {
+		fmt.Println("未找到配置文件，开始创建")
+		if err := runInit(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitConfigError
+		}
+		if !fileExists(configPath) {
+			return int(exitCode.Load())
+		}
+	}
+	config, err := loadConfig()
 	if err != nil {
-		fmt.Println("无法读取配置文件:", err)
-		return
+		fmt.Fprintln(os.Stderr, "无法读取配置文件:", err)
+		return exitConfigError
 	}
 
-	if err := runProjectMenu(config.ProjectDir, config.SubDir, config.Remarks); err != nil {
-		fmt.Println("程序异常:", err)
+	if err := runProjectMenu(ctx, config); err != nil {
+		logf("程序异常: %v", err)
+		fmt.Fprintln(os.Stderr, "程序异常:", err)
+		return errorExitCode()
 	}
+	return int(exitCode.Load())
 }
 
-func runProjectMenu(projectDir string, subDirs []string, remarks []struct {
-	Name   string `json:"name"`
-	Remark string `json:"remark"`
-}) error {
-	// 读取项目目录下的文件夹列表
-	folders, err := listFolders(projectDir, subDirs)
+func runProjectMenu(ctx context.Context, config *Config) error {
+	projects, err := projectFolders(config)
 	if err != nil {
-		return fmt.Errorf("无法读取文件夹: %v", err)
-	}
-	// 切换到项目目录
-	if err := os.Chdir(projectDir); err != nil {
 		return err
 	}
 
-	// 循环显示文件夹列表，直到用户选择成功或者主动退出
+	// 没有服务在运行时，提供恢复上次会话的选项
+	sess := restorableSession()
+	minChoice := 1
+	if sess != nil {
+		minChoice = 0
+	}
+
+	// 循环显示文件夹列表，直到用户选择成功或者主动退出，配置文件变动时重新读取并刷新菜单
+	menuSort = config.Sort
+	watchConfig()
+	if config.WatchProjectDir {
+		watchProjectDir(config)
+	}
+	redraw := true
 	for {
-		printFolderList(folders, subDirs, remarks)
-		choice, err := getUserChoice(len(folders))
+		if redraw {
+			sortProjects(config.ProjectDir, projects, config, menuSort)
+			printMenuHeader(ctx, config)
+			fmt.Println("启动项目：")
+			printSortMode()
+			if sess != nil {
+				fmt.Printf("0. 恢复上次会话（%s）\n", sess.summary())
+			}
+			printFolderList(ctx, config.ProjectDir, projects, config)
+		}
+		listed := redraw
+		redraw = true
+		choice, byKey, err := readMenuChoice(ctx, "请输入要运行的文件夹编号: ", projects, config, minChoice, listed)
+		if err == io.EOF {
+			return nil
+		}
+		if cmd, ok := err.(menuCommand); ok && cmd == refreshKey || err == errInterrupted {
+			reloadConfig(config)
+			if projects, err = projectFolders(config); err != nil {
+				return err
+			}
+			clearScreen()
+			continue
+		}
+		if cmd, ok := err.(menuCommand); ok {
+			switch cmd {
+			case sortKey:
+				menuSort = nextSortMode(menuSort, config)
+			case archiveKey:
+				if err := archiveFromMenu(ctx, config, projects); err != nil {
+					fmt.Println(err)
+				}
+			case eachKey:
+				if err := eachFromMenu(ctx, config, projects); err != nil {
+					fmt.Println(err)
+				}
+			case remarkKey:
+				if err := editRemarkFromMenu(ctx, config, projects); err != nil {
+					fmt.Println(err)
+				}
+			case downKey:
+				if answer, _ := readLine(ctx, "停止所有启动器、守护进程运行的服务和启动的依赖服务？(y/N): "); strings.ToLower(answer) == "y" || strings.ToLower(answer) == "yes" {
+					if err := runDown(ctx); err != nil {
+						fmt.Println(err)
+					}
+					readLine(ctx, "按回车返回菜单")
+				}
+			}
+			// 归档、编辑备注等操作会修改项目，重新构建项目并回到工作目录
+			if cmd != sortKey {
+				if projects, err = projectFolders(config); err != nil {
+					return err
+				}
+			}
+			clearScreen()
+			continue
+		}
 		if err != nil {
+			// 输入无效时只重新提示，不重新显示列表
 			fmt.Println(err)
+			redraw = false
 			continue
 		}
-		selectedFolder := folders[choice-1].Name()
-		if err := runCommand(selectedFolder, subDirs, remarks); err != nil {
+		if choice == 0 {
+			restoreSession(ctx, config, sess)
+			break
+		}
+		if err := runCommand(ctx, projects[choice-1], config, !byKey); err != nil {
+			setExitCode(exitLaunchFailed)
 			return fmt.Errorf("无法执行命令: %v", err)
 		}
 		break
@@ -66,56 +180,82 @@ func runProjectMenu(projectDir string, subDirs []string, remarks []struct {
 	return nil
 }
 
-func readConfig() (*Config, error) {
-	// 检测配置文件是否存在
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		// 如果配置文件不存在，则创建一个默认的配置文件,路径为程序所在目录
-		exePath, err := os.Executable()
-		if err != nil {
-			return nil, fmt.Errorf("无法获取当前执行文件的路径: %v", err)
-		}
-		exeDir := filepath.Dir(exePath)
-
-		defaultConfig := &Config{
-			ProjectDir: exeDir,
-			SubDir:     nil, // 默认为空
-		}
-		// 创建并写入配置文件
-		if err := writeConfig(defaultConfig); err != nil {
-			return nil, fmt.Errorf("无法创建配置文件: %v", err)
-		}
-		return defaultConfig, nil
-	}
-
-	// 读取配置文件
-	file, err := os.Open(configFile)
+// 列出工作目录下未归档的项目和子目录（含远程项目）并切换到项目目录，用于菜单和按名称启动
+func projectFolders(config *Config) ([]*Project, error) {
+	projects, err := menuProjects(config)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	// 解析配置文件内容到 Config 结构体
-	var config Config
-	err = json.NewDecoder(file).Decode(&config)
-	if err != nil {
+	if err := os.Chdir(config.ProjectDir); err != nil {
 		return nil, err
 	}
+	return projects, nil
+}
 
-	return &config, nil
+// 读取项目目录下未归档的文件夹列表（含远程项目），不切换目录
+func activeFolders(config *Config) ([]os.DirEntry, error) {
+	folders, err := listFolders(config.ProjectDir, config.SubDir)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取文件夹: %v", err)
+	}
+	folders = append(folders, remoteEntries(config)...)
+	// 隐藏已归档的项目，归档目录位于工作目录下时同样隐藏
+	active := folders[:0]
+	for _, folder := range folders {
+		if !contains(folder.Name(), config.Archived) && filepath.Join(config.ProjectDir, folder.Name()) != archiveDir(config) {
+			active = append(active, folder)
+		}
+	}
+	return active, nil
 }
 
-func writeConfig(config *Config) error {
-	// 创建配置文件
-	file, err := os.Create(configFile)
+// 不经菜单直接启动指定名称或别名的项目。写为 <项目>@<分支> 时先切换到该分支，与工作目录下的项目名相同时仍视为项目名
+func runProjectByName(ctx context.Context, config *Config, name string) error {
+	projects, err := projectFolders(config)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	// 编码配置信息并写入配置文件
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(config)
+	branch := ""
+	if i := strings.LastIndex(name, "@"); i > 0 && i < len(name)-1 && findDiscovered(projects, resolveAlias(config, name)) == nil {
+		name, branch = name[:i], name[i+1:]
+	}
+	project := findDiscovered(projects, resolveAlias(config, name))
+	if project == nil {
+		// 不是工作目录下的项目时按路径在子目录中查找
+		c, ok, suggestions, err := matchProject(config, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			setExitCode(exitNotFound)
+			return fmt.Errorf("未知命令或项目: %s%s", name, notFoundHint(suggestions))
+		}
+		if err := c.enter(config); err != nil {
+			return err
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		project = newProjectIndex(config).project(config, dir, c.Name)
+	}
+	if branch != "" {
+		fmt.Printf("正在启动项目：%s@%s\n", project.Name, branch)
+		if !checkFocus(ctx, config, project.Name) {
+			setExitCode(exitCanceled)
+			return nil
+		}
+		if err := launchBranch(ctx, config, project.Name, branch); err != nil {
+			setExitCode(exitLaunchFailed)
+			return err
+		}
+		return nil
+	}
+	if err := runCommand(ctx, project, config, false); err != nil {
+		setExitCode(exitLaunchFailed)
+		return err
+	}
+	return nil
 }
 
 // 获取指定目录下的文件夹列表，将子目录置顶
@@ -135,16 +275,25 @@ func listFolders(dir string, subDirs []string) ([]os.DirEntry, error) {
 		subDirNames[subDir] = true
 	}
 
-	// 首先将子目录加入 folders
+	// 过滤出文件夹，符号链接和目录联接指向文件夹时同样视为文件夹，程序自身的状态目录不作为项目
+	seen := make(map[string]bool)
+	var dirs []os.DirEntry
 	for _, entry := range entries {
-		if entry.IsDir() && subDirNames[entry.Name()] {
+		if isFolder(dir, entry, seen) && !isStateDir(filepath.Join(dir, entry.Name())) {
+			dirs = append(dirs, entry)
+		}
+	}
+
+	// 首先将子目录加入 folders
+	for _, entry := range dirs {
+		if subDirNames[entry.Name()] {
 			folders = append(folders, entry)
 		}
 	}
 
 	// 将除子目录外的其他文件夹加入 folders
-	for _, entry := range entries {
-		if entry.IsDir() && !subDirNames[entry.Name()] {
+	for _, entry := range dirs {
+		if !subDirNames[entry.Name()] {
 			folders = append(folders, entry)
 		}
 	}
@@ -152,68 +301,166 @@ func listFolders(dir string, subDirs []string) ([]os.DirEntry, error) {
 	return folders, nil
 }
 
-// 打印文件夹列表，如果是子目录，添加*号标记，如果有备注，显示备注
-func printFolderList(folders []os.DirEntry, subDirs []string, remarks []struct {
-	Name   string `json:"name"`
-	Remark string `json:"remark"`
-}) {
-	fmt.Println("启动项目：")
-	for i, folder := range folders {
-		folderName := folder.Name()
+// 判断目录项是否为文件夹。符号链接（Windows 下还包括目录联接）会解析到真实路径，
+// 指向当前目录或其上级目录的链接会造成循环，指向同一目标的链接只保留第一个
+func isFolder(dir string, entry os.DirEntry, seen map[string]bool) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&(os.ModeSymlink|os.ModeIrregular) == 0 {
+		return false
+	}
+
+	path := filepath.Join(dir, entry.Name())
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	target, _ = filepath.Abs(target)
+
+	// 链接指向当前目录或上级目录时跳过，避免进入子目录时无限循环
+	if realDir, err := filepath.EvalSymlinks(dir); err == nil {
+		realDir, _ = filepath.Abs(realDir)
+		if isSubPath(target, realDir) {
+			return false
+		}
+	}
+	if seen[target] {
+		return false
+	}
+	seen[target] = true
+	return true
+}
+
+// 判断目录是否为程序的状态目录，配置文件位于工作目录中时状态目录会出现在文件夹列表里
+func isStateDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	state, err := os.Stat(statePath())
+	return err == nil && os.SameFile(info, state)
+}
+
+// 判断 child 是否等于 parent 或位于 parent 之下
+func isSubPath(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// 打印文件夹列表，如果是子目录，添加*号标记，如果有备注，按备注的颜色显示备注，置顶的项目添加★号，绑定了快捷键时显示快捷键，
+// 开启 showActivity 时显示最近活动时间，脚本定义了 badge 钩子时显示其返回的状态，git worktree 缩进在主仓库之下并显示分支
+func printFolderList(ctx context.Context, dir string, projects []*Project, config *Config) {
+	var activity map[string]time.Time
+	if config.ShowActivity {
+		activity = folderActivity(ctx, dir, projects)
+	}
+	dups := duplicateNames(config)
+	worktrees := groupWorktrees(dir, projects)
+	parent := "./"
+	if rel, err := filepath.Rel(config.ProjectDir, dir); err == nil && rel != "." {
+		parent = filepath.ToSlash(rel) + "/"
+	}
+	for i, p := range projects {
+		folderName := p.Name
 		remark := ""
-		for _, r := range remarks {
-			if r.Name == folderName {
-				remark = fmt.Sprintf("  [%s]", r.Remark)
-				break
+		if p.Remark != "" {
+			remark = fmt.Sprintf("  [%s]", p.Remark)
+			if code, ok := remarkColors[p.Color]; ok && consoleVT {
+				remark = fmt.Sprintf("  \x1b[%sm[%s]\x1b[0m", code, p.Remark)
 			}
 		}
-		if contains(folderName, subDirs) {
+		if p.SubDir {
 			folderName += "*"
 		}
+		if dups[p.Name] {
+			// 与其他子目录中的项目同名时显示所在目录，只用于显示，查找备注等配置时使用目录名
+			folderName += "  (" + parent + ")"
+		}
+		if p.Config != nil && p.Config.Remote != "" {
+			folderName += "  ⇄ " + p.Config.Remote
+		} else if p.Config != nil && p.Config.WSL != "" {
+			folderName += "  ⇄ wsl:" + p.Config.WSL
+		}
+		if wt, ok := worktrees[p.Name]; ok && wt.Branch != "" {
+			folderName += "  ⎇ " + wt.Branch
+		}
+		if p.Pinned {
+			folderName = "★ " + folderName
+		}
+		if p.Key != 0 {
+			folderName = fmt.Sprintf("(%c) %s", p.Key, folderName)
+		}
+		if worktrees[p.Name].Main != "" {
+			// worktree 缩进显示在主仓库之下
+			folderName = "  ↳ " + folderName
+		}
+		if t, ok := activity[p.Name]; ok {
+			remark += "  · " + relativeTime(t)
+		}
+		if p.New {
+			remark += "  ✦ 新"
+		}
+		if badge := scriptBadge(config, p.Name); badge != "" {
+			remark += "  " + badge
+		}
 		fmt.Printf("%d. %s%s\n", i+1, folderName, remark)
 	}
 }
 
-// 获取用户选择的文件夹编号
-func getUserChoice(maxChoice int) (int, error) {
-	var choice int
-	fmt.Print("请输入要运行的文件夹编号: ")
-	_, err := fmt.Scanln(&choice)
-	if err != nil || choice < 1 || choice > maxChoice {
-		clearScreen()
-		return 0, fmt.Errorf("无效的选择，请重新输入。")
+// 查找项目的备注配置，未配置时返回 nil
+func findRemark(config *Config, name string) *Remark {
+	for i := range config.Remarks {
+		if config.Remarks[i].Name == name {
+			return &config.Remarks[i]
+		}
 	}
-	return choice, nil
+	return nil
 }
 
-// 进入项目目录并打印目录下的文件夹列表
-func runCommand(folder string, subDirs []string, remarks []struct {
-	Name   string `json:"name"`
-	Remark string `json:"remark"`
-}) error {
+// 备注颜色对应的 ANSI 颜色代码
+var remarkColors = map[string]string{
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+}
+
+// 进入项目目录并打印目录下的文件夹列表。showActions 为是否先显示操作菜单，否则直接打开编辑器并启动服务
+func runCommand(ctx context.Context, p *Project, config *Config, showActions bool) error {
+	folder := p.Name
 	fmt.Printf("正在启动项目：%s\n", folder)
+	if !p.SubDir && !checkFocus(ctx, config, folder) {
+		setExitCode(exitCanceled)
+		return nil
+	}
+	if p.Config != nil && p.Config.Remote != "" {
+		recordLaunch(folder)
+		return launchRemote(ctx, p.Config, config)
+	}
+	if p.Config != nil && p.Config.WSL != "" {
+		recordLaunch(folder)
+		return launchWSL(ctx, p.Config, config)
+	}
 	// 切换到指定文件夹
-	err := os.Chdir(folder)
+	err := os.Chdir(p.Path)
 	if err != nil {
 		return err
 	}
-	// 判断当前目录是否为子目录
-	isSubDir := false
-	for _, subDir := range subDirs {
-		if subDir == folder {
-			isSubDir = true
-			break
-		}
-	}
-
-	if isSubDir {
-		//获取子目录路径
-		dir, err := os.Getwd()
-		if err != nil {
-			fmt.Println("无法获取子目录路径:", err)
-		}
+	projectPath, _ := os.Getwd()
 
-		// 打印目录下的文件夹列表
+	if p.SubDir {
+		// 打印子目录下的文件夹列表
+		dir := p.Path
 		folders, err := listFolders(dir, nil)
 		if err != nil {
 			return err
@@ -222,49 +469,67 @@ func runCommand(folder string, subDirs []string, remarks []struct {
 			fmt.Println("项目目录下没有任何文件夹。")
 			return nil
 		}
+		projects := listProjects(config, dir, folders)
 		clearScreen()
-		printFolderList(folders, subDirs, remarks)
-
+		redraw := true
 		for {
-			choice, err := getUserChoice(len(folders))
+			if redraw {
+				sortProjects(dir, projects, config, menuSort)
+				fmt.Println("启动项目：")
+				printSortMode()
+				printFolderList(ctx, dir, projects, config)
+			}
+			listed := redraw
+			redraw = true
+			choice, byKey, err := readMenuChoice(ctx, "请输入要运行的文件夹编号: ", projects, config, 1, listed)
+			if err == io.EOF {
+				return nil
+			}
+			if cmd, ok := err.(menuCommand); ok && cmd == refreshKey || err == errInterrupted {
+				reloadConfig(config)
+				if folders, err = listFolders(dir, nil); err != nil {
+					return err
+				}
+				projects = listProjects(config, dir, folders)
+				clearScreen()
+				continue
+			}
+			if cmd, ok := err.(menuCommand); ok {
+				// 子目录中只支持切换排序，归档仅针对工作目录下的项目
+				if cmd == sortKey {
+					menuSort = nextSortMode(menuSort, config)
+				}
+				clearScreen()
+				continue
+			}
 			if err != nil {
 				fmt.Println(err)
+				redraw = false
 				continue
 			}
-			selectedFolder := folders[choice-1].Name()
-			if err := runCommand(selectedFolder, subDirs, remarks); err != nil {
+			if err := runCommand(ctx, projects[choice-1], config, !byKey); err != nil {
 				return fmt.Errorf("无法执行命令: %v", err)
 			}
 			break
 		}
 	} else {
-		cmd := exec.Command("code", ".")
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			return err
+		if !showActions {
+			return projectActions[0].Run(ctx, config, folder, projectPath)
 		}
-
-		// 检测是否为 WEB 项目
-		if _, err := os.Stat("package.json"); err == nil {
-			fmt.Printf("检测到 %s 为 WEB 项目\n", folder)
-			fmt.Println("5秒后启动 web 服务，Ctrl+C 停止")
-			time.Sleep(5 * time.Second)
-			cmd := exec.Command("npm", "run", "serve")
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			if err := cmd.Run(); err != nil {
-				fmt.Println("无法启动 web 服务:", err)
+		for {
+			action, err := chooseProjectAction(ctx, config, folder)
+			if err == io.EOF {
+				return nil
+			}
+			err = action.Run(ctx, config, folder, projectPath)
+			if !action.Stay {
+				return err
 			}
-		} else if _, err := os.Stat("webman"); err == nil {
-			fmt.Printf("检测到 %s 为 webman 项目\n", folder)
-			fmt.Println("5秒后启动 webman 服务，Ctrl+C 停止")
-			time.Sleep(5 * time.Second)
-			cmd := exec.Command("cmd", "/c", "windows.bat")
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			if err := cmd.Run(); err != nil {
-				fmt.Println("无法启动 webman 服务:", err)
+			if err == errProjectDeleted {
+				return nil
+			}
+			if err != nil {
+				fmt.Println(err)
 			}
 		}
 	}
@@ -272,10 +537,15 @@ func runCommand(folder string, subDirs []string, remarks []struct {
 	return nil
 }
 
+// 控制台是否支持 ANSI 转义序列，在程序启动时确定
+var consoleVT bool
+
 // 清屏
 func clearScreen() {
-	// 判断操作系统类型，清屏命令不同
-	if runtime.GOOS == "windows" {
+	// 判断操作系统类型，清屏命令不同；Windows 控制台支持转义序列时直接输出，无需启动 cmd
+	if runtime.GOOS == "windows" && consoleVT {
+		fmt.Print("\x1b[H\x1b[2J\x1b[3J")
+	} else if runtime.GOOS == "windows" {
 		cmd := exec.Command("cmd", "/c", "cls")
 		cmd.Stdout = os.Stdout
 		cmd.Run()
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 行内 Markdown 语法
+var (
+	mdImage  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	mdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	mdStrike = regexp.MustCompile(`~~([^~]+)~~`)
+	mdTag    = regexp.MustCompile(`<[^>]*>`)
+	mdANSI   = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// 块级 Markdown 语法
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRule      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdList      = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdTask      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdTableRule = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdSetext    = regexp.MustCompile(`^\s*(=+|-+)\s*$`)
+)
+
+// 为文本加上 ANSI 样式，控制台不支持转义序列时原样返回
+func ansi(code, s string) string {
+	if !consoleVT || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// 将 Markdown 转换为带 ANSI 样式的终端文本，支持标题、列表、引用、代码块、分隔线和常见的行内语法，
+// HTML 标签去掉后只保留文字，表格按原样显示
+func renderMarkdown(src string) []string {
+	var out []string
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			out = append(out, ansi("2", "  │ ")+ansi("33", line))
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			if lang := strings.TrimSpace(trimmed[3:]); lang != "" {
+				out = append(out, ansi("2", "  ┌ "+lang))
+			}
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			out = append(out, renderHeading(len(m[1]), renderInline(m[2])))
+		case trimmed != "" && i+1 < len(lines) && mdSetext.MatchString(lines[i+1]) && !mdList.MatchString(line):
+			level := 1
+			if strings.HasPrefix(strings.TrimSpace(lines[i+1]), "-") {
+				level = 2
+			}
+			out = append(out, renderHeading(level, renderInline(trimmed)))
+			i++
+		case mdRule.MatchString(line):
+			out = append(out, ansi("2", strings.Repeat("─", 40)))
+		case strings.HasPrefix(trimmed, ">"):
+			out = append(out, ansi("2", "│ ")+renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		case mdList.MatchString(line):
+			m := mdList.FindStringSubmatch(line)
+			bullet := "• "
+			item := m[2]
+			if t := mdTask.FindStringSubmatch(item); t != nil {
+				bullet = "☐ "
+				if t[1] != " " {
+					bullet = "☑ "
+				}
+				item = item[len(t[0]):]
+			}
+			out = append(out, m[1]+ansi("36", bullet)+renderInline(item))
+		case strings.HasPrefix(trimmed, "|") && mdTableRule.MatchString(line):
+			out = append(out, ansi("2", strings.Repeat("─", displayWidth(line))))
+		default:
+			text := renderInline(line)
+			// 只有 HTML 标签的行（如居中的徽章）去掉后为空，不保留空行
+			if strings.TrimSpace(mdANSI.ReplaceAllString(text, "")) == "" && trimmed != "" {
+				continue
+			}
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+// 渲染标题，一级标题加下划线
+func renderHeading(level int, text string) string {
+	if level == 1 {
+		return ansi("1;4;35", text)
+	}
+	return ansi("1;36", text)
+}
+
+// 渲染行内语法，代码片段中的内容不处理
+func renderInline(line string) string {
+	parts := strings.Split(line, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = ansi("33", part)
+			continue
+		}
+		part = mdTag.ReplaceAllString(part, "")
+		part = mdImage.ReplaceAllString(part, "[图片: $1]")
+		part = mdLink.ReplaceAllStringFunc(part, func(s string) string {
+			m := mdLink.FindStringSubmatch(s)
+			if m[1] == m[2] {
+				return ansi("4", m[2])
+			}
+			return m[1] + " (" + ansi("4", m[2]) + ")"
+		})
+		part = mdBold.ReplaceAllStringFunc(part, func(s string) string { return ansi("1", s[2:len(s)-2]) })
+		part = mdItalic.ReplaceAllStringFunc(part, func(s string) string { return ansi("3", s[1:len(s)-1]) })
+		part = mdStrike.ReplaceAllStringFunc(part, func(s string) string { return ansi("9", s[2:len(s)-2]) })
+		if i%2 == 1 {
+			// 没有闭合的反引号原样保留
+			part = "`" + part
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+// 文本在终端中占用的列数，去掉 ANSI 样式，中文等全角字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range mdANSI.ReplaceAllString(s, "") {
+		switch {
+		case r < ' ':
+		case r >= 0x1100 && r <= 0x115f, r >= 0x2e80 && r <= 0xa4cf, r >= 0xac00 && r <= 0xd7a3,
+			r >= 0xf900 && r <= 0xfaff, r >= 0xfe30 && r <= 0xfe4f, r >= 0xff00 && r <= 0xff60,
+			r >= 0xffe0 && r <= 0xffe6, r >= 0x1f300 && r <= 0x1f64f, r >= 0x1f900 && r <= 0x1f9ff,
+			r >= 0x20000 && r <= 0x3fffd:
+			width += 2
+		case r != utf8.RuneError:
+			width++
+		}
+	}
+	return width
+}
+
+// 分页显示文本：在终端中每次显示一屏，按空格或回车显示下一屏，按 q 结束；不在终端中时直接输出全部内容
+func showPaged(ctx context.Context, lines []string) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
+	}
+	cols, rows := terminalSize()
+	height := max(rows-1, 1)
+	used := 0
+	for i, line := range lines {
+		n := max(1, (displayWidth(line)+cols-1)/cols)
+		if used > 0 && used+n > height {
+			key, err := readInput(ctx, ansi("7", fmt.Sprintf(" %d%%，空格或回车继续，q 结束 ", i*100/len(lines))), func(rune) bool { return true }, nil, clickList{})
+			if consoleVT {
+				// 清除分页提示
+				fmt.Print("\x1b[1A\r\x1b[K")
+			}
+			if err != nil || key == quitInput {
+				return
+			}
+			used = 0
+		}
+		fmt.Println(line)
+		used += n
+	}
+}
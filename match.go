@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 未找到项目时最多给出的建议数
+const maxSuggestions = 5
+
+// projectCandidate 为可以直接启动的项目：工作目录下的项目，或子目录（subDir）中的项目
+type projectCandidate struct {
+	Dir  string // 所在子目录，工作目录下的项目为空
+	Name string // 项目文件夹名
+}
+
+// 相对于工作目录的路径，以 / 分隔
+func (c projectCandidate) path() string {
+	if c.Dir == "" {
+		return c.Name
+	}
+	return c.Dir + "/" + c.Name
+}
+
+// 列出工作目录下和各子目录中的项目，子目录本身不计入，不切换目录
+func projectCandidates(config *Config) ([]projectCandidate, error) {
+	folders, err := activeFolders(config)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []projectCandidate
+	var subDirs []string
+	for _, folder := range folders {
+		if contains(folder.Name(), config.SubDir) {
+			subDirs = append(subDirs, folder.Name())
+			continue
+		}
+		candidates = append(candidates, projectCandidate{Name: folder.Name()})
+	}
+	for _, dir := range subDirs {
+		entries, err := listFolders(filepath.Join(config.ProjectDir, dir), nil)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			candidates = append(candidates, projectCandidate{Dir: dir, Name: entry.Name()})
+		}
+	}
+	return candidates, nil
+}
+
+// 按名称、别名或路径查找项目。路径可以只写末尾的部分，如 work/api 或 api；
+// 完整路径优先，其次为末尾部分相同的项目，有多个匹配时返回错误并列出各自的路径。
+// 都不匹配时按模糊匹配查找，得分明显最高的项目视为匹配，否则 ok 为 false，并给出最接近的几个项目作为建议
+func matchProject(config *Config, query string) (c projectCandidate, ok bool, suggestions []string, err error) {
+	candidates, err := projectCandidates(config)
+	if err != nil {
+		return c, false, nil, err
+	}
+	query = strings.Trim(filepath.ToSlash(resolveAlias(config, query)), "/")
+	for _, c := range candidates {
+		if c.path() == query {
+			return c, true, nil, nil
+		}
+	}
+	var matches []projectCandidate
+	for _, c := range candidates {
+		if strings.HasSuffix(c.path(), "/"+query) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		// 大小写不同时同样视为匹配
+		lower := strings.ToLower(query)
+		for _, c := range candidates {
+			if p := strings.ToLower(c.path()); p == lower || strings.HasSuffix(p, "/"+lower) {
+				matches = append(matches, c)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		ranked := fuzzyRank(config, candidates, query)
+		if len(ranked) > 0 && ranked[0].score > scoreSubsequence && (len(ranked) == 1 || ranked[0].score-ranked[1].score >= clearWinnerMargin) {
+			fmt.Printf("未找到 %s，使用最接近的项目 %s\n", query, ranked[0].path())
+			return ranked[0].projectCandidate, true, nil, nil
+		}
+		for i := 0; i < len(ranked) && i < maxSuggestions; i++ {
+			suggestions = append(suggestions, ranked[i].path())
+		}
+		return c, false, suggestions, nil
+	case 1:
+		return matches[0], true, nil, nil
+	}
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = m.path()
+	}
+	setExitCode(exitNotFound)
+	return c, false, nil, fmt.Errorf("有多个项目名为 %s，请输入更完整的路径：%s", query, strings.Join(paths, "、"))
+}
+
+// 未找到项目时的提示，有建议时一并列出
+func notFoundHint(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return "，是否要找：" + strings.Join(suggestions, "、")
+}
+
+// 模糊匹配的得分，得分越高越接近
+const (
+	scoreSame        = 100 // 忽略大小写和 - _ . 等分隔符后相同，如 myapp 与 my-app
+	scorePrefix      = 80  // 名称以输入开头
+	scoreInitials    = 70  // 输入为各单词的首字母，如 ma 与 my-app
+	scoreContains    = 60  // 名称包含输入
+	scoreTypo        = 50  // 有一两处打错，每处扣 10 分
+	scoreSubsequence = 30  // 输入的字母按顺序出现在名称中
+)
+
+// 最高分比第二名至少高出该分数时视为明确的匹配，直接启动；只是字母按顺序出现时只作为建议
+const clearWinnerMargin = 20
+
+// rankedCandidate 为模糊匹配得分大于 0 的项目
+type rankedCandidate struct {
+	projectCandidate
+	score int
+}
+
+// 按模糊匹配得分从高到低排列项目，比较项目名、路径和指向项目的别名，得分相同时保持原有顺序
+func fuzzyRank(config *Config, candidates []projectCandidate, query string) []rankedCandidate {
+	aliases := make(map[string][]string)
+	for alias, name := range projectAliases(config) {
+		aliases[name] = append(aliases[name], alias)
+	}
+	q := normalizeName(query)
+	var ranked []rankedCandidate
+	if q == "" {
+		return nil
+	}
+	for _, c := range candidates {
+		score := 0
+		names := append([]string{c.Name, c.path()}, aliases[c.path()]...)
+		for _, name := range names {
+			score = max(score, fuzzyScore(name, q))
+		}
+		if score > 0 {
+			ranked = append(ranked, rankedCandidate{projectCandidate: c, score: score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	return ranked
+}
+
+// 计算名称与已规范化的输入的模糊匹配得分，不匹配时为 0
+func fuzzyScore(name, q string) int {
+	n := normalizeName(name)
+	switch {
+	case n == q:
+		return scoreSame
+	case strings.HasPrefix(n, q):
+		return scorePrefix
+	case len(q) > 1 && nameInitials(name) == q:
+		return scoreInitials
+	case strings.Contains(n, q):
+		return scoreContains
+	}
+	// 允许的打错次数随长度增加，较短的输入只允许一处
+	if d := editDistance(n, q); d <= max(1, len(q)/5) && d < len(q) {
+		return scoreTypo - 10*d
+	}
+	if isSubsequence(q, n) {
+		return scoreSubsequence
+	}
+	return 0
+}
+
+// 转换为小写并去掉分隔符，用于忽略 kebab-case、snake_case 等写法的差异
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if !strings.ContainsRune("-_. /", r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// 以分隔符和大小写切换划分单词，返回各单词小写的首字母，如 my-appServer 为 mas
+func nameInitials(name string) string {
+	var b strings.Builder
+	prev := '-'
+	for _, r := range name {
+		if !strings.ContainsRune("-_. /", r) && (strings.ContainsRune("-_. /", prev) || unicode.IsUpper(r) && !unicode.IsUpper(prev)) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// 两个字符串的编辑距离（插入、删除、替换各计一次）
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// 判断 q 的字符是否按顺序出现在 s 中
+func isSubsequence(q, s string) bool {
+	rs := []rune(s)
+	i := 0
+	for _, r := range q {
+		for i < len(rs) && rs[i] != r {
+			i++
+		}
+		if i == len(rs) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// 进入项目所在的目录，之后可以按项目文件夹名启动
+func (c projectCandidate) enter(config *Config) error {
+	return os.Chdir(filepath.Join(config.ProjectDir, c.Dir))
+}
+
+// 在工作目录和各子目录中出现多次的项目文件夹名，菜单中为这些项目显示所在目录以便区分
+func duplicateNames(config *Config) map[string]bool {
+	if len(config.SubDir) == 0 {
+		return nil
+	}
+	candidates, err := projectCandidates(config)
+	if err != nil {
+		return nil
+	}
+	count := make(map[string]int, len(candidates))
+	for _, c := range candidates {
+		count[c.Name]++
+	}
+	dups := make(map[string]bool)
+	for name, n := range count {
+		if n > 1 {
+			dups[name] = true
+		}
+	}
+	return dups
+}
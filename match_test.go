@@ -0,0 +1,134 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        int
+	}{
+		{"my-app", "myapp", scoreSame},
+		{"My_App", "my.app", scoreSame},
+		{"api-server", "api", scorePrefix},
+		{"my-app", "ma", scoreInitials},
+		{"myAppServer", "mas", scoreInitials},
+		{"backend", "end", scoreContains},
+		{"frontend", "frontnd", scoreTypo - 10},
+		{"frontend", "fntd", scoreSubsequence},
+		{"api", "xyz", 0},
+		// 单个字母打错时不算匹配
+		{"a", "b", 0},
+	}
+	for _, tt := range tests {
+		if got := fuzzyScore(tt.name, normalizeName(tt.query)); got != tt.want {
+			t.Errorf("fuzzyScore(%q, %q) = %d, want %d", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchProject(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []string
+		config      Config
+		query       string
+		want        string // 匹配的项目路径，为空表示未匹配
+		suggestions []string
+		wantErr     bool
+	}{
+		{
+			name:    "完整名称",
+			entries: []string{"web/", "webapp/"},
+			query:   "web",
+			want:    "web",
+		},
+		{
+			name:    "子目录中的项目按末尾部分匹配",
+			entries: []string{"api-server/", "work/", "work/api/"},
+			config:  Config{SubDir: []string{"work"}},
+			query:   "api",
+			want:    "work/api",
+		},
+		{
+			name:    "忽略大小写",
+			entries: []string{"web/"},
+			query:   "WEB",
+			want:    "web",
+		},
+		{
+			name:    "别名",
+			entries: []string{"frontend/"},
+			config:  Config{Aliases: map[string]string{"fe": "frontend"}},
+			query:   "fe",
+			want:    "frontend",
+		},
+		{
+			name:    "多个同名项目时报错",
+			entries: []string{"work/", "work/app/", "other/", "other/app/"},
+			config:  Config{SubDir: []string{"work", "other"}},
+			query:   "app",
+			wantErr: true,
+		},
+		{
+			name:    "模糊匹配得分领先足够多时直接启动",
+			entries: []string{"myapp/", "myapp-admin/"},
+			query:   "my-app",
+			want:    "myapp",
+		},
+		{
+			name:    "只有一个打错的匹配时直接启动",
+			entries: []string{"web/", "docs/"},
+			query:   "wb",
+			want:    "web",
+		},
+		{
+			name:        "得分相同时只给出建议",
+			entries:     []string{"backend/", "frontend/"},
+			query:       "end",
+			suggestions: []string{"backend", "frontend"},
+		},
+		{
+			name:        "领先不足 clearWinnerMargin 时只给出建议",
+			entries:     []string{"abc/", "apple-box/"},
+			query:       "ab",
+			suggestions: []string{"abc", "apple-box"},
+		},
+		{
+			name:        "只是字母按顺序出现时不启动",
+			entries:     []string{"api-server/", "docs/"},
+			query:       "aer",
+			suggestions: []string{"api-server"},
+		},
+		{
+			name:    "没有任何匹配",
+			entries: []string{"web/"},
+			query:   "zzz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempState(t)
+			t.Cleanup(func() { exitCode.Store(0) })
+			config := tt.config
+			config.ProjectDir = makeProjectDir(t, tt.entries...)
+
+			c, ok, suggestions, err := matchProject(&config, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchProject(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			got := ""
+			if ok {
+				got = c.path()
+			}
+			if got != tt.want {
+				t.Errorf("matchProject(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+			if !reflect.DeepEqual(suggestions, tt.suggestions) {
+				t.Errorf("matchProject(%q) suggestions = %v, want %v", tt.query, suggestions, tt.suggestions)
+			}
+		})
+	}
+}
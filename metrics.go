@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 就绪耗时直方图的分桶上限（秒）
+var readyBuckets = []float64{1, 2, 5, 10, 20, 30, 60, 120, 300}
+
+// readyHistogram 为一个项目的就绪耗时分布
+type readyHistogram struct {
+	counts []int // 与 readyBuckets 对应，每个分桶的次数（不累加）
+	sum    float64
+	count  int
+}
+
+// daemonMetrics 为守护进程启动以来的累计指标，通过 /metrics 以 Prometheus 文本格式提供
+type daemonMetrics struct {
+	mu       sync.Mutex
+	launches map[string]int
+	failures map[string]int // 未能就绪（就绪检查超时或就绪前退出）的启动次数
+	crashes  map[string]int // 服务异常退出的次数
+	ready    map[string]*readyHistogram
+}
+
+func newDaemonMetrics() *daemonMetrics {
+	return &daemonMetrics{
+		launches: make(map[string]int),
+		failures: make(map[string]int),
+		crashes:  make(map[string]int),
+		ready:    make(map[string]*readyHistogram),
+	}
+}
+
+// 记录一次启动，服务就绪或无法就绪后记录结果。只有配置了就绪检查的服务记录就绪耗时
+func (m *daemonMetrics) launched(svc *service, started time.Time) {
+	m.mu.Lock()
+	m.launches[svc.Project]++
+	m.mu.Unlock()
+	go func() {
+		<-svc.ready
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if !svc.readyOK {
+			m.failures[svc.Project]++
+			return
+		}
+		if svc.Health == nil {
+			return
+		}
+		h := m.ready[svc.Project]
+		if h == nil {
+			h = &readyHistogram{counts: make([]int, len(readyBuckets))}
+			m.ready[svc.Project] = h
+		}
+		seconds := time.Since(started).Seconds()
+		h.sum += seconds
+		h.count++
+		for i, bound := range readyBuckets {
+			if seconds <= bound {
+				h.counts[i]++
+				break
+			}
+		}
+	}()
+}
+
+// 记录服务异常退出
+func (m *daemonMetrics) crashed(project string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.crashes[project]++
+}
+
+// 在 addr 上提供 /metrics，返回关闭服务的函数
+func (d *daemon) serveMetrics(addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("无法监听指标地址 %s: %v", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		d.writeMetrics(w)
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	fmt.Printf("指标地址：http://%s/metrics\n", listener.Addr())
+	return func() { server.Close() }, nil
+}
+
+// 以 Prometheus 文本格式输出指标：运行中的服务、启动次数、失败次数和就绪耗时
+func (d *daemon) writeMetrics(w io.Writer) {
+	d.mu.Lock()
+	running := make(map[string]bool, len(d.services))
+	ready := make(map[string]bool, len(d.services))
+	for name, svc := range d.services {
+		running[name] = svc.running()
+		ready[name] = running[name] && svc.ready()
+	}
+	d.mu.Unlock()
+
+	count := 0
+	for _, ok := range running {
+		if ok {
+			count++
+		}
+	}
+	fmt.Fprintln(w, "# HELP quickstart_services_running 守护进程启动且仍在运行的服务数量")
+	fmt.Fprintln(w, "# TYPE quickstart_services_running gauge")
+	fmt.Fprintf(w, "quickstart_services_running %d\n", count)
+	writeGauge(w, "quickstart_service_running", "服务是否在运行", running)
+	writeGauge(w, "quickstart_service_ready", "服务是否已就绪", ready)
+
+	m := d.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeCounter(w, "quickstart_launches_total", "守护进程启动服务的次数", m.launches)
+	writeCounter(w, "quickstart_launch_failures_total", "启动后未能就绪的次数", m.failures)
+	writeCounter(w, "quickstart_service_crashes_total", "服务异常退出的次数", m.crashes)
+
+	fmt.Fprintln(w, "# HELP quickstart_ready_seconds 服务启动到就绪检查通过的耗时")
+	fmt.Fprintln(w, "# TYPE quickstart_ready_seconds histogram")
+	for _, project := range sortedKeys(m.ready) {
+		h := m.ready[project]
+		label := promLabel(project)
+		total := 0
+		for i, bound := range readyBuckets {
+			total += h.counts[i]
+			fmt.Fprintf(w, "quickstart_ready_seconds_bucket{project=%s,le=\"%g\"} %d\n", label, bound, total)
+		}
+		fmt.Fprintf(w, "quickstart_ready_seconds_bucket{project=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "quickstart_ready_seconds_sum{project=%s} %g\n", label, h.sum)
+		fmt.Fprintf(w, "quickstart_ready_seconds_count{project=%s} %d\n", label, h.count)
+	}
+}
+
+// 输出按项目区分的 0/1 指标
+func writeGauge(w io.Writer, name, help string, values map[string]bool) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for _, project := range sortedKeys(values) {
+		value := 0
+		if values[project] {
+			value = 1
+		}
+		fmt.Fprintf(w, "%s{project=%s} %d\n", name, promLabel(project), value)
+	}
+}
+
+// 输出按项目区分的计数
+func writeCounter(w io.Writer, name, help string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, project := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{project=%s} %d\n", name, promLabel(project), values[project])
+	}
+}
+
+// 将标签值转义并加上引号
+func promLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
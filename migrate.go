@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 当前的配置文件版本，修改配置格式时加 1，并在 configMigrations 中追加对应的迁移
+const configVersion = 1
+
+// configMigration 将配置从上一个版本升级到 Version
+type configMigration struct {
+	Version     int
+	Description string
+	// Migrate 直接修改配置文件的顶层字段，旧格式可能无法解析为当前的 Config
+	Migrate func(raw map[string]json.RawMessage) error
+}
+
+// 按版本顺序排列的配置迁移
+var configMigrations = []configMigration{
+	{Version: 1, Description: "将备注转换为 Remark 列表：兼容 {\"项目\": \"备注\"} 的写法，去掉空备注，同一项目只保留第一条", Migrate: migrateRemarks},
+}
+
+// 将配置内容升级到当前版本，返回升级后的内容和原来的版本。已是当前版本时原样返回；
+// 版本高于当前程序支持的版本时只提示，仍按当前格式读取
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return nil, 0, fmt.Errorf("version 有误: %v", err)
+		}
+	}
+	if version > configVersion {
+		fmt.Fprintf(os.Stderr, "配置文件的版本 %d 高于当前程序支持的版本 %d，新的配置项可能不会生效，请升级 quickstart\n", version, configVersion)
+		return data, version, nil
+	}
+	if version == configVersion {
+		return data, version, nil
+	}
+	for _, m := range configMigrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := m.Migrate(raw); err != nil {
+			return nil, version, fmt.Errorf("无法将配置升级到版本 %d（%s）: %v", m.Version, m.Description, err)
+		}
+	}
+	raw["version"], _ = json.Marshal(configVersion)
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, err
+	}
+	return migrated, version, nil
+}
+
+// 升级配置文件：旧版本的配置升级后写回，原文件备份为 config.json.v<版本>.bak
+func migrateConfigFile(data []byte) ([]byte, error) {
+	migrated, from, err := migrateConfig(data)
+	if err != nil || from >= configVersion {
+		return migrated, err
+	}
+	var config Config
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, err
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", configPath, from)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return nil, fmt.Errorf("无法备份配置文件: %v", err)
+	}
+	if err := writeConfig(&config); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "配置文件已从版本 %d 升级到 %d，原文件已备份为 %s\n", from, configVersion, backup)
+	return migrated, nil
+}
+
+// legacyRemark 为版本 0 的备注，只有项目名和备注文字
+type legacyRemark struct {
+	Name   string `json:"name"`
+	Remark string `json:"remark"`
+}
+
+// 版本 1：remarks 由 [{"name": ..., "remark": ...}] 或 {"项目": "备注"} 转换为 Remark 列表。
+// 版本 0 的菜单显示同一项目的第一条备注，这里同样只保留第一条，并去掉没有内容的备注
+func migrateRemarks(raw map[string]json.RawMessage) error {
+	value, ok := raw["remarks"]
+	if !ok {
+		return nil
+	}
+	var legacy []legacyRemark
+	if err := json.Unmarshal(value, &legacy); err != nil {
+		// 按项目名写备注的对象，键的顺序即为备注的顺序
+		var byName map[string]string
+		if json.Unmarshal(value, &byName) != nil {
+			return fmt.Errorf("remarks 有误: %v", err)
+		}
+		decoder := json.NewDecoder(strings.NewReader(string(value)))
+		decoder.Token()
+		for decoder.More() {
+			key, _ := decoder.Token()
+			var remark string
+			decoder.Decode(&remark)
+			legacy = append(legacy, legacyRemark{Name: key.(string), Remark: remark})
+		}
+	}
+	remarks := []Remark{}
+	seen := make(map[string]bool)
+	for _, r := range legacy {
+		if seen[r.Name] {
+			continue
+		}
+		seen[r.Name] = true
+		remark := Remark{Name: r.Name, Remark: strings.TrimSpace(r.Remark)}
+		if !remark.empty() {
+			remarks = append(remarks, remark)
+		}
+	}
+	raw["remarks"], _ = json.Marshal(remarks)
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 读取 testdata/migrate 中的配置文件
+func readMigrateFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrate", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// 比较两个 JSON 的内容，忽略格式和字段顺序
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("结果不是有效的 JSON: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("结果为\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		fixture string
+		from    int
+	}{
+		{"v0-list", 0},
+		{"v0-object", 0},
+		{"v0-no-remarks", 0},
+		{"v1", 1},
+		{"future", 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			migrated, from, err := migrateConfig(readMigrateFixture(t, tt.fixture+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != tt.from {
+				t.Errorf("原版本 = %d, want %d", from, tt.from)
+			}
+			assertJSONEqual(t, migrated, readMigrateFixture(t, tt.fixture+".want.json"))
+		})
+	}
+}
+
+func TestMigrateConfigErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"remarks": "api"}`,
+		`{"remarks": {"api": 1}}`,
+		`{"version": "1"}`,
+		`not json`,
+	} {
+		if _, _, err := migrateConfig([]byte(data)); err == nil {
+			t.Errorf("migrateConfig(%s) 没有返回错误", data)
+		}
+	}
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	old := configPath
+	configPath = filepath.Join(t.TempDir(), configFile)
+	t.Cleanup(func() { configPath = old })
+
+	data := readMigrateFixture(t, "v0-list.json")
+	migrated, err := migrateConfigFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, migrated, readMigrateFixture(t, "v0-list.want.json"))
+
+	backup, err := os.ReadFile(configPath + ".v0.bak")
+	if err != nil {
+		t.Fatalf("没有备份原文件: %v", err)
+	}
+	if string(backup) != string(data) {
+		t.Errorf("备份的内容与原文件不同")
+	}
+	written, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config Config
+	if err := json.Unmarshal(written, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Version != configVersion || !reflect.DeepEqual(config.Remarks, []Remark{{Name: "api", Remark: "后端"}}) {
+		t.Errorf("写回的配置 = %+v", config)
+	}
+
+	// 已是当前版本时不再备份和写回
+	if err := os.Remove(configPath + ".v0.bak"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrateConfigFile(written); err != nil {
+		t.Fatal(err)
+	}
+	if fileExists(configPath + ".v1.bak") {
+		t.Error("当前版本的配置不应备份")
+	}
+}
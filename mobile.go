@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// 判断当前目录是否为 Flutter 项目
+func isFlutterProject() bool {
+	data, err := os.ReadFile("pubspec.yaml")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "flutter:")
+}
+
+// 判断当前目录是否为 React Native 项目
+func isReactNativeProject() bool {
+	var pkg packageJSON
+	if err := readJSONFile("package.json", &pkg); err != nil {
+		return false
+	}
+	_, ok := pkg.Dependencies["react-native"]
+	return ok
+}
+
+// 列出已连接的设备和模拟器，每个设备对应一个 flutter run -d 启动方式
+func flutterActions(ctx context.Context) []launchAction {
+	var actions []launchAction
+	out, err := queryOutput(ctx, "flutter", "devices", "--machine")
+	if err == nil {
+		var devices []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		}
+		// flutter 可能在 JSON 前输出提示信息，从第一个 [ 开始解析
+		if i := strings.Index(string(out), "["); i >= 0 && json.Unmarshal(out[i:], &devices) == nil {
+			for _, device := range devices {
+				actions = append(actions, launchAction{
+					Name:    "flutter run -d " + device.ID + "  (" + device.Name + ")",
+					Command: []string{"flutter", "run", "-d", device.ID},
+				})
+			}
+		}
+	}
+	if len(actions) == 0 {
+		actions = append(actions, launchAction{Name: "flutter run", Command: []string{"flutter", "run"}})
+	}
+	return actions
+}
+
+// 列出 adb 已连接的 Android 设备，macOS 下额外提供 iOS 模拟器启动方式
+func reactNativeActions(ctx context.Context) []launchAction {
+	var actions []launchAction
+	for _, device := range adbDevices(ctx) {
+		actions = append(actions, launchAction{
+			Name:    "npx react-native run-android --deviceId " + device,
+			Command: []string{"npx", "react-native", "run-android", "--deviceId", device},
+		})
+	}
+	if len(actions) == 0 {
+		actions = append(actions, launchAction{Name: "npx react-native run-android", Command: []string{"npx", "react-native", "run-android"}})
+	}
+	if runtime.GOOS == "darwin" {
+		actions = append(actions, launchAction{Name: "npx react-native run-ios", Command: []string{"npx", "react-native", "run-ios"}})
+	}
+	return actions
+}
+
+// 获取 adb devices 中状态为 device 的设备序列号
+func adbDevices(ctx context.Context) []string {
+	out, err := queryOutput(ctx, "adb", "devices")
+	if err != nil {
+		return nil
+	}
+	var devices []string
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == "device" {
+			devices = append(devices, fields[0])
+		}
+	}
+	return devices
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// workspacePackage 表示 monorepo 中的一个子包或应用
+type workspacePackage struct {
+	Name    string            // 包名，用于过滤命令
+	Dir     string            // 相对于仓库根目录的路径
+	Scripts map[string]string // package.json 中的脚本
+}
+
+// 判断当前目录是否为 monorepo
+func isMonorepo() bool {
+	return monorepoTool() != ""
+}
+
+// 识别 monorepo 使用的工具，非 monorepo 返回空字符串
+func monorepoTool() string {
+	switch {
+	case fileExists("nx.json"):
+		return "nx"
+	case fileExists("turbo.json"):
+		return "turbo"
+	case fileExists("lerna.json"):
+		return "lerna"
+	case fileExists("pnpm-workspace.yaml"):
+		return "pnpm"
+	}
+	if len(packageWorkspaces()) > 0 {
+		if fileExists("yarn.lock") {
+			return "yarn"
+		}
+		return "npm"
+	}
+	return ""
+}
+
+// 列出 monorepo 中每个子包的启动方式
+func monorepoActions(_ context.Context) []launchAction {
+	tool := monorepoTool()
+	var actions []launchAction
+	for _, pkg := range workspacePackages(tool) {
+		var command []string
+		if tool == "nx" {
+			command = []string{"npx", "nx", "serve", pkg.Name}
+		} else {
+			script := devScript(pkg.Scripts)
+			if script == "" {
+				continue
+			}
+			switch tool {
+			case "turbo":
+				command = []string{"npx", "turbo", "run", script, "--filter=" + pkg.Name}
+			case "lerna":
+				command = []string{"npx", "lerna", "run", script, "--scope", pkg.Name}
+			case "pnpm":
+				command = []string{"pnpm", "--filter", pkg.Name, script}
+			case "yarn":
+				command = []string{"yarn", "workspace", pkg.Name, script}
+			default:
+				command = []string{"npm", "run", script, "--workspace", pkg.Name}
+			}
+		}
+		actions = append(actions, launchAction{
+			Name:    pkg.Name + "  (" + filepath.ToSlash(pkg.Dir) + ")",
+			Command: command,
+		})
+	}
+	return actions
+}
+
+// 根据工具读取子包的路径模式并展开为子包列表
+func workspacePackages(tool string) []workspacePackage {
+	var patterns []string
+	switch tool {
+	case "nx":
+		return nxProjects()
+	case "lerna":
+		var lerna struct {
+			Packages []string `json:"packages"`
+		}
+		if err := readJSONFile("lerna.json", &lerna); err == nil {
+			patterns = lerna.Packages
+		}
+	case "pnpm":
+		patterns = pnpmWorkspaces()
+	}
+	// turbo 与未声明 packages 的 lerna 沿用包管理器的 workspaces 配置
+	if len(patterns) == 0 {
+		patterns = packageWorkspaces()
+	}
+	return expandWorkspaces(patterns)
+}
+
+// 读取 package.json 中的 workspaces，兼容数组和 {packages: []} 两种写法
+func packageWorkspaces() []string {
+	var pkg packageJSON
+	if err := readJSONFile("package.json", &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+	return nil
+}
+
+// 读取 pnpm-workspace.yaml 中 packages 列表，只解析简单的列表写法
+func pnpmWorkspaces() []string {
+	file, err := os.Open("pnpm-workspace.yaml")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var (
+		patterns   []string
+		inPackages bool
+	)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		// 顶格的键名表示新的配置段
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			continue
+		}
+		if inPackages && strings.HasPrefix(trimmed, "-") {
+			pattern := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			patterns = append(patterns, strings.Trim(pattern, `"'`))
+		}
+	}
+	return patterns
+}
+
+// 展开子包路径模式，支持 * 与 ** 通配符以及 ! 排除
+func expandWorkspaces(patterns []string) []workspacePackage {
+	var include, exclude []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			exclude = append(exclude, strings.TrimPrefix(pattern, "!"))
+		} else {
+			include = append(include, pattern)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var packages []workspacePackage
+	for _, pattern := range include {
+		for _, dir := range globDirs(pattern) {
+			if seen[dir] || matchAny(exclude, dir) {
+				continue
+			}
+			seen[dir] = true
+
+			var pkg packageJSON
+			if err := readJSONFile(filepath.Join(dir, "package.json"), &pkg); err != nil {
+				continue
+			}
+			name := pkg.Name
+			if name == "" {
+				name = filepath.Base(dir)
+			}
+			packages = append(packages, workspacePackage{Name: name, Dir: dir, Scripts: pkg.Scripts})
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Dir < packages[j].Dir })
+	return packages
+}
+
+// 展开路径模式得到匹配的目录，** 匹配任意层级（跳过 node_modules）
+func globDirs(pattern string) []string {
+	pattern = filepath.FromSlash(strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/"))
+	if !strings.Contains(pattern, "**") {
+		matches, _ := filepath.Glob(pattern)
+		var dirs []string
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				dirs = append(dirs, match)
+			}
+		}
+		return dirs
+	}
+
+	root := strings.TrimSuffix(pattern[:strings.Index(pattern, "**")], string(filepath.Separator))
+	if root == "" {
+		root = "."
+	}
+	var dirs []string
+	filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		if entry.Name() == "node_modules" || (strings.HasPrefix(entry.Name(), ".") && path != root) {
+			return filepath.SkipDir
+		}
+		if path != root && matchPattern(pattern, path) {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// 判断路径是否匹配任一模式
+func matchAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(filepath.FromSlash(strings.TrimPrefix(pattern, "./")), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// 按路径段匹配模式，** 可匹配零个或多个路径段
+func matchPattern(pattern, path string) bool {
+	return matchSegments(strings.Split(filepath.ToSlash(pattern), "/"), strings.Split(filepath.ToSlash(path), "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// 查找 nx 工作区中的项目，以 project.json 所在目录为一个项目
+func nxProjects() []workspacePackage {
+	var packages []workspacePackage
+	filepath.WalkDir(".", func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if entry.Name() == "node_modules" || entry.Name() == "dist" || (strings.HasPrefix(entry.Name(), ".") && path != ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "project.json" || path == "project.json" {
+			return nil
+		}
+		var project struct {
+			Name string `json:"name"`
+		}
+		if err := readJSONFile(path, &project); err != nil {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		name := project.Name
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		packages = append(packages, workspacePackage{Name: name, Dir: dir})
+		return nil
+	})
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Dir < packages[j].Dir })
+	return packages
+}
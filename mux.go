@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// 多个服务同时运行时，输出前缀依次使用的颜色
+var prefixColors = []string{"36", "33", "35", "32", "34", "31"}
+
+// prefixWriter 为每行输出加上服务名前缀，多个服务共用同一把锁避免输出交错
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, bytes.TrimRight(w.buf[:i], "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// 输出末尾没有换行的剩余内容
+func (w *prefixWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+		w.buf = nil
+	}
+}
+
+// 多服务视图保留的最近输出行数，切换显示的服务时从中回放
+const logViewLines = 2000
+
+// logEntry 为多服务视图中的一行输出
+type logEntry struct {
+	service int
+	text    string
+}
+
+// logView 汇集多个服务的输出并加上彩色名称前缀，可以只显示其中一个服务（focus 为服务编号，0 为全部）
+type logView struct {
+	mu       sync.Mutex
+	out      io.Writer
+	prefixes []string
+	entries  []logEntry
+	focus    int
+}
+
+// paneWriter 为一个服务写入多服务视图的输出
+type paneWriter struct {
+	view    *logView
+	service int
+	buf     []byte
+}
+
+func (w *paneWriter) Write(p []byte) (int, error) {
+	v := w.view
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		entry := logEntry{service: w.service, text: string(bytes.TrimRight(w.buf[:i], "\r"))}
+		w.buf = w.buf[i+1:]
+		v.entries = append(v.entries, entry)
+		if len(v.entries) > logViewLines {
+			v.entries = v.entries[len(v.entries)-logViewLines:]
+		}
+		if v.focus == 0 || v.focus == entry.service {
+			v.print(entry)
+		}
+	}
+	return len(p), nil
+}
+
+// 输出一行，调用方需持有锁
+func (v *logView) print(entry logEntry) {
+	fmt.Fprintf(v.out, "%s%s\n", v.prefixes[entry.service-1], entry.text)
+}
+
+// 切换显示的服务：清屏后回放该服务（0 为全部）的最近输出，之后只显示它的新输出
+func (v *logView) setFocus(focus int, names []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.focus = focus
+	clearScreen()
+	if focus == 0 {
+		printBanner(v.out, "显示全部服务的输出")
+	} else {
+		printBanner(v.out, "只显示 "+names[focus-1]+" 的输出，按 0 显示全部")
+	}
+	for _, entry := range v.entries {
+		if focus == 0 || entry.service == focus {
+			v.print(entry)
+		}
+	}
+}
+
+// 同时运行多个服务，每个服务的输出带有彩色的名称前缀，所有服务退出后返回。设置了 After 的服务等待其中的服务就绪后再启动。
+// 在终端中运行时按 1-9 只显示对应服务的输出并回放其最近输出，按 0 恢复显示全部，Ctrl+C 或 q 停止所有服务
+func runServices(ctx context.Context, services []*service) {
+	if len(services) == 1 {
+		if err := services[0].run(ctx); err != nil {
+			failf(exitLaunchFailed, "%s 服务已退出: %v", services[0].label(), err)
+		}
+		return
+	}
+
+	width := 0
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.label()
+		width = max(width, len(names[i]))
+	}
+	view := &logView{out: processStdout}
+	for i, name := range names {
+		color := prefixColors[i%len(prefixColors)]
+		view.prefixes = append(view.prefixes, "\x1b["+color+"m"+name+strings.Repeat(" ", width-len(name))+" |\x1b[0m ")
+	}
+
+	// 按键模式下终端不再把 Ctrl+C 发给子进程，改为通过 Stop 结束服务
+	restore, keys := enableRawInput()
+	stop := make(chan struct{})
+	if keys {
+		defer restore()
+		for i, svc := range services {
+			svc.Stop = stop
+			fmt.Printf("%d. %s\n", i+1, names[i])
+		}
+		fmt.Printf("按编号只看对应服务的输出，0 显示全部，Ctrl+C 或 %s 停止所有服务\n", quitInput)
+	}
+
+	// 先为被等待的服务创建就绪通道，避免服务在通道创建前就绪
+	for _, svc := range services {
+		for _, dep := range svc.After {
+			if dep.ready == nil {
+				dep.ready = make(chan struct{})
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		svc.Output = &paneWriter{view: view, service: i + 1}
+		wg.Add(1)
+		go func(svc *service) {
+			defer wg.Done()
+			if !svc.waitAfter(stop) {
+				svc.setReady(false)
+				select {
+				case <-stop:
+				default:
+					setExitCode(exitLaunchFailed)
+				}
+				return
+			}
+			if err := svc.run(ctx); err != nil {
+				fmt.Fprintf(svc.Output, "服务已退出: %v\n", err)
+				setExitCode(exitLaunchFailed)
+			}
+		}(svc)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	if !keys {
+		<-done
+		return
+	}
+
+	stopOnce := sync.Once{}
+	for {
+		r, err := readRune(ctx, done)
+		if err == errInterrupted {
+			return
+		}
+		switch {
+		case err != nil || r == 3 || r == 4 || string(r) == quitInput:
+			stopOnce.Do(func() {
+				fmt.Println("正在停止所有服务")
+				close(stop)
+			})
+			if err != nil {
+				<-done
+				return
+			}
+		case r >= '0' && r <= '9' && int(r-'0') <= len(services):
+			view.setFocus(int(r-'0'), names)
+		}
+	}
+}
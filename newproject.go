@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 新项目在菜单中显示标记的默认天数
+const defaultNewDays = 3
+
+// 监听工作目录时的轮询间隔
+const projectDirInterval = 2 * time.Second
+
+// 首次发现记录文件路径，记录每个项目第一次出现在工作目录中的时间
+func seenPath() string {
+	return statePath("seen.json")
+}
+
+// 新项目显示标记的天数，小于 0 时不显示
+func newDays(config *Config) int {
+	if config.NewDays == 0 {
+		return defaultNewDays
+	}
+	return config.NewDays
+}
+
+// 记录首次出现在工作目录中的项目，返回最近 newDays 天内出现的项目。
+// 记录文件不存在时（首次使用）已有的项目不视为新项目；项目文件夹删除后移除记录，再次出现时重新视为新项目
+func newProjects(config *Config) map[string]bool {
+	folders, err := activeFolders(config)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]time.Time)
+	first := readJSONFile(seenPath(), &seen) != nil
+	changed := false
+	present := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		name := folder.Name()
+		present[name] = true
+		if _, ok := seen[name]; !ok {
+			if first {
+				seen[name] = time.Time{}
+			} else {
+				seen[name] = time.Now()
+			}
+			changed = true
+		}
+	}
+	for name := range seen {
+		if !present[name] {
+			delete(seen, name)
+			changed = true
+		}
+	}
+	if changed {
+		if data, err := json.MarshalIndent(seen, "", "  "); err == nil && os.MkdirAll(filepath.Dir(seenPath()), 0755) == nil {
+			os.WriteFile(seenPath(), data, 0644)
+		}
+	}
+
+	days := newDays(config)
+	if days < 0 {
+		return nil
+	}
+	result := make(map[string]bool)
+	for name, t := range seen {
+		if !t.IsZero() && time.Since(t) < time.Duration(days)*24*time.Hour {
+			result[name] = true
+		}
+	}
+	return result
+}
+
+// 在后台轮询工作目录下的文件夹，有文件夹新增、删除或改名时向 configChanged 发送信号，使菜单刷新
+func watchProjectDir(config *Config) {
+	dir := config.ProjectDir
+	names := folderNames(dir)
+	go func() {
+		for range time.Tick(projectDirInterval) {
+			current := folderNames(dir)
+			if current == names {
+				continue
+			}
+			names = current
+			select {
+			case configChanged <- struct{}{}:
+			default:
+			}
+		}
+	}()
+}
+
+// 目录下的文件夹名，排序后拼接，便于比较是否变动
+func folderNames(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n")
+}
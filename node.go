@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 记录上次安装时锁文件哈希的文件，放在 node_modules 中以便随目录一起删除
+const lockHashFile = ".quickstart-lock-hash"
+
+// 各包管理器的锁文件及对应的安装命令，按优先级排列
+var nodeLockFiles = []struct {
+	File    string
+	Command []string
+}{
+	{"pnpm-lock.yaml", []string{"pnpm", "install"}},
+	{"yarn.lock", []string{"yarn", "install"}},
+	{"bun.lockb", []string{"bun", "install"}},
+	{"bun.lock", []string{"bun", "install"}},
+	{"package-lock.json", []string{"npm", "install"}},
+}
+
+// 检查 node_modules 是否缺失或与锁文件不一致，需要安装时返回安装步骤
+func nodeInstall() *installStep {
+	lockFile, command := "", []string{"npm", "install"}
+	for _, lock := range nodeLockFiles {
+		if fileExists(lock.File) {
+			lockFile, command = lock.File, lock.Command
+			break
+		}
+	}
+
+	if !fileExists("node_modules") {
+		return &installStep{Reason: "检测到 node_modules 不存在", Command: command, Done: func() { saveLockHash(lockFile) }}
+	}
+	if lockFile == "" {
+		return nil
+	}
+
+	hash := fileHash(lockFile)
+	saved, err := os.ReadFile(filepath.Join("node_modules", lockHashFile))
+	if err != nil {
+		// 没有安装记录时，以锁文件是否比 node_modules 更新来判断
+		lockInfo, err1 := os.Stat(lockFile)
+		modulesInfo, err2 := os.Stat("node_modules")
+		if err1 == nil && err2 == nil && lockInfo.ModTime().After(modulesInfo.ModTime()) {
+			return &installStep{Reason: "检测到 " + lockFile + " 比 node_modules 更新", Command: command, Done: func() { saveLockHash(lockFile) }}
+		}
+		saveLockHash(lockFile)
+		return nil
+	}
+	if strings.TrimSpace(string(saved)) != hash {
+		return &installStep{Reason: "检测到 " + lockFile + " 已变更", Command: command, Done: func() { saveLockHash(lockFile) }}
+	}
+	return nil
+}
+
+// 根据锁文件判断项目使用的包管理器，默认为 npm
+func nodePackageManager() string {
+	for _, lock := range nodeLockFiles {
+		if fileExists(lock.File) {
+			return lock.Command[0]
+		}
+	}
+	return "npm"
+}
+
+// 将锁文件哈希写入 node_modules，作为依赖已安装的记录
+func saveLockHash(lockFile string) {
+	if lockFile == "" || !fileExists("node_modules") {
+		return
+	}
+	os.WriteFile(filepath.Join("node_modules", lockHashFile), []byte(fileHash(lockFile)), 0644)
+}
+
+// 计算文件内容的 SHA-256 哈希，读取失败时返回空字符串
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// 项目笔记保存的文件，位于状态目录中，按项目文件夹名命名
+func notesPath(folder string) string {
+	return statePath("notes", folder+".md")
+}
+
+// 分页显示项目目录下的 README，Markdown 转换为带样式的终端文本
+func showReadme(ctx context.Context, _ *Config, _, _ string) error {
+	name := findReadme()
+	if name == "" {
+		return fmt.Errorf("项目中没有 README")
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	showPaged(ctx, renderMarkdown(string(data)))
+	return nil
+}
+
+// 显示项目笔记并选择编辑、追加或删除。笔记由启动器保存，不写入项目目录，可以比一行备注长
+func projectNotes(ctx context.Context, _ *Config, folder, _ string) error {
+	path := notesPath(folder)
+	for {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		notes := strings.TrimSpace(string(data))
+		if notes == "" {
+			fmt.Printf("%s 还没有笔记\n", folder)
+		} else {
+			showPaged(ctx, renderMarkdown(notes))
+		}
+		input, err := readLine(ctx, "e 用编辑器编辑，a 追加一条，d 删除，直接回车返回: ")
+		if err != nil || input == "" || input == quitInput {
+			return nil
+		}
+		switch input {
+		case "e":
+			if err := editNotes(path); err != nil {
+				fmt.Println(err)
+			}
+		case "a":
+			if err := appendNote(ctx, path); err != nil {
+				fmt.Println(err)
+			}
+		case "d":
+			if notes != "" && confirm(ctx, fmt.Sprintf("确定删除 %s 的笔记？(Y/n): ", folder)) {
+				if err := os.Remove(path); err != nil {
+					fmt.Println(err)
+				}
+			}
+		default:
+			fmt.Println("无效的输入")
+		}
+	}
+}
+
+// 用编辑器打开笔记文件并等待编辑完成：依次使用 VISUAL、EDITOR 环境变量，
+// 其次为 Windows 的记事本或 vi
+func editNotes(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	command := append(splitCommand(editor), path)
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("无法使用 %s 编辑笔记: %v", editor, err)
+	}
+	return nil
+}
+
+// 输入多行文字追加为一条带日期的笔记，空行结束输入
+func appendNote(ctx context.Context, path string) error {
+	fmt.Println("输入笔记内容，空行结束：")
+	var lines []string
+	for {
+		line, err := readLine(ctx, "")
+		if err != nil || line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	entry := fmt.Sprintf("- %s %s\n", time.Now().Format("2006-01-02"), strings.Join(lines, "\n  "))
+	_, err = file.WriteString(entry)
+	return err
+}
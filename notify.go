@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Windows 下通过 PowerShell 调用系统通知，标题和内容经环境变量传入以避免转义问题
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode($env:QUICKSTART_TITLE)) > $null
+$texts.Item(1).AppendChild($template.CreateTextNode($env:QUICKSTART_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)`
+
+// 发送桌面通知，不等待通知命令结束，发送失败时静默忽略
+func notify(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+		cmd.Env = append(os.Environ(), "QUICKSTART_TITLE="+title, "QUICKSTART_MESSAGE="+message)
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	default:
+		cmd = exec.Command("notify-send", title, message)
+	}
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	go cmd.Wait()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// 命令行 --offline，开启离线模式
+var offlineFlag bool
+
+// 是否处于离线模式：命令行 --offline 或环境变量 QUICKSTART_OFFLINE 不为空且不为 0。
+// 离线模式下跳过 git pull、依赖安装、从 1Password 等服务读取密钥等需要网络的操作，编辑器和本地服务照常启动，
+// 不会因网络超时拖慢启动
+func offline() bool {
+	if offlineFlag {
+		return true
+	}
+	value := os.Getenv("QUICKSTART_OFFLINE")
+	return value != "" && value != "0"
+}
+
+// 离线模式下提示跳过需要网络的操作并返回 true
+func skipOffline(what string) bool {
+	if !offline() {
+		return false
+	}
+	fmt.Printf("离线模式，已跳过%s\n", what)
+	return true
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go-quickStart/ipc"
+)
+
+// 配置目录和状态目录下使用的应用名
+const appName = "quickstart"
+
+// 旧版本放在配置文件旁边的状态目录名
+const legacyStateDirName = ".quickstart"
+
+var (
+	// 配置文件的绝对路径，由 initPaths 确定
+	configPath string
+	// 状态文件所在目录，由 initPaths 确定
+	stateDir string
+)
+
+// 确定配置文件和状态目录的位置：配置文件位于用户配置目录（Linux 为 XDG_CONFIG_HOME，
+// Windows 为 %APPDATA%），状态文件位于用户状态目录（Linux 为 XDG_STATE_HOME，Windows 为 %LOCALAPPDATA%）。
+// 新位置没有配置文件时，从旧版本使用的程序所在目录迁移
+func initPaths() error {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return fmt.Errorf("无法确定配置目录: %v", err)
+	}
+	configPath = filepath.Join(configDir, appName, configFile)
+	if stateDir, err = ipc.StateDir(); err != nil {
+		return fmt.Errorf("无法确定状态目录: %v", err)
+	}
+	if !fileExists(configPath) {
+		migrateLegacyConfig()
+	}
+	return nil
+}
+
+// 旧版本的配置文件位于程序所在目录，找到时复制到新位置，并将旁边的状态目录移动到新的状态目录。
+// 不从当前目录迁移，避免在其他项目中运行时把项目自己的 config.json 当作启动器配置。旧文件保留不动，可以手动删除
+func migrateLegacyConfig() {
+	exePath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	legacy := filepath.Join(filepath.Dir(exePath), configFile)
+	if !isLauncherConfig(legacy) {
+		return
+	}
+	if err := copyFile(legacy, configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "无法迁移配置文件 %s: %v\n", legacy, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "已将配置文件 %s 迁移到 %s，旧文件可以删除\n", legacy, configPath)
+
+	legacyState := filepath.Join(filepath.Dir(legacy), legacyStateDirName)
+	if fileExists(legacyState) && !fileExists(stateDir) {
+		if err := os.MkdirAll(filepath.Dir(stateDir), 0755); err == nil {
+			os.Rename(legacyState, stateDir)
+		}
+	}
+}
+
+// 判断文件是否像启动器的配置文件：是 JSON 对象且配置了 projectDir
+func isLauncherConfig(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var config struct {
+		ProjectDir string `json:"projectDir"`
+	}
+	return json.Unmarshal(data, &config) == nil && config.ProjectDir != ""
+}
+
+// 复制文件，自动创建目标目录
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
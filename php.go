@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"runtime"
+)
+
+// composerJSON 为 composer.json 中用到的字段
+type composerJSON struct {
+	Require map[string]string `json:"require"`
+	Scripts map[string]any    `json:"scripts"`
+}
+
+// vendor 目录不存在时返回 composer install 安装步骤
+func composerInstall() *installStep {
+	if !fileExists("composer.json") || fileExists("vendor") {
+		return nil
+	}
+	return &installStep{Reason: "检测到 vendor 目录不存在", Command: []string{"composer", "install"}}
+}
+
+// webman 在 Windows 下通过 windows.bat 启动，其他系统通过 start.php 启动
+func webmanActions(_ context.Context) []launchAction {
+	if runtime.GOOS == "windows" {
+		// 需带上目录前缀，否则不会在当前目录中查找；启动时转换为绝对路径，避免目录名含空格或中文时出错
+		return []launchAction{{Name: "windows.bat", Command: []string{`.\windows.bat`}}}
+	}
+	return []launchAction{{Name: "php start.php start", Command: []string{"php", "start.php", "start"}}}
+}
+
+// Laravel 项目可选择启动开发服务、队列或 Horizon
+func laravelActions(_ context.Context) []launchAction {
+	actions := []launchAction{
+		{Name: "php artisan serve", Command: []string{"php", "artisan", "serve"}},
+		{Name: "php artisan queue:work", Command: []string{"php", "artisan", "queue:work"}},
+	}
+	var composer composerJSON
+	if err := readJSONFile("composer.json", &composer); err == nil {
+		if _, ok := composer.Require["laravel/horizon"]; ok {
+			actions = append(actions, launchAction{Name: "php artisan horizon", Command: []string{"php", "artisan", "horizon"}})
+		}
+	}
+	return actions
+}
+
+// 普通 PHP 项目优先使用 composer.json 中的脚本，其次使用内置服务器
+func phpActions(_ context.Context) []launchAction {
+	var actions []launchAction
+	var composer composerJSON
+	if err := readJSONFile("composer.json", &composer); err == nil {
+		for _, name := range []string{"serve", "start", "dev"} {
+			if _, ok := composer.Scripts[name]; ok {
+				actions = append(actions, launchAction{Name: "composer run " + name, Command: []string{"composer", "run", name}})
+			}
+		}
+	}
+	docRoot := "."
+	if fileExists("public") {
+		docRoot = "public"
+	}
+	actions = append(actions, launchAction{
+		Name:    "php -S localhost:8000 -t " + docRoot,
+		Command: []string{"php", "-S", "localhost:8000", "-t", docRoot},
+	})
+	return actions
+}
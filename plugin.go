@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// 插件协议版本，随请求发送给插件，协议有不兼容的修改时递增
+const pluginProtocolVersion = 1
+
+// 插件请求的类型
+const (
+	pluginDetect  = "detect"  // 识别项目类型并给出启动方式
+	pluginActions = "actions" // 在操作菜单中添加操作
+	pluginSteps   = "steps"   // 启动服务前执行的步骤
+)
+
+// pluginRequest 为通过标准输入发送给插件的请求
+type pluginRequest struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"`
+	Project string `json:"project"` // 项目名，即工作目录下的文件夹名
+	Dir     string `json:"dir"`     // 项目的绝对路径，也是插件运行的目录
+	Profile string `json:"profile,omitempty"`
+}
+
+// pluginResponse 为插件通过标准输出返回的响应，只需填写与请求类型对应的字段
+type pluginResponse struct {
+	Error    string          `json:"error"`    // 非空时视为插件出错
+	Detector *pluginDetector `json:"detector"` // detect 请求，未识别时为空
+	Actions  []pluginCommand `json:"actions"`  // actions 请求
+	Steps    []pluginCommand `json:"steps"`    // steps 请求
+}
+
+// pluginDetector 为插件识别出的项目类型
+type pluginDetector struct {
+	Name    string          `json:"name"`    // 项目类型，用于提示信息
+	Service string          `json:"service"` // 服务名称，用于提示信息，为空时使用 name
+	Actions []pluginCommand `json:"actions"` // 可选的启动方式
+	Install *struct {
+		Reason  string   `json:"reason"`
+		Command []string `json:"command"`
+	} `json:"install"` // 缺少依赖时的安装步骤
+}
+
+// pluginCommand 为插件给出的命令，在项目目录中执行
+type pluginCommand struct {
+	Name    string            `json:"name"`
+	Command []string          `json:"command"`
+	Env     map[string]string `json:"env"`
+}
+
+// 插件所在目录 ~/.quickstart/plugins，用户主目录无法确定时返回空字符串
+func pluginDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, legacyStateDirName, "plugins")
+}
+
+// 列出插件目录中的可执行文件，按文件名排序，目录不存在时返回 nil
+func listPlugins() []string {
+	dir := pluginDir()
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var plugins []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && isExecutableFile(info) {
+			plugins = append(plugins, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(plugins)
+	return plugins
+}
+
+// 判断文件是否可执行：Windows 按扩展名判断，其他系统按权限位判断
+func isExecutableFile(info os.FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0111 != 0
+}
+
+// 插件名，即去掉扩展名的文件名
+func pluginName(plugin string) string {
+	name := filepath.Base(plugin)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// 在当前目录运行插件：将请求以 JSON 写入标准输入，从标准输出读取 JSON 响应，没有输出时视为空响应。
+// 插件的标准错误直接显示在终端中，超过 queryTimeout 未返回时结束插件
+func callPlugin(ctx context.Context, config *Config, folder, plugin string, req pluginRequest) (*pluginResponse, error) {
+	req.Version = pluginProtocolVersion
+	req.Project = folder
+	req.Dir, _ = os.Getwd()
+	req.Profile = config.ActiveProfile
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	command := batchCommand([]string{plugin})
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	setBatchCmdLine(cmd)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("超过 %v 未返回", queryTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resp pluginResponse
+	if len(bytes.TrimSpace(out)) == 0 {
+		return &resp, nil
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("响应不是有效的 JSON: %v", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// 向所有插件发送请求，出错的插件提示后跳过，返回插件名和响应
+func queryPlugins(ctx context.Context, config *Config, folder, kind string) (names []string, responses []*pluginResponse) {
+	for _, plugin := range listPlugins() {
+		resp, err := callPlugin(ctx, config, folder, plugin, pluginRequest{Type: kind})
+		if err != nil {
+			fmt.Printf("插件 %s 出错，已跳过: %v\n", pluginName(plugin), err)
+			logf("插件 %s 处理 %s 请求出错: %v", plugin, kind, err)
+			continue
+		}
+		names = append(names, pluginName(plugin))
+		responses = append(responses, resp)
+	}
+	return names, responses
+}
+
+// 当前目录可用的项目类型检测：插件识别出的类型在前，优先于内置检测，之后为内置检测，最后按 detect 配置调整顺序和禁用的类型
+func projectDetectors(ctx context.Context, config *Config, folder string) []detector {
+	var result []detector
+	_, responses := queryPlugins(ctx, config, folder, pluginDetect)
+	for _, resp := range responses {
+		if d := resp.Detector; d != nil && d.Name != "" {
+			result = append(result, d.detector())
+		}
+	}
+	return arrangeDetectors(append(result, detectors...), projectDetectConfig(config, folder))
+}
+
+// 将插件识别出的项目类型转换为内置检测的形式
+func (d *pluginDetector) detector() detector {
+	service := d.Service
+	if service == "" {
+		service = d.Name
+	}
+	result := detector{
+		Name:    d.Name,
+		Service: service,
+		Match:   func() bool { return true },
+		Actions: func(_ context.Context) []launchAction {
+			var actions []launchAction
+			for _, c := range d.Actions {
+				if len(c.Command) > 0 {
+					actions = append(actions, launchAction{Name: c.label(), Command: c.Command, Env: c.env()})
+				}
+			}
+			return actions
+		},
+	}
+	if install := d.Install; install != nil && len(install.Command) > 0 {
+		result.Install = func() *installStep {
+			return &installStep{Reason: install.Reason, Command: install.Command}
+		}
+	}
+	return result
+}
+
+// 插件在操作菜单中添加的操作，执行时在项目目录中前台运行命令，结束后回到操作菜单
+func pluginMenuActions(ctx context.Context, config *Config, folder string) []projectAction {
+	var actions []projectAction
+	_, responses := queryPlugins(ctx, config, folder, pluginActions)
+	for _, resp := range responses {
+		for _, c := range resp.Actions {
+			if len(c.Command) == 0 {
+				continue
+			}
+			c := c
+			actions = append(actions, projectAction{
+				Name: c.label(),
+				Run: func(ctx context.Context, config *Config, folder, _ string) error {
+					return runForeground(ctx, config, folder, 0, c.Command, c.env()...)
+				},
+				Stay: true,
+			})
+		}
+	}
+	return actions
+}
+
+// 启动服务前依次执行插件给出的步骤。有步骤失败时询问是否继续，按 Ctrl+C 取消时不再继续，返回是否继续启动项目
+func runPluginSteps(ctx context.Context, config *Config, folder string) bool {
+	names, responses := queryPlugins(ctx, config, folder, pluginSteps)
+	ok := true
+	for i, resp := range responses {
+		for _, step := range resp.Steps {
+			if len(step.Command) == 0 {
+				continue
+			}
+			fmt.Printf("正在执行插件 %s 的步骤 %s\n", names[i], step.label())
+			command := wrapCommand(step.Command)
+			err := runStep(ctx, stepTimeout(config, "install"), append(projectEnv(ctx, config, folder), step.env()...), command...)
+			if err == errStepCanceled {
+				fmt.Println("已取消")
+				return false
+			}
+			if err != nil {
+				fmt.Printf("✘ %s 执行失败: %v\n", step.label(), err)
+				ok = false
+			}
+		}
+	}
+	return ok || confirm(ctx, "部分插件步骤失败，是否继续启动项目？(Y/n): ")
+}
+
+// 菜单和提示中显示的名称，未填写时为命令本身
+func (c pluginCommand) label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return strings.Join(c.Command, " ")
+}
+
+// 命令追加的环境变量，按名称排序
+func (c pluginCommand) env() []string {
+	var env []string
+	for _, name := range sortedKeys(c.Env) {
+		env = append(env, name+"="+c.Env[name])
+	}
+	return env
+}
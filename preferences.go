@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectPreference 为记住的项目选择，再次启动时直接使用，不再重复询问
+type projectPreference struct {
+	Detector string `json:"detector,omitempty"` // 同时匹配多种项目类型时选择的类型
+	Action   string `json:"action,omitempty"`   // 有多种启动方式时选择的启动方式
+	Script   string `json:"script,omitempty"`   // 上次运行的脚本
+	// Always 为选择了「总是」的确认，键为确认的类别，之后不再询问
+	Always map[string]bool `json:"always,omitempty"`
+}
+
+// 记住的确认类别
+const (
+	alwaysInstall    = "install"    // 缺少依赖时先安装
+	alwaysSkipEditor = "skipEditor" // 编辑器无法打开时跳过编辑器继续启动
+)
+
+// 各项目记住的选择记录文件路径
+func preferencesPath() string {
+	return statePath("preferences.json")
+}
+
+// 读取各项目记住的选择
+func savedPreferences() map[string]projectPreference {
+	saved := make(map[string]projectPreference)
+	readJSONFile(preferencesPath(), &saved)
+	return saved
+}
+
+// 获取项目记住的选择
+func projectPreferences(folder string) projectPreference {
+	return savedPreferences()[folder]
+}
+
+// 修改项目记住的选择并写回，没有任何选择的项目从记录中删除
+func updatePreferences(folder string, update func(p *projectPreference)) {
+	saved := savedPreferences()
+	p := saved[folder]
+	update(&p)
+	if p.Detector == "" && p.Action == "" && p.Script == "" && len(p.Always) == 0 {
+		delete(saved, folder)
+	} else {
+		saved[folder] = p
+	}
+	if data, err := json.MarshalIndent(saved, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(preferencesPath()), 0755); err == nil {
+			os.WriteFile(preferencesPath(), data, 0644)
+		}
+	}
+}
+
+// 询问用户是否继续，直接回车视为同意。输入 a 表示总是同意，记住后该项目不再询问同类问题
+func confirmAlways(ctx context.Context, folder, kind, prompt string) bool {
+	if projectPreferences(folder).Always[kind] {
+		return true
+	}
+	answer, err := readLine(ctx, prompt)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(answer) {
+	case "", "y", "yes":
+		return true
+	case "a", "always":
+		updatePreferences(folder, func(p *projectPreference) {
+			if p.Always == nil {
+				p.Always = make(map[string]bool)
+			}
+			p.Always[kind] = true
+		})
+		return true
+	}
+	return false
+}
+
+// 在有多个选项的菜单中选择，记住的选项标记为「上次」，直接回车即选择该项。输入 q 或输入结束时返回 io.EOF
+func chooseRemembered(ctx context.Context, prompt string, names []string, remembered string) (int, error) {
+	last := 0
+	for i, name := range names {
+		mark := ""
+		if name == remembered {
+			last = i + 1
+			mark = "（上次）"
+		}
+		fmt.Printf("%d. %s%s\n", i+1, name, mark)
+	}
+	if last > 0 {
+		prompt = fmt.Sprintf("%s（直接回车为 %d）: ", prompt, last)
+	} else {
+		prompt += ": "
+	}
+	for {
+		input, err := readLine(ctx, prompt)
+		if err != nil {
+			return 0, err
+		}
+		if input == quitInput {
+			return 0, io.EOF
+		}
+		if input == "" && last > 0 {
+			return last, nil
+		}
+		choice, err := parseChoice(input, 1, len(names))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return choice, nil
+	}
+}
+
+// 清除项目记住的选择，之后启动时重新询问
+func forgetPreferences(_ context.Context, _ *Config, folder, _ string) error {
+	if _, ok := savedPreferences()[folder]; !ok {
+		fmt.Println("没有记住的选择")
+		return nil
+	}
+	updatePreferences(folder, func(p *projectPreference) { *p = projectPreference{} })
+	fmt.Printf("已清除 %s 记住的选择\n", folder)
+	return nil
+}
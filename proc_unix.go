@@ -0,0 +1,169 @@
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 将子进程放入独立的进程组，便于连同其派生的子进程一起结束
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// 按 cmd.exe 的规则设置 .bat/.cmd 脚本的命令行，只在 Windows 下需要
+func setBatchCmdLine(cmd *exec.Cmd) {}
+
+// 进程启动后开始跟踪其派生的子进程。Unix 下由进程组跟踪，无需额外处理
+func trackProcessTree(cmd *exec.Cmd) {}
+
+// 结束进程及其所在进程组，以及已脱离进程组的后代进程，先发送 SIGTERM，超时后发送 SIGKILL
+func killProcessTree(cmd *exec.Cmd, done <-chan error) {
+	if cmd.Process == nil {
+		return
+	}
+	pid := cmd.Process.Pid
+	tree := newProcessTree(pid, pid)
+	tree.signal(syscall.SIGTERM)
+	timeout := time.After(3 * time.Second)
+	select {
+	case <-done:
+	case <-timeout:
+		tree.signal(syscall.SIGKILL)
+		<-done
+		return
+	}
+	// 直接子进程已退出，等待其余进程退出，如 npm 派生的 node
+	for tree.alive() {
+		select {
+		case <-timeout:
+			tree.signal(syscall.SIGKILL)
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// 进程自行退出后结束进程组中残留的子进程，避免其继续占用端口。进程不在独立的进程组中时不处理
+func reapProcessTree(cmd *exec.Cmd) {
+	if cmd.Process == nil || cmd.SysProcAttr == nil || !(cmd.SysProcAttr.Setpgid || cmd.SysProcAttr.Setsid) {
+		return
+	}
+	tree := processTree{target: -cmd.Process.Pid}
+	if !tree.alive() {
+		return
+	}
+	tree.signal(syscall.SIGTERM)
+	deadline := time.Now().Add(3 * time.Second)
+	for tree.alive() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if tree.alive() {
+		tree.signal(syscall.SIGKILL)
+	}
+}
+
+// 判断进程是否仍在运行
+func processAlive(pid int) bool {
+	return pid > 0 && syscall.Kill(pid, 0) == nil
+}
+
+// 结束其他启动器实例中的进程及其所在进程组和后代进程，先发送 SIGTERM，3 秒内未退出时发送 SIGKILL。
+// 进程与当前进程同组时只结束该进程及其后代进程，返回 false 表示进程仍在运行
+func stopProcessTree(pid int) bool {
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil || pgid == syscall.Getpgrp() {
+		pgid = 0
+	}
+	tree := newProcessTree(pid, pgid)
+	tree.signal(syscall.SIGTERM)
+	deadline := time.Now().Add(3 * time.Second)
+	for tree.alive() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if tree.alive() {
+		tree.signal(syscall.SIGKILL)
+		time.Sleep(100 * time.Millisecond)
+	}
+	return !processAlive(pid)
+}
+
+// processTree 为要结束的进程树
+type processTree struct {
+	target int   // 进程组（负数）或单个进程
+	others []int // 不在该进程组中的后代进程，如通过 setsid 脱离的进程
+}
+
+// 记录进程 pid 的进程树，pgid 为 0 时不结束进程组，只结束 pid 本身及其后代进程。
+// 需要在发送信号前记录：父进程退出后，子进程会被过继给 init，无法再找到
+func newProcessTree(pid, pgid int) processTree {
+	tree := processTree{target: pid}
+	if pgid > 0 {
+		tree.target = -pgid
+	}
+	for _, p := range processDescendants(pid) {
+		if g, err := syscall.Getpgid(p); err == nil && pgid > 0 && g == pgid {
+			continue
+		}
+		tree.others = append(tree.others, p)
+	}
+	return tree
+}
+
+// 向进程树中的所有进程发送信号
+func (t processTree) signal(sig syscall.Signal) {
+	syscall.Kill(t.target, sig)
+	for _, pid := range t.others {
+		syscall.Kill(pid, sig)
+	}
+}
+
+// 进程树中是否还有进程在运行
+func (t processTree) alive() bool {
+	if syscall.Kill(t.target, 0) == nil {
+		return true
+	}
+	for _, pid := range t.others {
+		if processAlive(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// 列出进程的所有后代进程，无法执行 ps 时返回 nil
+func processDescendants(pid int) []int {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=").Output()
+	if err != nil {
+		return nil
+	}
+	children := map[int][]int{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		child, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		parent, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		children[parent] = append(children[parent], child)
+	}
+	var descendants []int
+	queue := children[pid]
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		descendants = append(descendants, p)
+		queue = append(queue, children[p]...)
+	}
+	return descendants
+}
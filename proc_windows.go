@@ -0,0 +1,163 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	// SetInformationJobObject 的 JobObjectExtendedLimitInformation 信息类
+	jobObjectExtendedLimitInformationClass = 9
+	// 作业对象的最后一个句柄关闭时结束其中的所有进程
+	jobObjectLimitKillOnJobClose = 0x2000
+	// 将进程加入作业对象所需的访问权限
+	processSetQuota = 0x0100
+)
+
+// jobObjectExtendedLimitInformation 对应 JOBOBJECT_EXTENDED_LIMIT_INFORMATION 结构
+type jobObjectExtendedLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+	IoInfo                  [6]uint64 // IO_COUNTERS
+	ProcessMemoryLimit      uintptr
+	JobMemoryLimit          uintptr
+	PeakProcessMemoryUsed   uintptr
+	PeakJobMemoryUsed       uintptr
+}
+
+// 服务进程所在的作业对象，按 PID 记录
+var processJobs = struct {
+	sync.Mutex
+	jobs map[int]syscall.Handle
+}{jobs: map[int]syscall.Handle{}}
+
+// Windows 下由作业对象跟踪进程树，无需设置进程组
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// 由 batchCommand 改为通过 cmd /d /c call 执行的脚本，自行生成命令行并用双引号括起脚本路径：
+// Go 只为含空格、制表符或引号的参数加引号，路径中的 & ( ) 等字符会被 cmd.exe 当作命令分隔符
+func setBatchCmdLine(cmd *exec.Cmd) {
+	args := cmd.Args
+	if len(args) < 5 || args[0] != "cmd" || args[1] != "/d" || args[2] != "/c" || args[3] != "call" {
+		return
+	}
+	line := []string{"cmd", "/d", "/c", "call", `"` + args[4] + `"`}
+	for _, arg := range args[5:] {
+		line = append(line, syscall.EscapeArg(arg))
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CmdLine = strings.Join(line, " ")
+}
+
+// 进程启动后将其加入新的作业对象，之后派生的子进程也会加入该作业对象，
+// 启动器退出时作业对象关闭，其中的进程随之结束。无法创建作业对象时改用 taskkill 结束进程树
+func trackProcessTree(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	job, _, _ := procCreateJobObjectW.Call(0, 0)
+	if job == 0 {
+		return
+	}
+	info := jobObjectExtendedLimitInformation{LimitFlags: jobObjectLimitKillOnJobClose}
+	if r, _, _ := procSetInformationJobObject.Call(job, jobObjectExtendedLimitInformationClass,
+		uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info)); r == 0 {
+		syscall.CloseHandle(syscall.Handle(job))
+		return
+	}
+	pid := cmd.Process.Pid
+	process, err := syscall.OpenProcess(processSetQuota|syscall.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		syscall.CloseHandle(syscall.Handle(job))
+		return
+	}
+	r, _, _ := procAssignProcessToJobObject.Call(job, uintptr(process))
+	syscall.CloseHandle(process)
+	if r == 0 {
+		syscall.CloseHandle(syscall.Handle(job))
+		return
+	}
+	processJobs.Lock()
+	processJobs.jobs[pid] = syscall.Handle(job)
+	processJobs.Unlock()
+}
+
+// 取出进程所在的作业对象
+func takeProcessJob(cmd *exec.Cmd) (syscall.Handle, bool) {
+	processJobs.Lock()
+	defer processJobs.Unlock()
+	job, ok := processJobs.jobs[cmd.Process.Pid]
+	delete(processJobs.jobs, cmd.Process.Pid)
+	return job, ok
+}
+
+// 结束进程及其派生的所有子进程
+func killProcessTree(cmd *exec.Cmd, done <-chan error) {
+	if cmd.Process == nil {
+		return
+	}
+	if job, ok := takeProcessJob(cmd); ok {
+		procTerminateJobObject.Call(uintptr(job), 1)
+		syscall.CloseHandle(job)
+	} else {
+		exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	}
+	<-done
+}
+
+// 进程自行退出后关闭其作业对象，结束残留的子进程，避免其继续占用端口
+func reapProcessTree(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	if job, ok := takeProcessJob(cmd); ok {
+		syscall.CloseHandle(job)
+	}
+}
+
+// 判断进程是否仍在运行
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
+
+// 结束其他启动器实例中的进程及其派生的所有子进程，返回 false 表示进程仍在运行
+func stopProcessTree(pid int) bool {
+	exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+	deadline := time.Now().Add(3 * time.Second)
+	for processAlive(pid) && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	return !processAlive(pid)
+}
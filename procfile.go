@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// 按优先级查找的 Procfile，Procfile.dev 为 Rails 等项目专用于开发环境的进程定义
+var procfileNames = []string{"Procfile.dev", "Procfile"}
+
+// foreman 为第一个进程分配的端口，之后每个进程加 100
+const procfileBasePort = 5000
+
+// procfileProcess 为 Procfile 中定义的一个进程
+type procfileProcess struct {
+	Name    string
+	Command string
+}
+
+// 查找当前目录中的 Procfile，不存在时返回空字符串
+func procfilePath() string {
+	for _, name := range procfileNames {
+		if fileExists(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// 判断当前目录是否为通过 Procfile 定义进程的项目
+func isProcfileProject() bool {
+	return len(procfileProcesses(procfilePath())) > 0
+}
+
+// 读取 Procfile 中的进程，每行格式为 <名称>: <命令>，忽略空行和 # 开头的注释
+func procfileProcesses(path string) []procfileProcess {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var processes []procfileProcess
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, command, ok := strings.Cut(line, ":")
+		if name = strings.TrimSpace(name); !ok || name == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		if command = strings.TrimSpace(command); command != "" {
+			processes = append(processes, procfileProcess{Name: name, Command: command})
+		}
+	}
+	return processes
+}
+
+// 列出 Procfile 的启动方式：同时启动全部进程、只启动其中一个进程，安装了 overmind 或 foreman 时可交给它们管理
+func procfileActions(_ context.Context) []launchAction {
+	path := procfilePath()
+	processes := procfileProcesses(path)
+	var actions []launchAction
+	if len(processes) > 1 {
+		names := make([]string, 0, len(processes))
+		all := launchAction{}
+		for i, p := range processes {
+			names = append(names, p.Name)
+			all.Group = append(all.Group, procfileAction(p, i))
+		}
+		all.Name = "全部进程（" + strings.Join(names, "、") + "）"
+		actions = append(actions, all)
+	}
+	for i, p := range processes {
+		actions = append(actions, procfileAction(p, i))
+	}
+	if _, err := exec.LookPath("overmind"); err == nil {
+		actions = append(actions, launchAction{Name: "overmind start -f " + path, Command: []string{"overmind", "start", "-f", path}})
+	}
+	if _, err := exec.LookPath("foreman"); err == nil {
+		actions = append(actions, launchAction{Name: "foreman start -f " + path, Command: []string{"foreman", "start", "-f", path}})
+	}
+	return actions
+}
+
+// 生成 Procfile 中一个进程的启动方式。与 foreman 一致，未设置 PORT 时按进程顺序分配端口
+func procfileAction(p procfileProcess, index int) launchAction {
+	action := launchAction{Name: p.Name + ": " + p.Command, Process: p.Name, Command: procfileCommand(p.Command)}
+	if os.Getenv("PORT") == "" {
+		action.Env = []string{"PORT=" + strconv.Itoa(procfileBasePort+100*index)}
+	}
+	return action
+}
+
+// Procfile 中的命令按 Unix shell 语法编写，含 shell 语法时与 foreman 一样通过 sh -c 执行，
+// Windows 下没有 sh（如未安装 Git for Windows）时使用 cmd
+func procfileCommand(command string) []string {
+	if !strings.ContainsAny(command, shellMetaChars) {
+		return splitCommand(command)
+	}
+	if _, err := exec.LookPath("sh"); err != nil && runtime.GOOS == "windows" {
+		return shellArgs("cmd", command)
+	}
+	return []string{"sh", "-c", command}
+}
+
+// 倒计时后在当前目录同时启动一组进程，输出带有进程名前缀。
+// 就绪检查、自动打开链接和使用统计只作用于第一个进程，避免重复
+func startProcessGroup(ctx context.Context, folder, path string, config *Config, name string, group []launchAction) {
+	services := make([]*service, 0, len(group))
+	for i, action := range group {
+		svc := newService(ctx, config, folder, wrapCommand(batchCommand(action.Command)))
+		svc.Path = path
+		svc.Process = action.Process
+		svc.LaunchEnv = action.Env
+		svc.Env = append(svc.Env, action.Env...)
+		if i > 0 {
+			svc.Health, svc.OpenURLs, svc.Stats = nil, nil, false
+		}
+		services = append(services, svc)
+	}
+	fmt.Printf("5秒后启动 %s 的 %d 个进程，Ctrl+C 停止\n", name, len(services))
+	if !waitLaunch(ctx) {
+		return
+	}
+	runServices(ctx, services)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Project 为发现的项目及其解析后的元数据。发现项目时构建一次后传递使用，
+// 无需在循环中按文件夹名反复查找备注、别名、标签和项目配置
+type Project struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"` // 远程项目为 user@host:/path，WSL 项目为 wsl:<发行版>:/path
+	Remark  string   `json:"remark,omitempty"`
+	Tags    []string `json:"tags,omitempty"`    // projects[].tags 和备注中的标签
+	Aliases []string `json:"aliases,omitempty"` // 指向该项目的别名，按字典序排列
+	Pinned  bool     `json:"pinned,omitempty"`
+	New     bool     `json:"new,omitempty"` // 最近新出现在工作目录中
+
+	SubDir bool   `json:"-"` // 为子目录（subDir），选择后列出其中的项目
+	Color  string `json:"-"` // 备注在菜单中的颜色
+	Key    rune   `json:"-"` // 绑定的快捷键，未绑定时为 0
+
+	Config  *ProjectConfig  `json:"-"` // 项目的启动配置，未配置时为 nil
+	Running []serviceRecord `json:"-"` // 各启动器实例中正在运行的该项目的服务
+
+	// 以下字段由 resolve 在项目目录中检测后填充，远程项目和 WSL 项目不检测
+	Type     string   `json:"-"` // 启动时使用的项目类型，同时匹配多种类型时为记住的选择，未识别时为空
+	Types    []string `json:"-"` // 所有匹配的项目类型
+	Commands []string `json:"-"` // 配置的启动命令和项目中可运行的脚本
+	Git      *gitInfo `json:"-"` // 不是 git 仓库时为 nil
+}
+
+// gitInfo 为项目所在 git 仓库的信息
+type gitInfo struct {
+	Branch string
+	Remote string // origin 的地址，没有 origin 时为空
+}
+
+// 是否为本机上的项目，远程项目和 WSL 项目返回 false
+func (p *Project) local() bool {
+	return p.Config == nil || p.Config.Remote == "" && p.Config.WSL == ""
+}
+
+// 项目是否带有任一标签
+func (p *Project) hasTag(tags []string) bool {
+	for _, tag := range tags {
+		if contains(tag, p.Tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// 在项目目录中检测项目类型、可运行的命令和 git 信息，完成后回到原来的目录。
+// 需要执行 git 和读取项目文件，只在需要时调用
+func (p *Project) resolve(ctx context.Context, config *Config) error {
+	if !p.local() {
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(p.Path); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	matched := matchDetectors(projectDetectors(ctx, config, p.Name))
+	p.Type, p.Types = "", nil
+	for _, d := range matched {
+		p.Types = append(p.Types, d.Name)
+	}
+	if d := preferredDetector(p.Name, matched); d != nil {
+		p.Type = d.Name
+	}
+	p.Commands = nil
+	if p.Config != nil && p.Config.Command != "" {
+		p.Commands = append(p.Commands, p.Config.Command)
+	}
+	for _, script := range projectScripts() {
+		p.Commands = append(p.Commands, script.Name)
+	}
+	p.Git = nil
+	if branch := gitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD"); branch != "" {
+		p.Git = &gitInfo{Branch: branch, Remote: gitOutput(ctx, "remote", "get-url", "origin")}
+	}
+	return nil
+}
+
+// 获取项目的标签，包括 projects[].tags 和备注中的标签
+func projectTags(config *Config, name string) []string {
+	var tags []string
+	if project := findProject(config, name); project != nil {
+		tags = append(tags, project.Tags...)
+	}
+	if r := findRemark(config, name); r != nil {
+		tags = append(tags, r.Tags...)
+	}
+	return tags
+}
+
+// 发现可直接启动的项目（不含子目录菜单），按配置的排序方式排列，不切换目录。
+// 项目类型等需要进入项目目录检测的信息不在此填充，需要时调用 resolve
+func discoverProjects(config *Config) ([]*Project, error) {
+	items, err := menuProjects(config)
+	if err != nil {
+		return nil, err
+	}
+	sortProjects(config.ProjectDir, items, config, config.Sort)
+	projects := items[:0]
+	for _, p := range items {
+		if !p.SubDir {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+// 列出工作目录下未归档的项目和子目录（含远程项目），保持目录中的顺序，不切换目录
+func menuProjects(config *Config) ([]*Project, error) {
+	folders, err := activeFolders(config)
+	if err != nil {
+		return nil, err
+	}
+	return listProjects(config, config.ProjectDir, folders), nil
+}
+
+// 为目录下的文件夹构建项目，只在工作目录下标记新项目
+func listProjects(config *Config, dir string, folders []os.DirEntry) []*Project {
+	index := newProjectIndex(config)
+	var fresh map[string]bool
+	if dir == config.ProjectDir {
+		fresh = newProjects(config)
+	}
+	// 相对路径的工作目录以启动时所在目录为准，菜单切换目录后项目路径仍然有效
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	projects := make([]*Project, 0, len(folders))
+	for _, folder := range folders {
+		p := index.project(config, dir, folder.Name())
+		p.New = fresh[p.Name]
+		projects = append(projects, p)
+	}
+	return projects
+}
+
+// 构建单个项目，路径为工作目录下的同名文件夹，不检测是否为新项目
+func newProject(config *Config, name string) *Project {
+	return newProjectIndex(config).project(config, config.ProjectDir, name)
+}
+
+// projectIndex 为构建项目时按项目名查找的别名和运行中的服务，只读取一次
+type projectIndex struct {
+	aliases map[string][]string
+	running map[string][]serviceRecord
+}
+
+// 读取配置中的别名和正在运行的服务
+func newProjectIndex(config *Config) projectIndex {
+	index := projectIndex{aliases: make(map[string][]string), running: make(map[string][]serviceRecord)}
+	for alias, name := range projectAliases(config) {
+		index.aliases[name] = append(index.aliases[name], alias)
+	}
+	for _, record := range runningServices() {
+		index.running[record.Project] = append(index.running[record.Project], record)
+	}
+	return index
+}
+
+// 按配置构建 dir 目录下项目的元数据
+func (index projectIndex) project(config *Config, dir, name string) *Project {
+	p := &Project{
+		Name:    name,
+		Path:    filepath.Join(dir, name),
+		Tags:    projectTags(config, name),
+		Aliases: index.aliases[name],
+		Config:  findProject(config, name),
+		Running: index.running[name],
+		SubDir:  contains(name, config.SubDir),
+		Key:     projectKey(config, name),
+	}
+	sort.Strings(p.Aliases)
+	if r := findRemark(config, name); r != nil {
+		p.Remark, p.Color, p.Pinned = r.Remark, r.Color, r.Pinned
+	}
+	if p.Config != nil && p.Config.Remote != "" {
+		p.Path = p.Config.Remote
+	} else if p.Config != nil && p.Config.WSL != "" {
+		p.Path = "wsl:" + p.Config.WSL
+	}
+	return p
+}
+
+// 在发现的项目中按名称查找，不存在时返回 nil
+func findDiscovered(projects []*Project, name string) *Project {
+	if i := projectNumber(projects, name); i > 0 {
+		return projects[i-1]
+	}
+	return nil
+}
+
+// 查找项目在列表中的编号，未找到时返回 0
+func projectNumber(projects []*Project, name string) int {
+	for i, p := range projects {
+		if p.Name == name {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 使用临时的状态目录，避免读取和写入本机的启动记录
+func useTempState(t *testing.T) {
+	t.Helper()
+	old := stateDir
+	stateDir = t.TempDir()
+	t.Cleanup(func() { stateDir = old })
+}
+
+// 在临时目录中创建项目文件夹和文件，以 / 结尾的为文件夹
+func makeProjectDir(t *testing.T, entries ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, entry := range entries {
+		path := filepath.Join(dir, filepath.FromSlash(entry))
+		if entry[len(entry)-1] == '/' {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDiscoverProjects(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		config  Config
+		want    []string
+	}{
+		{
+			name:    "只列出文件夹",
+			entries: []string{"api/", "web/", "notes.txt"},
+			want:    []string{"api", "web"},
+		},
+		{
+			name:    "跳过子目录和已归档的项目",
+			entries: []string{"api/", "work/", "work/inner/", "old/"},
+			config:  Config{SubDir: []string{"work"}, Archived: []string{"old"}},
+			want:    []string{"api"},
+		},
+		{
+			name:    "按名称排序",
+			entries: []string{"b/", "C/", "a/"},
+			config:  Config{Sort: "name"},
+			want:    []string{"a", "b", "C"},
+		},
+		{
+			name:    "置顶的项目在前",
+			entries: []string{"a/", "b/", "c/"},
+			config:  Config{Sort: "name", Remarks: []Remark{{Name: "c", Pinned: true}}},
+			want:    []string{"c", "a", "b"},
+		},
+		{
+			name:    "远程项目和 WSL 项目追加在本地项目之后",
+			entries: []string{"a/"},
+			config:  Config{Projects: []ProjectConfig{{Name: "remote", Remote: "me@host:/src"}, {Name: "linux", WSL: "Ubuntu:/src"}}},
+			want:    []string{"a", "remote", "linux"},
+		},
+		{
+			name:   "没有项目",
+			config: Config{},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempState(t)
+			config := tt.config
+			config.ProjectDir = makeProjectDir(t, tt.entries...)
+			wd, _ := os.Getwd()
+
+			projects, err := discoverProjects(&config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, p := range projects {
+				names = append(names, p.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("discoverProjects() = %v, want %v", names, tt.want)
+			}
+			if now, _ := os.Getwd(); now != wd {
+				t.Errorf("discoverProjects() 切换了工作目录到 %s", now)
+			}
+		})
+	}
+}
+
+func TestProjectIndexProject(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		config  Config
+		project string
+		want    Project
+	}{
+		{
+			name:    "未配置的项目",
+			project: "api",
+			want:    Project{Name: "api", Path: filepath.Join(dir, "api")},
+		},
+		{
+			name: "备注、标签和快捷键",
+			config: Config{
+				Remarks:  []Remark{{Name: "api", Remark: "后端", Color: "green", Pinned: true, Tags: []string{"go"}}},
+				Projects: []ProjectConfig{{Name: "api", Tags: []string{"work"}}},
+				Keys:     map[string]string{"a": "api"},
+			},
+			project: "api",
+			want: Project{
+				Name: "api", Path: filepath.Join(dir, "api"),
+				Remark: "后端", Color: "green", Pinned: true, Key: 'a',
+				Tags: []string{"work", "go"},
+			},
+		},
+		{
+			name: "别名按字典序排列",
+			config: Config{
+				Aliases: map[string]string{"zz": "api", "b": "api", "other": "web"},
+				Remarks: []Remark{{Name: "api", Aliases: []string{"a"}}},
+			},
+			project: "api",
+			want:    Project{Name: "api", Path: filepath.Join(dir, "api"), Aliases: []string{"a", "b", "zz"}},
+		},
+		{
+			name:    "远程项目的路径",
+			config:  Config{Projects: []ProjectConfig{{Name: "srv", Remote: "me@host:/src/srv"}}},
+			project: "srv",
+			want:    Project{Name: "srv", Path: "me@host:/src/srv"},
+		},
+		{
+			name:    "WSL 项目的路径",
+			config:  Config{Projects: []ProjectConfig{{Name: "linux", WSL: "Ubuntu:/home/me/linux"}}},
+			project: "linux",
+			want:    Project{Name: "linux", Path: "wsl:Ubuntu:/home/me/linux"},
+		},
+		{
+			name:    "子目录",
+			config:  Config{SubDir: []string{"work"}},
+			project: "work",
+			want:    Project{Name: "work", Path: filepath.Join(dir, "work"), SubDir: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempState(t)
+			config := tt.config
+			config.ProjectDir = dir
+			got := newProjectIndex(&config).project(&config, dir, tt.project)
+			// 项目配置指向 config 中的元素，单独比较
+			if want := findProject(&config, tt.project); got.Config != want {
+				t.Errorf("Config = %v, want %v", got.Config, want)
+			}
+			got.Config = nil
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("project() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
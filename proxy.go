@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// 代理环境变量，大写和小写两种写法都设置，curl 等工具只读取小写的写法
+var proxyVars = map[string][]string{
+	"http":    {"HTTP_PROXY", "http_proxy"},
+	"https":   {"HTTPS_PROXY", "https_proxy"},
+	"noProxy": {"NO_PROXY", "no_proxy"},
+}
+
+// 启动时系统环境中原有的代理变量，全局代理配置写入环境变量之前读取
+var (
+	systemHTTPSProxy = firstEnv("HTTPS_PROXY", "https_proxy")
+	systemNoProxy    = firstEnv("NO_PROXY", "no_proxy")
+)
+
+// 返回第一个非空的环境变量
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// 合并全局和项目的代理配置：项目中填写的字段覆盖全局配置，与其他配置一样 noProxy 整体替换。
+// 都未配置代理时返回 nil
+func mergeProxy(global, project *ProxyConfig) *ProxyConfig {
+	if global == nil && project == nil {
+		return nil
+	}
+	var merged ProxyConfig
+	if global != nil {
+		merged = *global
+	}
+	if project != nil {
+		if project.HTTP != "" {
+			merged.HTTP = project.HTTP
+		}
+		if project.HTTPS != "" {
+			merged.HTTPS = project.HTTPS
+		}
+		if project.NoProxy != nil {
+			merged.NoProxy = project.NoProxy
+		}
+	}
+	return &merged
+}
+
+// 代理配置对应的环境变量，未填写的字段不设置，沿用系统环境中已有的值。
+// 系统环境中原有的 NO_PROXY 保留在 noProxy 之后
+func (p *ProxyConfig) env() []string {
+	if p == nil {
+		return nil
+	}
+	var env []string
+	set := func(kind, value string) {
+		if value == "" {
+			return
+		}
+		for _, name := range proxyVars[kind] {
+			env = append(env, name+"="+value)
+		}
+	}
+	set("http", p.HTTP)
+	// 只配置了 http 时 HTTPS 请求同样经该代理
+	https := p.HTTPS
+	if https == "" && systemHTTPSProxy == "" {
+		https = p.HTTP
+	}
+	set("https", https)
+	if len(p.NoProxy) > 0 {
+		hosts := append([]string(nil), p.NoProxy...)
+		for _, host := range strings.Split(systemNoProxy, ",") {
+			if host = strings.TrimSpace(host); host != "" && !contains(host, hosts) {
+				hosts = append(hosts, host)
+			}
+		}
+		set("noProxy", strings.Join(hosts, ","))
+	}
+	return env
+}
+
+// 将全局代理配置写入启动器自身的环境变量，启动器的网络请求（如就绪检查）和所有子进程（git、npm、编辑器等）都会使用
+func applyProxyEnv(config *Config) {
+	for _, kv := range config.Proxy.env() {
+		name, value, _ := strings.Cut(kv, "=")
+		os.Setenv(name, value)
+	}
+}
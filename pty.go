@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+	"unicode/utf8"
+)
+
+// 无法分配伪终端，此时改用管道运行服务
+var errNoPTY = errors.New("无法分配伪终端")
+
+// 进程退出后等待伪终端中剩余输出的最长时间，派生的后台进程仍持有伪终端时不再等待
+const ptyDrainTimeout = time.Second
+
+// ptyProcess 为在伪终端中运行的服务进程
+type ptyProcess struct {
+	wait      func() error    // 等待进程退出并释放伪终端
+	interrupt <-chan struct{} // 在终端中按下 Ctrl+C 时关闭
+}
+
+// 启动服务进程，返回等待进程退出的函数和在终端中按下 Ctrl+C 时关闭的通道。
+// 服务开启了伪终端时在伪终端中运行，无法分配时提示后改用管道
+func startProcess(ctx context.Context, svc *service, cmd *exec.Cmd) (wait func() error, interrupt <-chan struct{}, err error) {
+	if svc.PTY {
+		p, err := startPTY(ctx, svc, cmd)
+		if err == nil {
+			return p.wait, p.interrupt, nil
+		}
+		if !errors.Is(err, errNoPTY) {
+			return nil, nil, err
+		}
+		fmt.Fprintf(svc.output(), "%v，改用管道运行\n", err)
+		svc.PTY = false
+		out := svc.capture()
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, out, out
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	return cmd.Wait, nil, nil
+}
+
+// 服务直接输出到终端时将终端切换为按键输入模式，把按键原样转发到伪终端，
+// 使交互式提示、方向键选择等可以正常使用。Ctrl+C 不转发，而是关闭返回的通道以结束服务。
+// 服务输出到多服务视图或守护进程时不转发，返回 nil
+func forwardInput(ctx context.Context, svc *service, w io.Writer, stop <-chan struct{}) (interrupt <-chan struct{}, restore func()) {
+	if svc.Output != nil {
+		return nil, func() {}
+	}
+	restore, ok := enableRawInput()
+	if !ok {
+		return nil, func() {}
+	}
+	keys := make(chan struct{})
+	go func() {
+		buf := make([]byte, utf8.UTFMax)
+		for {
+			r, err := readRune(ctx, stop)
+			if err != nil {
+				return
+			}
+			if r == 3 {
+				close(keys)
+				return
+			}
+			n := utf8.EncodeRune(buf, r)
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+	}()
+	return keys, restore
+}
+
+// 将伪终端的输出复制到服务输出，返回复制结束时关闭的通道
+func copyOutput(svc *service, r io.Reader) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		io.Copy(svc.capture(), r)
+		close(done)
+	}()
+	return done
+}
+
+// 等待伪终端的输出复制结束，超过 ptyDrainTimeout 时放弃
+func drainOutput(done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(ptyDrainTimeout):
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// 打开一对伪终端，返回主设备和从设备
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var name [128]byte
+	for _, step := range []struct {
+		req uint
+		arg unsafe.Pointer
+	}{
+		{syscall.TIOCPTYGRANT, nil},
+		{syscall.TIOCPTYUNLK, nil},
+		{syscall.TIOCPTYGNAME, unsafe.Pointer(&name[0])},
+	} {
+		if err := ioctl(master, step.req, step.arg); err != nil {
+			master.Close()
+			return nil, nil, err
+		}
+	}
+	path := string(name[:bytes.IndexByte(name[:], 0)])
+	slave, err = os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
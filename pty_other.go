@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// 其他系统暂不支持伪终端
+func startPTY(ctx context.Context, svc *service, cmd *exec.Cmd) (*ptyProcess, error) {
+	return nil, fmt.Errorf("%w: %s 暂不支持", errNoPTY, runtime.GOOS)
+}
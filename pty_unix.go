@@ -0,0 +1,88 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// winsize 对应 struct winsize
+type winsize struct {
+	Rows, Cols, X, Y uint16
+}
+
+// 在伪终端中启动进程。子进程在新的会话中运行，以伪终端为控制终端，
+// 进程组与会话 ID 均为其 PID，仍可通过 killProcessTree 结束整个进程树
+func startPTY(ctx context.Context, svc *service, cmd *exec.Cmd) (*ptyProcess, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoPTY, err)
+	}
+	resizePTY(master)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	err = cmd.Start()
+	slave.Close()
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	// 终端窗口大小变化时同步到伪终端
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	go func() {
+		for range winch {
+			resizePTY(master)
+		}
+	}()
+	output := copyOutput(svc, master)
+	stop := make(chan struct{})
+	interrupt, restore := forwardInput(ctx, svc, master, stop)
+	return &ptyProcess{
+		wait: func() error {
+			err := cmd.Wait()
+			drainOutput(output)
+			signal.Stop(winch)
+			close(winch)
+			close(stop)
+			restore()
+			master.Close()
+			return err
+		},
+		interrupt: interrupt,
+	}, nil
+}
+
+// 将当前终端的窗口大小设置到伪终端，标准输出不是终端时使用 80x24
+func resizePTY(master *os.File) {
+	var ws winsize
+	if ioctl(processStdout, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)) != nil || ws.Cols == 0 {
+		ws = winsize{Rows: 24, Cols: 80}
+	}
+	ioctl(master, syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+}
+
+// 对文件执行 ioctl。通过 SyscallConn 获取描述符，避免 Fd() 将文件切换为阻塞模式后无法通过 Close 结束读取
+func ioctl(f *os.File, req uint, arg unsafe.Pointer) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(arg))
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
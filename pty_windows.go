@@ -0,0 +1,210 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	procCreatePseudoConsole               = kernel32.NewProc("CreatePseudoConsole")
+	procResizePseudoConsole               = kernel32.NewProc("ResizePseudoConsole")
+	procClosePseudoConsole                = kernel32.NewProc("ClosePseudoConsole")
+	procInitializeProcThreadAttributeList = kernel32.NewProc("InitializeProcThreadAttributeList")
+	procUpdateProcThreadAttribute         = kernel32.NewProc("UpdateProcThreadAttribute")
+	procDeleteProcThreadAttributeList     = kernel32.NewProc("DeleteProcThreadAttributeList")
+	procGetConsoleScreenBufferInfo        = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// 将伪控制台关联到新进程的属性
+const procThreadAttributePseudoConsole = 0x00020016
+
+// 使用扩展的启动信息（STARTUPINFOEXW）创建进程
+const extendedStartupInfoPresent = 0x00080000
+
+// 没有 SIGWINCH，按该间隔检查控制台窗口大小
+const consoleResizeInterval = 500 * time.Millisecond
+
+// startupInfoEx 对应 STARTUPINFOEXW 结构
+type startupInfoEx struct {
+	syscall.StartupInfo
+	AttributeList *byte
+}
+
+// consoleScreenBufferInfo 对应 CONSOLE_SCREEN_BUFFER_INFO 结构
+type consoleScreenBufferInfo struct {
+	Size              [2]int16
+	CursorPosition    [2]int16
+	Attributes        uint16
+	Window            [4]int16 // Left、Top、Right、Bottom
+	MaximumWindowSize [2]int16
+}
+
+// 通过 ConPTY（Windows 10 1809 及以上）在伪控制台中启动进程。exec.Cmd 无法为进程关联伪控制台，
+// 这里按 cmd 的路径、参数、目录和环境变量直接调用 CreateProcess，并将 cmd.Process 设为新进程
+func startPTY(ctx context.Context, svc *service, cmd *exec.Cmd) (*ptyProcess, error) {
+	if cmd.Err != nil {
+		return nil, cmd.Err
+	}
+	if err := procCreatePseudoConsole.Find(); err != nil {
+		return nil, fmt.Errorf("%w: 当前系统不支持 ConPTY", errNoPTY)
+	}
+	var inRead, inWrite, outRead, outWrite syscall.Handle
+	if err := syscall.CreatePipe(&inRead, &inWrite, nil, 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoPTY, err)
+	}
+	if err := syscall.CreatePipe(&outRead, &outWrite, nil, 0); err != nil {
+		syscall.CloseHandle(inRead)
+		syscall.CloseHandle(inWrite)
+		return nil, fmt.Errorf("%w: %v", errNoPTY, err)
+	}
+	var console syscall.Handle
+	r, _, _ := procCreatePseudoConsole.Call(consoleSize(), uintptr(inRead), uintptr(outWrite), 0, uintptr(unsafe.Pointer(&console)))
+	// 伪控制台已持有管道的另一端
+	syscall.CloseHandle(inRead)
+	syscall.CloseHandle(outWrite)
+	input := os.NewFile(uintptr(inWrite), "conpty-input")
+	output := os.NewFile(uintptr(outRead), "conpty-output")
+	if r != 0 {
+		input.Close()
+		output.Close()
+		return nil, fmt.Errorf("%w: CreatePseudoConsole 失败 (0x%x)", errNoPTY, r)
+	}
+	closeConsole := func() {
+		procClosePseudoConsole.Call(uintptr(console))
+	}
+
+	process, err := createConsoleProcess(cmd, console)
+	if err != nil {
+		closeConsole()
+		input.Close()
+		output.Close()
+		return nil, err
+	}
+	cmd.Process = process
+
+	copied := copyOutput(svc, output)
+	stop := make(chan struct{})
+	go func() {
+		size := consoleSize()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(consoleResizeInterval):
+			}
+			if s := consoleSize(); s != size {
+				size = s
+				procResizePseudoConsole.Call(uintptr(console), size)
+			}
+		}
+	}()
+	interrupt, restore := forwardInput(ctx, svc, input, stop)
+	return &ptyProcess{
+		wait: func() error {
+			state, err := process.Wait()
+			// 关闭伪控制台后输出管道才会结束
+			closeConsole()
+			drainOutput(copied)
+			close(stop)
+			restore()
+			input.Close()
+			output.Close()
+			if err != nil {
+				return err
+			}
+			cmd.ProcessState = state
+			if !state.Success() {
+				return &exec.ExitError{ProcessState: state}
+			}
+			return nil
+		},
+		interrupt: interrupt,
+	}, nil
+}
+
+// 创建关联到伪控制台的进程
+func createConsoleProcess(cmd *exec.Cmd, console syscall.Handle) (*os.Process, error) {
+	var size uintptr
+	procInitializeProcThreadAttributeList.Call(0, 1, 0, uintptr(unsafe.Pointer(&size)))
+	list := make([]byte, size)
+	if r, _, err := procInitializeProcThreadAttributeList.Call(uintptr(unsafe.Pointer(&list[0])), 1, 0, uintptr(unsafe.Pointer(&size))); r == 0 {
+		return nil, fmt.Errorf("%w: %v", errNoPTY, err)
+	}
+	defer procDeleteProcThreadAttributeList.Call(uintptr(unsafe.Pointer(&list[0])))
+	if r, _, err := procUpdateProcThreadAttribute.Call(uintptr(unsafe.Pointer(&list[0])), 0, procThreadAttributePseudoConsole,
+		uintptr(console), unsafe.Sizeof(console), 0, 0); r == 0 {
+		return nil, fmt.Errorf("%w: %v", errNoPTY, err)
+	}
+
+	si := startupInfoEx{AttributeList: &list[0]}
+	si.Cb = uint32(unsafe.Sizeof(si))
+	args := make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		args[i] = syscall.EscapeArg(arg)
+	}
+	appName, err := syscall.UTF16PtrFromString(cmd.Path)
+	if err != nil {
+		return nil, err
+	}
+	line := strings.Join(args, " ")
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.CmdLine != "" {
+		line = cmd.SysProcAttr.CmdLine
+	}
+	cmdLine, err := syscall.UTF16PtrFromString(line)
+	if err != nil {
+		return nil, err
+	}
+	var dir *uint16
+	if cmd.Dir != "" {
+		if dir, err = syscall.UTF16PtrFromString(cmd.Dir); err != nil {
+			return nil, err
+		}
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	var pi syscall.ProcessInformation
+	if err := syscall.CreateProcess(appName, cmdLine, nil, nil, false,
+		extendedStartupInfoPresent|syscall.CREATE_UNICODE_ENVIRONMENT, environmentBlock(env), dir, &si.StartupInfo, &pi); err != nil {
+		return nil, &os.PathError{Op: "CreateProcess", Path: cmd.Path, Err: err}
+	}
+	defer syscall.CloseHandle(pi.Thread)
+	defer syscall.CloseHandle(pi.Process)
+	// 持有 pi.Process 期间进程对象不会释放，即使进程已退出也能打开
+	return os.FindProcess(int(pi.ProcessId))
+}
+
+// 生成 CreateProcess 所需的环境变量块：每项以 NUL 结尾，最后再加一个 NUL
+func environmentBlock(env []string) *uint16 {
+	var block []uint16
+	for _, kv := range env {
+		if strings.ContainsRune(kv, 0) {
+			continue
+		}
+		block = append(block, utf16.Encode([]rune(kv))...)
+		block = append(block, 0)
+	}
+	block = append(block, 0)
+	return &block[0]
+}
+
+// 获取当前控制台窗口大小，按 COORD 结构按值传递的方式打包；标准输出不是控制台时为 80x24
+func consoleSize() uintptr {
+	cols, rows := 80, 24
+	var info consoleScreenBufferInfo
+	if r, _, _ := procGetConsoleScreenBufferInfo.Call(processStdout.Fd(), uintptr(unsafe.Pointer(&info))); r != 0 {
+		cols = int(info.Window[2]-info.Window[0]) + 1
+		rows = int(info.Window[3]-info.Window[1]) + 1
+	}
+	return uintptr(uint16(cols)) | uintptr(uint16(rows))<<16
+}
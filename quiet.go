@@ -0,0 +1,20 @@
+package main
+
+import "os"
+
+// 命令行 --quiet 或 -q，只显示错误信息
+var quietFlag bool
+
+// 程序启动时的标准输出。服务、each 中的命令、依赖安装等子进程的输出始终写到这里，不受 --quiet 影响
+var processStdout = os.Stdout
+
+// 开启安静模式：将 os.Stdout 替换为空设备，隐藏启动器自身的提示、菜单和进度，
+// 错误信息通过标准错误输出
+func enableQuiet() error {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	os.Stdout = null
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// 在菜单中按编号选择项目并输入新的备注，保存到配置文件
+func editRemarkFromMenu(ctx context.Context, config *Config, projects []*Project) error {
+	choice, err := getUserChoice(ctx, "请输入要编辑备注的项目编号: ", len(projects))
+	if err != nil {
+		return err
+	}
+	name := projects[choice-1].Name
+	if remark := projects[choice-1].Remark; remark != "" {
+		fmt.Printf("当前备注：%s\n", remark)
+	}
+	input, err := readLine(ctx, "新的备注（直接回车保持不变，输入 - 清除）: ")
+	if err != nil || input == "" {
+		return nil
+	}
+	if input == "-" {
+		input = ""
+	}
+	if err := updateRemark(name, input); err != nil {
+		return err
+	}
+	config.Remarks = setRemark(config.Remarks, name, input)
+	return nil
+}
+
+// 修改配置文件中项目的备注，备注为空时删除。在原始配置上修改，避免写入配置档覆盖的值
+func updateRemark(name, remark string) error {
+	raw, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	raw.Remarks = setRemark(raw.Remarks, name, remark)
+	return writeConfig(raw)
+}
+
+// 设置备注列表中项目的备注，新项目追加在末尾。备注为空且没有标签等其他内容时移除该项目
+func setRemark(remarks []Remark, name, remark string) []Remark {
+	result := make([]Remark, 0, len(remarks)+1)
+	found := false
+	for _, r := range remarks {
+		if r.Name == name {
+			found = true
+			r.Remark = remark
+			if r.empty() {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	if !found && remark != "" {
+		result = append(result, Remark{Name: name, Remark: remark})
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// virtualEntry 表示不在工作目录中的项目，用于和本地文件夹一起显示在菜单中
+type virtualEntry struct {
+	name string
+}
+
+func (e virtualEntry) Name() string               { return e.name }
+func (e virtualEntry) IsDir() bool                { return true }
+func (e virtualEntry) Type() fs.FileMode          { return fs.ModeDir }
+func (e virtualEntry) Info() (fs.FileInfo, error) { return nil, fs.ErrNotExist }
+
+// 获取配置中的远程项目和 WSL 项目，作为菜单项追加到本地项目之后
+func remoteEntries(config *Config) []os.DirEntry {
+	var entries []os.DirEntry
+	for _, project := range config.Projects {
+		if project.Remote != "" || project.WSL != "" {
+			entries = append(entries, virtualEntry{name: project.Name})
+		}
+	}
+	return entries
+}
+
+// 拆分 user@host:/path 格式的远程路径
+func parseRemote(remote string) (host, path string, err error) {
+	i := strings.Index(remote, ":")
+	if i <= 0 || i == len(remote)-1 {
+		return "", "", fmt.Errorf("远程路径格式错误，应为 user@host:/path: %s", remote)
+	}
+	return remote[:i], remote[i+1:], nil
+}
+
+// 通过 VS Code Remote-SSH 打开远程项目，配置了启动命令时通过 SSH 在远程运行
+func launchRemote(ctx context.Context, project *ProjectConfig, config *Config) error {
+	if offline() {
+		return fmt.Errorf("离线模式下无法连接远程项目 %s", project.Remote)
+	}
+	if project.Command == "" {
+		return openEditor(ctx, config, project)
+	}
+	if !openEditorOrContinue(ctx, config, project.Name, project) || !askInputs(ctx, config, project.Name) {
+		return nil
+	}
+	host, command, err := remoteCommand(config, project)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("5秒后在 %s 上启动服务，Ctrl+C 停止\n", host)
+	if !waitLaunch(ctx) {
+		return nil
+	}
+	if err := newService(ctx, config, project.Name, command).run(ctx); err != nil {
+		fmt.Println("无法启动远程服务:", err)
+	}
+	return nil
+}
+
+// 生成通过 SSH 在远程项目目录中执行启动命令的命令行
+func remoteCommand(config *Config, project *ProjectConfig) (host string, command []string, err error) {
+	host, path, err := parseRemote(project.Remote)
+	if err != nil {
+		return "", nil, err
+	}
+	line, err := expandCommand(config, project.Name, project.Command)
+	if err != nil {
+		return "", nil, err
+	}
+	remoteDir := path
+	if project.WorkDir != "" {
+		remoteDir = strings.TrimSuffix(path, "/") + "/" + project.WorkDir
+	}
+	return host, []string{"ssh", "-t", host, "cd " + shellQuote(remoteDir) + " && " + line}, nil
+}
+
+// 为 POSIX shell 添加单引号，用于拼接在远程执行的命令
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
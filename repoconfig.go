@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 项目内共享的启动配置文件名，随仓库提交，团队成员共用
+const repoConfigFile = ".quickstart.json"
+
+// 读取项目目录中共享的启动配置：优先读取 .quickstart.json，其次为 package.json 中的 quickstart 字段，
+// 都没有时返回 nil。格式与配置文件 projects 中的一项相同，name、remote、wsl 和 script 不生效
+func readRepoConfig(dir string) (map[string]json.RawMessage, string, error) {
+	path := filepath.Join(dir, repoConfigFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		var pkg struct {
+			Quickstart map[string]json.RawMessage `json:"quickstart"`
+		}
+		path = filepath.Join(dir, "package.json")
+		if readJSONFile(path, &pkg) != nil || pkg.Quickstart == nil {
+			return nil, "", nil
+		}
+		data, _ = json.Marshal(pkg.Quickstart)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, path, err
+	}
+	// 仓库中的配置不能改变项目的位置，也不能指定在启动器中执行的脚本
+	for _, key := range []string{"name", "remote", "wsl", "script"} {
+		delete(raw, key)
+	}
+	// 先解析一次，字段类型有误时整个文件忽略，不影响其他配置
+	var project ProjectConfig
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, path, err
+	}
+	return raw, path, nil
+}
+
+// 各项目仓库中共享的启动配置，优先于全局配置和配置档；仓库配置有误时提示并忽略
+func repoLayer(config *Config) configLayer {
+	layer := configLayer{Name: "项目配置", Projects: map[string]map[string]json.RawMessage{}, Sources: map[string]string{}}
+	if config.ProjectDir == "" {
+		return layer
+	}
+	candidates, err := projectCandidates(config)
+	if err != nil {
+		return layer
+	}
+	for _, c := range candidates {
+		// 不同子目录中的同名项目共用同一项配置，以先出现的为准
+		if _, ok := layer.Projects[c.Name]; ok {
+			continue
+		}
+		if project := findProject(config, c.Name); project != nil && (project.Remote != "" || project.WSL != "") {
+			continue
+		}
+		repo, path, err := readRepoConfig(filepath.Join(config.ProjectDir, c.Dir, c.Name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "项目配置 %s 有误，已忽略: %v\n", path, err)
+			continue
+		}
+		if repo == nil {
+			continue
+		}
+		layer.Projects[c.Name] = repo
+		layer.Order = append(layer.Order, c.Name)
+		layer.Sources[c.Name] = path
+	}
+	return layer
+}
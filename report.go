@@ -0,0 +1,250 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// 问题报告中替换敏感内容的占位符
+const redacted = "REDACTED"
+
+// 配置中值需要隐藏的字段名包含的关键字
+var sensitiveKeys = []string{"token", "password", "passwd", "secret", "apikey", "api_key"}
+
+// 执行 report 子命令：将启动器日志、隐藏密钥后的配置、系统信息、工具版本和最近一次失败的命令输出打包为 zip，
+// 便于附加到 issue 中。未指定文件时在当前目录生成 quickstart-report-<时间>.zip
+func runReportCommand(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("用法: quickstart report [文件]")
+	}
+	path := "quickstart-report-" + time.Now().Format("20060102-150405") + ".zip"
+	if len(args) == 1 {
+		path = args[0]
+	}
+	files := []reportFile{
+		{"system.txt", []byte(reportSystem(ctx))},
+		{"tools.txt", []byte(reportTools(ctx))},
+	}
+	if data, err := os.ReadFile(configPath); err == nil {
+		if config, err := redactConfig(data); err == nil {
+			files = append(files, reportFile{"config.json", config})
+		} else {
+			// 无法解析时不打包原文，避免泄露密钥
+			files = append(files, reportFile{"config-error.txt", []byte(fmt.Sprintf("配置文件 %s 无法解析: %v\n", configPath, err))})
+		}
+	}
+	for _, log := range []string{launcherLogPath() + ".1", launcherLogPath(), lastFailurePath()} {
+		if data, err := os.ReadFile(log); err == nil {
+			files = append(files, reportFile{"logs/" + filepath.Base(log), data})
+		}
+	}
+	if err := writeZip(path, files); err != nil {
+		return err
+	}
+	fmt.Printf("问题报告已生成: %s\n", path)
+	fmt.Println("配置中的环境变量和密钥已隐藏，附加到 issue 前请检查命令和日志中是否还有不便公开的内容")
+	return nil
+}
+
+// reportFile 为问题报告中的一个文件
+type reportFile struct {
+	name string
+	data []byte
+}
+
+// 将文件写入 zip
+func writeZip(path string, files []reportFile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := zip.NewWriter(file)
+	for _, f := range files {
+		entry, err := w.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: time.Now()})
+		if err == nil {
+			_, err = entry.Write(f.data)
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// 问题报告中的版本、系统和路径信息
+func reportSystem(ctx context.Context) string {
+	ver, rev, built := buildInfo()
+	var b strings.Builder
+	fmt.Fprintf(&b, "quickstart: %s（commit %s，built %s）\n", ver, rev, built)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "系统: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if v := osVersion(ctx); v != "" {
+		fmt.Fprintf(&b, "系统版本: %s\n", v)
+	}
+	fmt.Fprintf(&b, "配置文件: %s\n", configPath)
+	fmt.Fprintf(&b, "状态目录: %s\n", stateDir)
+	if config, err := loadConfig(); err == nil && config.ActiveProfile != "" {
+		fmt.Fprintf(&b, "配置档: %s\n", config.ActiveProfile)
+	}
+	for _, name := range []string{"TERM", "SHELL", "COMSPEC", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			fmt.Fprintf(&b, "%s: %s\n", name, v)
+		}
+	}
+	fmt.Fprintf(&b, "生成时间: %s\n", time.Now().Format(time.RFC3339))
+	return b.String()
+}
+
+// 获取操作系统版本，失败时返回空字符串
+func osVersion(ctx context.Context) string {
+	var out []byte
+	var err error
+	if runtime.GOOS == "windows" {
+		out, err = queryOutput(ctx, "cmd", "/c", "ver")
+	} else {
+		out, err = queryOutput(ctx, "uname", "-srv")
+	}
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// 问题报告中环境检查所列开发工具的版本
+func reportTools(ctx context.Context) string {
+	var b strings.Builder
+	for _, tool := range doctorTools {
+		if _, err := exec.LookPath(tool.Name); err != nil {
+			fmt.Fprintf(&b, "%-8s 未安装\n", tool.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "%-8s %s\n", tool.Name, toolVersion(ctx, tool.Name, tool.Args...))
+	}
+	return b.String()
+}
+
+// 隐藏配置中的敏感内容：env 中除密钥引用（如 op://）外的值、名称像密钥的字段、链接中的密码和敏感查询参数
+func redactConfig(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redactValue("", v), "", "  ")
+}
+
+// 递归隐藏 JSON 值中的敏感内容，key 为值所在的字段名
+func redactValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if env, ok := x.(map[string]any); ok && k == "env" {
+				for name, value := range env {
+					if s, ok := value.(string); !ok || !isSecretRef(s) {
+						env[name] = redacted
+					}
+				}
+				continue
+			}
+			v[k] = redactValue(k, x)
+			if k == "hooks" {
+				v[k] = redactHooks(v[k])
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactValue(key, v[i])
+		}
+	case string:
+		if isSensitiveKey(key) {
+			return redacted
+		}
+		return redactURL(v)
+	}
+	return v
+}
+
+// 隐藏事件钩子中的地址：Slack、Discord 等的 webhook 地址本身就是密钥，只保留协议和主机
+func redactHooks(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if s, ok := x.(string); ok && k == "url" {
+				v[k] = redactHookURL(s)
+				continue
+			}
+			v[k] = redactHooks(x)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactHooks(v[i])
+		}
+	}
+	return v
+}
+
+// 将钩子地址的路径和查询参数替换为占位符，无法解析时整个替换
+func redactHookURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	if u.Path == "" && u.RawQuery == "" && u.User == nil {
+		return s
+	}
+	return u.Scheme + "://" + u.Host + "/" + redacted
+}
+
+// 判断字段名是否像密钥
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// 隐藏链接中的密码和名称像密钥的查询参数，不是链接时原样返回
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	changed := false
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+			changed = true
+		}
+	}
+	query := u.Query()
+	for name := range query {
+		if isSensitiveKey(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
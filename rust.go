@@ -0,0 +1,15 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Rust 项目默认 cargo run，安装了 cargo-watch 时可选择文件变动自动重启
+func rustActions(_ context.Context) []launchAction {
+	actions := []launchAction{{Name: "cargo run", Command: []string{"cargo", "run"}}}
+	if _, err := exec.LookPath("cargo-watch"); err == nil {
+		actions = append(actions, launchAction{Name: "cargo watch -x run", Command: []string{"cargo", "watch", "-x", "run"}})
+	}
+	return actions
+}
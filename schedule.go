@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 守护进程错过定时任务（如电脑休眠）后补执行的最长时间，超过后跳过，避免醒来时启动早已过时的任务
+const scheduleCatchUp = 5 * time.Minute
+
+// cronField 为 cron 表达式中一个字段允许的值
+type cronField map[int]bool
+
+// cronSchedule 为解析后的 cron 表达式：分 时 日 月 周
+type cronSchedule struct {
+	minute, hour, day, month, weekday cronField
+	// 日和周都不为 * 时满足其一即可，与 cron 的行为一致
+	anyDay, anyWeekday bool
+}
+
+// cron 表达式中月和周可以使用的英文缩写，不区分大小写
+var (
+	cronMonthNames   = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	cronWeekdayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+)
+
+// 解析 5 个字段的 cron 表达式，如 "45 8 * * 1-5"。字段支持 *、列表（1,3）、范围（1-5）和步长（*/15、0-30/10），
+// 月和周可以使用英文缩写（jan-dec、mon-fri），周中 0 和 7 都表示周日
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron 表达式 %q 应为 5 个字段：分 时 日 月 周", spec)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]map[string]int{3: cronMonthNames, 4: cronWeekdayNames}
+	var parsed [5]cronField
+	for i, field := range fields {
+		values, err := parseCronField(field, bounds[i][0], bounds[i][1], names[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron 表达式 %q 的第 %d 个字段 %s 无效: %v", spec, i+1, field, err)
+		}
+		parsed[i] = values
+	}
+	if parsed[4][7] {
+		parsed[4][0] = true
+	}
+	return &cronSchedule{
+		minute: parsed[0], hour: parsed[1], day: parsed[2], month: parsed[3], weekday: parsed[4],
+		anyDay: fields[2] == "*", anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// 解析 cron 表达式的一个字段，names 为该字段可以使用的英文缩写
+func parseCronField(field string, min, max int, names map[string]int) (cronField, error) {
+	values := make(cronField)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("步长 %s 无效", stepPart)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(from, names); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(to, names); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("取值范围为 %d-%d", min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// 解析字段中的一个值，可以是数字或英文缩写
+func cronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s 不是数字", s)
+	}
+	return v, nil
+}
+
+// 时间（精确到分钟）是否满足表达式
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	day, weekday := c.day[t.Day()], c.weekday[int(t.Weekday())]
+	if !c.anyDay && !c.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// 表达式在 after 之后第一次满足的时间，一年内都不满足时返回零值
+func (c *cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(1, 0, 0); t.Before(end); t = t.Add(time.Minute) {
+		if c.matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// 定时任务的目标名称，用于输出
+func (s ScheduleConfig) target() string {
+	if s.Group != "" {
+		return "项目组 " + s.Group
+	}
+	return s.Project
+}
+
+// 在后台按配置的 schedules 定时启动和停止项目或项目组，每分钟检查一次并重新读取配置，修改配置后无需重启守护进程
+func (d *daemon) runSchedules() {
+	if config, err := loadConfig(); err == nil && len(config.Schedules) > 0 {
+		now := time.Now()
+		for _, s := range config.Schedules {
+			for _, action := range []struct{ name, spec string }{{"启动", s.Start}, {"停止", s.Stop}} {
+				if action.spec == "" {
+					continue
+				}
+				cron, err := parseCron(action.spec)
+				if err != nil {
+					fmt.Printf("定时任务 %s: %v\n", s.target(), err)
+					continue
+				}
+				if next := cron.next(now); !next.IsZero() {
+					fmt.Printf("定时%s %s：下次 %s\n", action.name, s.target(), next.Format("2006-01-02 15:04"))
+				}
+			}
+		}
+	}
+
+	last := time.Now().Truncate(time.Minute)
+	for {
+		time.Sleep(time.Until(last.Add(time.Minute)) + time.Second)
+		now := time.Now().Truncate(time.Minute)
+		if now.Sub(last) > scheduleCatchUp {
+			last = now.Add(-scheduleCatchUp)
+		}
+		config, err := loadConfig()
+		if err != nil {
+			last = now
+			continue
+		}
+		for t := last.Add(time.Minute); !t.After(now); t = t.Add(time.Minute) {
+			for _, s := range config.Schedules {
+				// 启动项目组需要等待逐批就绪，在后台执行，不影响其他定时任务
+				if cron, err := parseCron(s.Stop); err == nil && cron.matches(t) {
+					go d.runScheduled(config, s, false)
+				}
+				if cron, err := parseCron(s.Start); err == nil && cron.matches(t) {
+					go d.runScheduled(config, s, true)
+				}
+			}
+		}
+		last = now
+	}
+}
+
+// 执行一个定时任务：启动或停止项目，项目组按依赖顺序逐批启动、逆序停止
+func (d *daemon) runScheduled(config *Config, s ScheduleConfig, start bool) {
+	var levels [][]string
+	if s.Group != "" {
+		group, ok := config.Groups[s.Group]
+		if !ok {
+			fmt.Printf("定时任务的项目组 %s 不存在\n", s.Group)
+			return
+		}
+		var err error
+		if levels, err = groupLevels(config, group.Projects); err != nil {
+			fmt.Printf("项目组 %s 的配置有误: %v\n", s.Group, err)
+			return
+		}
+	} else {
+		levels = [][]string{{resolveAlias(config, s.Project)}}
+	}
+
+	if !start {
+		fmt.Printf("定时停止 %s\n", s.target())
+		logf("定时停止 %s", s.target())
+		for i := len(levels) - 1; i >= 0; i-- {
+			for _, project := range levels[i] {
+				if svc := d.service(project); svc != nil && svc.running() {
+					svc.shutdown()
+				}
+			}
+		}
+		return
+	}
+
+	fmt.Printf("定时启动 %s\n", s.target())
+	logf("定时启动 %s", s.target())
+	for _, level := range levels {
+		var batch []*daemonService
+		for _, project := range level {
+			if svc := d.service(project); svc != nil && svc.running() {
+				batch = append(batch, svc)
+				continue
+			}
+			if _, err := d.launch(project); err != nil {
+				fmt.Printf("定时启动 %s 失败: %v\n", project, err)
+				return
+			}
+			batch = append(batch, d.service(project))
+		}
+		// 与 quickstart group 相同，等上一批全部就绪后再启动下一批
+		for _, svc := range batch {
+			<-svc.svc.ready
+			if !svc.svc.readyOK {
+				fmt.Printf("%s 未就绪，%s 的后续项目不再启动\n", svc.info.Project, s.target())
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"字段数不足", "* * * *"},
+		{"字段数过多", "* * * * * *"},
+		{"分钟超出范围", "60 * * * *"},
+		{"日小于 1", "* * 0 * *"},
+		{"周超出范围", "* * * * 8"},
+		{"步长为 0", "*/0 * * * *"},
+		{"步长不是数字", "*/x * * * *"},
+		{"范围颠倒", "5-1 * * * *"},
+		{"不是数字", "a * * * *"},
+		{"范围的结束不是数字", "1-x * * * *"},
+		{"未知的月份缩写", "* * * foo *"},
+		{"周的缩写不能用于月", "* * * mon *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCron(tt.spec); err == nil {
+				t.Errorf("parseCron(%q) 没有返回错误", tt.spec)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"工作日早上，周五之后为下周一", "45 8 * * 1-5", at(10, 16, 9, 0), at(10, 19, 8, 45)},
+		{"当天还未到时间", "45 8 * * 1-5", at(10, 15, 8, 0), at(10, 15, 8, 45)},
+		{"正好在触发时间时取下一次", "45 8 * * 1-5", at(10, 15, 8, 45), at(10, 16, 8, 45)},
+		{"步长", "*/15 * * * *", at(10, 15, 10, 7), at(10, 15, 10, 15)},
+		{"范围加步长", "0-30/10 9 * * *", at(10, 15, 9, 25), at(10, 15, 9, 30)},
+		{"范围加步长跨到下一小时", "0-30/10 9 * * *", at(10, 15, 9, 31), at(10, 16, 9, 0)},
+		{"单个值加步长到最大值", "50/5 * * * *", at(10, 15, 9, 56), at(10, 15, 10, 50)},
+		{"列表", "0 0 1,15 * *", at(10, 2, 0, 0), at(10, 15, 0, 0)},
+		{"日和周都指定时满足日即可", "0 0 13 * 5", at(10, 12, 0, 0), at(10, 13, 0, 0)},
+		{"日和周都指定时满足周即可", "0 0 13 * 5", at(10, 13, 0, 0), at(10, 16, 0, 0)},
+		{"周为 * 时只看日", "0 0 13 * *", at(10, 14, 0, 0), at(11, 13, 0, 0)},
+		{"周日可以写为 7", "0 0 * * 7", at(10, 15, 0, 0), at(10, 18, 0, 0)},
+		{"月和周的缩写", "0 12 * jan MON", at(10, 15, 0, 0), time.Date(2027, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{"周的缩写范围", "0 9 * * mon-fri", at(10, 17, 0, 0), at(10, 19, 9, 0)},
+		{"一年内不会触发", "0 0 30 2 *", at(10, 15, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cron.next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("next(%s) = %s, want %s", tt.after.Format("2006-01-02 15:04 Mon"), got.Format("2006-01-02 15:04 Mon"), tt.want.Format("2006-01-02 15:04 Mon"))
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// 脚本钩子的最长执行时间和最大执行步数，防止死循环卡住菜单
+const (
+	scriptTimeout  = 5 * time.Second
+	scriptMaxSteps = 10000000
+)
+
+// 脚本可以定义的钩子
+const (
+	hookCommand = "command" // 返回服务启动命令，None 表示按原方式启动
+	hookLaunch  = "launch"  // 返回 False 或跳过原因时不启动服务
+	hookBadge   = "badge"   // 返回菜单中显示在项目后面的状态
+)
+
+// 已加载的脚本，键为脚本内容，配置修改后按新内容重新加载
+var (
+	scriptMu    sync.Mutex
+	scriptCache = map[string]*loadedScript{}
+)
+
+// loadedScript 为执行过顶层代码的脚本
+type loadedScript struct {
+	globals starlark.StringDict
+	err     error
+}
+
+// 读取脚本内容：以 .star 结尾且不含换行时视为脚本文件路径，相对路径相对于配置文件所在目录，否则为脚本本身
+func scriptSource(script string) (name, src string, err error) {
+	if !strings.HasSuffix(script, ".star") || strings.Contains(script, "\n") {
+		return "config.json", script, nil
+	}
+	path := script
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(configPath), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("无法读取脚本 %s: %v", path, err)
+	}
+	return path, string(data), nil
+}
+
+// 加载脚本并执行顶层代码，结果按脚本内容缓存
+func loadScript(script string) (starlark.StringDict, error) {
+	name, src, err := scriptSource(script)
+	if err != nil {
+		return nil, err
+	}
+	scriptMu.Lock()
+	defer scriptMu.Unlock()
+	if s, ok := scriptCache[src]; ok {
+		return s.globals, s.err
+	}
+	thread := newScriptThread(".")
+	globals, err := starlark.ExecFile(thread, name, src, scriptBuiltins)
+	if err != nil {
+		err = fmt.Errorf("脚本 %s 有误: %v", name, err)
+	}
+	scriptCache[src] = &loadedScript{globals: globals, err: err}
+	return globals, err
+}
+
+// 查找项目可用的钩子：先在项目脚本中查找，其次为全局脚本。未定义时返回 nil
+func findHook(config *Config, folder, hook string) (starlark.Callable, error) {
+	var scripts []string
+	if project := findProject(config, folder); project != nil && project.Script != "" {
+		scripts = append(scripts, project.Script)
+	}
+	if config.Script != "" {
+		scripts = append(scripts, config.Script)
+	}
+	for _, script := range scripts {
+		globals, err := loadScript(script)
+		if err != nil {
+			return nil, err
+		}
+		if fn, ok := globals[hook].(starlark.Callable); ok {
+			return fn, nil
+		}
+	}
+	return nil, nil
+}
+
+// 调用项目的钩子，参数为项目信息 ctx。未定义钩子时返回 nil
+func callHook(config *Config, folder, hook string) (starlark.Value, error) {
+	fn, err := findHook(config, folder, hook)
+	if err != nil || fn == nil {
+		return nil, err
+	}
+	data := projectCommandData(config, folder)
+	thread := newScriptThread(data.ProjectPath)
+	timer := time.AfterFunc(scriptTimeout, func() { thread.Cancel(fmt.Sprintf("超过 %v 未完成", scriptTimeout)) })
+	defer timer.Stop()
+	result, err := starlark.Call(thread, fn, starlark.Tuple{scriptContext(config, folder, data)}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("脚本钩子 %s 执行失败: %v", hook, err)
+	}
+	return result, nil
+}
+
+// 钩子的参数 ctx：项目名、路径、端口、配置档、启动参数、配置的启动命令，以及当前是星期几（1 为周一，7 为周日）和几点
+func scriptContext(config *Config, folder string, data commandData) starlark.Value {
+	inputs := starlark.NewDict(len(data.Inputs))
+	for _, name := range sortedKeys(data.Inputs) {
+		inputs.SetKey(starlark.String(name), starlark.String(data.Inputs[name]))
+	}
+	command := ""
+	if project := findProject(config, folder); project != nil {
+		command = project.Command
+	}
+	now := time.Now()
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return starlarkstruct.FromStringDict(starlark.String("ctx"), starlark.StringDict{
+		"project": starlark.String(folder),
+		"path":    starlark.String(data.ProjectPath),
+		"port":    starlark.MakeInt(data.Port),
+		"profile": starlark.String(data.Profile),
+		"inputs":  inputs,
+		"command": starlark.String(command),
+		"weekday": starlark.MakeInt(weekday),
+		"hour":    starlark.MakeInt(now.Hour()),
+	})
+}
+
+// 创建执行脚本的线程，dir 为内置函数执行命令和检查文件的目录。print 输出到终端
+func newScriptThread(dir string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  "quickstart",
+		Print: func(_ *starlark.Thread, msg string) { fmt.Println(msg) },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	thread.SetLocal("dir", dir)
+	return thread
+}
+
+// 脚本中可以使用的内置函数和模块
+var scriptBuiltins = starlark.StringDict{
+	"env":    starlark.NewBuiltin("env", scriptEnv),
+	"exists": starlark.NewBuiltin("exists", scriptExists),
+	"run":    starlark.NewBuiltin("run", scriptRun),
+	"git":    starlark.NewBuiltin("git", scriptGit),
+	"branch": starlark.NewBuiltin("branch", scriptBranch),
+	"time":   startime.Module,
+}
+
+// env(name, default="")：读取系统环境变量
+func scriptEnv(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, value string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "default?", &value); err != nil {
+		return nil, err
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		value = v
+	}
+	return starlark.String(value), nil
+}
+
+// exists(path)：判断项目目录下的文件或目录是否存在
+func scriptExists(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "path", &path); err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(scriptDir(thread), path)
+	}
+	return starlark.Bool(fileExists(path)), nil
+}
+
+// run(ctx, name, *args)：在项目目录中执行命令并返回去掉首尾空白的标准输出，命令失败时钩子执行失败
+func scriptRun(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	command, err := scriptArgs(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	out, err := scriptCommandOutput(scriptDir(thread), command)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", strings.Join(command, " "), err)
+	}
+	return starlark.String(out), nil
+}
+
+// git(*args)：在项目目录中执行 git 命令并返回输出，失败时（如不是 git 仓库）返回空字符串
+func scriptGit(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	command, err := scriptArgs(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := scriptCommandOutput(scriptDir(thread), append([]string{"git"}, command...))
+	return starlark.String(out), nil
+}
+
+// branch()：项目当前的 git 分支，不是 git 仓库时返回空字符串
+func scriptBranch(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	out, _ := scriptCommandOutput(scriptDir(thread), []string{"git", "rev-parse", "--abbrev-ref", "HEAD"})
+	return starlark.String(out), nil
+}
+
+// 内置函数执行命令和检查文件的目录
+func scriptDir(thread *starlark.Thread) string {
+	if dir, ok := thread.Local("dir").(string); ok && dir != "" {
+		return dir
+	}
+	return "."
+}
+
+// 将内置函数的参数转换为命令参数，参数必须为字符串
+func scriptArgs(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) ([]string, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: 不支持关键字参数", b.Name())
+	}
+	command := make([]string, len(args))
+	for i, arg := range args {
+		s, ok := starlark.AsString(arg)
+		if !ok {
+			return nil, fmt.Errorf("%s: 第 %d 个参数应为字符串，而不是 %s", b.Name(), i+1, arg.Type())
+		}
+		command[i] = s
+	}
+	return command, nil
+}
+
+// 在指定目录执行命令并返回去掉首尾空白的标准输出，超过 queryTimeout 时结束
+func scriptCommandOutput(dir string, command []string) (string, error) {
+	if len(command) == 0 {
+		return "", fmt.Errorf("缺少命令")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// 调用 command 钩子确定服务启动命令：返回字符串时按配置的启动命令处理，返回列表时为命令及参数，
+// 返回 None 或未定义钩子时 ok 为 false
+func scriptCommand(config *Config, folder string) (command []string, ok bool, err error) {
+	result, err := callHook(config, folder, hookCommand)
+	if err != nil || result == nil || result == starlark.None {
+		return nil, false, err
+	}
+	switch v := result.(type) {
+	case starlark.String:
+		if v == "" {
+			return nil, false, nil
+		}
+		return commandArgs(config, string(v)), true, nil
+	case *starlark.List, starlark.Tuple:
+		iter := starlark.Iterate(v)
+		defer iter.Done()
+		var x starlark.Value
+		for iter.Next(&x) {
+			s, ok := starlark.AsString(x)
+			if !ok {
+				return nil, false, fmt.Errorf("脚本钩子 %s 返回的列表中应为字符串，而不是 %s", hookCommand, x.Type())
+			}
+			command = append(command, s)
+		}
+		return command, len(command) > 0, nil
+	}
+	return nil, false, fmt.Errorf("脚本钩子 %s 应返回字符串、列表或 None，而不是 %s", hookCommand, result.Type())
+}
+
+// 调用 launch 钩子判断是否启动服务，返回 False 或非空字符串时不启动并提示原因。钩子出错时不启动
+func scriptAllowsLaunch(config *Config, folder string) bool {
+	result, err := callHook(config, folder, hookLaunch)
+	if err != nil {
+		failf(exitConfigError, "%v", err)
+		return false
+	}
+	switch v := result.(type) {
+	case nil, starlark.NoneType:
+		return true
+	case starlark.String:
+		if v == "" {
+			return true
+		}
+		fmt.Printf("脚本跳过了 %s 的启动: %s\n", folder, string(v))
+		return false
+	}
+	if !result.Truth() {
+		fmt.Printf("脚本跳过了 %s 的启动\n", folder)
+		return false
+	}
+	return true
+}
+
+// 调用 badge 钩子获取菜单中显示的项目状态，未定义钩子时返回空字符串，出错时显示提示并写入日志
+func scriptBadge(config *Config, folder string) string {
+	result, err := callHook(config, folder, hookBadge)
+	if err != nil {
+		logf("项目 %s 的%v", folder, err)
+		return "⚠ 脚本出错"
+	}
+	switch v := result.(type) {
+	case nil, starlark.NoneType:
+		return ""
+	case starlark.String:
+		return string(v)
+	}
+	return result.String()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 读取一个密钥的最长时间，op 等工具可能需要用户在弹窗中解锁
+const secretTimeout = time.Minute
+
+var (
+	secretMu sync.Mutex
+	// 本次运行中已读取的密钥，只保存在内存中，避免每次启动命令都重新解锁
+	secretCache = make(map[string]string)
+)
+
+// 判断环境变量的值是否为密钥引用
+func isSecretRef(value string) bool {
+	for _, prefix := range []string{"op://", "vault://", "ssm://", "keychain://"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析环境变量中的密钥引用，普通值原样返回。支持的引用：
+//
+//	op://<vault>/<item>/<field>      1Password CLI（op read）
+//	vault://<path>#<field>           HashiCorp Vault KV（vault kv get -field）
+//	ssm://<name>                     AWS SSM Parameter Store（解密 SecureString）
+//	keychain://<service>/<account>   系统钥匙串：macOS 钥匙串、Linux Secret Service、Windows 凭据管理器
+func resolveSecret(ctx context.Context, value string) (string, error) {
+	if !isSecretRef(value) {
+		return value, nil
+	}
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	if secret, ok := secretCache[value]; ok {
+		return secret, nil
+	}
+
+	// 钥匙串在本机，其他密钥服务需要网络
+	if offline() && !strings.HasPrefix(value, "keychain://") {
+		return "", fmt.Errorf("离线模式下无法读取")
+	}
+
+	var secret string
+	var err error
+	switch scheme, ref, _ := strings.Cut(value, "://"); scheme {
+	case "op":
+		secret, err = secretCommand(ctx, "op", "read", value)
+	case "vault":
+		path, field, ok := strings.Cut(ref, "#")
+		if !ok || path == "" || field == "" {
+			return "", fmt.Errorf("格式应为 vault://<path>#<field>")
+		}
+		secret, err = secretCommand(ctx, "vault", "kv", "get", "-field="+field, path)
+	case "ssm":
+		secret, err = secretCommand(ctx, "aws", "ssm", "get-parameter", "--name", ref, "--with-decryption", "--query", "Parameter.Value", "--output", "text")
+	case "keychain":
+		service, account, ok := strings.Cut(ref, "/")
+		if !ok || service == "" || account == "" {
+			return "", fmt.Errorf("格式应为 keychain://<service>/<account>")
+		}
+		secret, err = keychainSecret(ctx, service, account)
+	}
+	if err != nil {
+		return "", err
+	}
+	secretCache[value] = secret
+	return secret, nil
+}
+
+// 执行读取密钥的命令并返回去掉末尾换行的输出。标准输入和错误输出连接到终端，以便工具提示登录或解锁
+func secretCommand(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("未找到 %s，请安装后将其加入 PATH", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s 超过 %v 未返回", name, secretTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s 执行失败: %v", name, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// 从系统钥匙串读取密钥：macOS 通过 security 读取通用密码，其他系统通过 secret-tool 读取 Secret Service 中的条目
+func keychainSecret(ctx context.Context, service, account string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		return secretCommand(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	}
+	secret, err := secretCommand(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	if err == nil && secret == "" {
+		return "", fmt.Errorf("钥匙串中没有 service=%s account=%s 的条目", service, account)
+	}
+	return secret, err
+}
+
+// 将密钥写入系统钥匙串，已有同名条目时覆盖：macOS 通过 security 添加通用密码，
+// 其他系统通过 secret-tool 写入 Secret Service。密钥经标准输入传入，不出现在其他用户可见的命令行参数中
+func storeKeychainSecret(ctx context.Context, service, account, secret string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// -w 放在最后且不带值时 security 提示输入两次密钥。在新的会话中运行，
+		// 没有控制终端时 security 从标准输入读取
+		if strings.ContainsAny(secret, "\r\n") {
+			return fmt.Errorf("密钥不能包含换行")
+		}
+		cmd = exec.CommandContext(ctx, "security", "add-generic-password", "-U", "-s", service, "-a", account, "-w")
+		cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	} else {
+		cmd = exec.CommandContext(ctx, "secret-tool", "store", "--label="+service, "service", service, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	if cmd.Err != nil {
+		return fmt.Errorf("未找到 %s，无法写入钥匙串", cmd.Args[0])
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s 执行失败: %s", cmd.Args[0], msg)
+		}
+		return fmt.Errorf("%s 执行失败: %v", cmd.Args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	advapi32      = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead  = advapi32.NewProc("CredReadW")
+	procCredWrite = advapi32.NewProc("CredWriteW")
+	procCredFree  = advapi32.NewProc("CredFree")
+)
+
+// 凭据类型：普通凭据，即 cmdkey /generic 添加的凭据
+const credTypeGeneric = 1
+
+// 凭据保存在本机，不随漫游配置同步
+const credPersistLocalMachine = 2
+
+// credential 对应 Windows 的 CREDENTIALW 结构
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// 从 Windows 凭据管理器读取普通凭据的密码，目标名为 service，并检查用户名与 account 一致。
+// 可通过 cmdkey /generic:<service> /user:<account> /pass 添加
+func keychainSecret(ctx context.Context, service, account string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(service)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	if r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); r == 0 {
+		return "", fmt.Errorf("凭据管理器中没有 %s: %v", service, err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if user := utf16PtrToString(cred.UserName); user != account {
+		return "", fmt.Errorf("凭据 %s 的用户名为 %s，不是 %s", service, user, account)
+	}
+	// cmdkey 和凭据管理器以 UTF-16 保存密码
+	blob := unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)
+	chars := make([]uint16, len(blob)/2)
+	for i := range chars {
+		chars[i] = uint16(blob[2*i]) | uint16(blob[2*i+1])<<8
+	}
+	return string(utf16.Decode(chars)), nil
+}
+
+// 将以 0 结尾的 UTF-16 字符串转换为 string
+func utf16PtrToString(p *uint16) string {
+	if p == nil {
+		return ""
+	}
+	var chars []uint16
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; ptr = unsafe.Add(ptr, 2) {
+		chars = append(chars, *(*uint16)(ptr))
+	}
+	return string(utf16.Decode(chars))
+}
+
+// 将密钥写入 Windows 凭据管理器的普通凭据，目标名为 service，用户名为 account，已有时覆盖。
+// 与 cmdkey 一致以 UTF-16 保存密码
+func storeKeychainSecret(_ context.Context, service, account, secret string) error {
+	target, err := syscall.UTF16PtrFromString(service)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	chars := utf16.Encode([]rune(secret))
+	blob := make([]byte, 2*len(chars))
+	for i, c := range chars {
+		blob[2*i], blob[2*i+1] = byte(c), byte(c>>8)
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("无法写入凭据管理器: %v", err)
+	}
+	return nil
+}
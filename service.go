@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// service 表示一个由启动器运行的服务
+type service struct {
+	Project   string
+	Process   string // Procfile 中的进程名，为空表示项目的唯一服务
+	Path      string // 项目根目录
+	Dir       string // 服务运行目录，为空时使用当前目录
+	Command   []string
+	Supervise SuperviseConfig
+	Watch     []string
+	Health    *HealthCheckConfig
+	Notify    bool
+	Stats     bool     // 是否在服务结束后记录使用统计
+	OpenURLs  []string // 服务启动（配置了就绪检查时为就绪）后在浏览器中打开的链接
+	Env       []string
+	PTY       bool            // 是否在伪终端中运行
+	LaunchEnv []string        // 启动方式追加的环境变量（已包含在 Env 中），记录到会话中以便恢复
+	Output    io.Writer       // 服务输出，为空时输出到标准输出
+	Stop      <-chan struct{} // 关闭时结束服务，不再重启；为 nil 时服务只能通过 Ctrl+C 结束
+	After     []*service      // 同时运行多个服务时，需要先就绪才能启动该服务的服务
+	Timing    *launchTiming   // 本次启动各阶段的耗时，就绪后显示；为 nil 时不记录
+	Hooks     []eventHook     // 服务启动、就绪、异常退出和结束时执行的事件钩子
+	IdleStop  time.Duration   // 没有访问、输出和文件变动超过该时长后停止服务，为 0 时不停止
+	Port      int             // 服务端口，用于判断是否有访问
+
+	record     serviceRecord
+	recordFile string
+	tail       outputTail    // 最近的输出，服务失败时写入 last-failure.log
+	captured   bool          // 是否记录了输出
+	idle       *idleMonitor  // 最近一次活动的时间，未配置空闲停止时为 nil
+	ready      chan struct{} // 服务就绪或无法就绪时关闭，结果为 readyOK；为 nil 时没有服务等待
+	readyOK    bool
+	readyOnce  sync.Once
+}
+
+// 本进程中运行过的服务数量，用于生成运行记录的文件名
+var serviceCount atomic.Int32
+
+// serviceRecord 为写入状态目录的运行记录，供 ps 等命令查看其他启动器实例运行的服务
+type serviceRecord struct {
+	Project     string             `json:"project"`
+	Path        string             `json:"path"`
+	Dir         string             `json:"dir"`
+	Command     []string           `json:"command"`
+	LauncherPID int                `json:"launcherPid"`
+	PID         int                `json:"pid"`
+	Started     time.Time          `json:"started"`
+	Health      *HealthCheckConfig `json:"health,omitempty"`
+}
+
+// 启动服务前的等待时间，期间可以按 Ctrl+C 取消
+const launchDelay = 5 * time.Second
+
+// 启动服务前等待 launchDelay，ctx 被取消时立即返回 false，不再启动
+func waitLaunch(ctx context.Context) bool {
+	select {
+	case <-time.After(launchDelay):
+		return true
+	case <-ctx.Done():
+		fmt.Println("已取消启动")
+		return false
+	}
+}
+
+// 按项目配置创建服务
+func newService(ctx context.Context, config *Config, name string, command []string) *service {
+	return &service{
+		Project:   name,
+		Command:   command,
+		Supervise: superviseConfig(config, name),
+		Watch:     projectWatch(config, name),
+		Health:    projectHealthCheck(config, name),
+		Notify:    config.Notify,
+		Stats:     config.Stats,
+		OpenURLs:  launchURLs(config, name),
+		Env:       projectEnv(ctx, config, name),
+		PTY:       projectPTY(config, name),
+		Hooks:     projectHooks(config, name),
+		IdleStop:  projectIdleStop(config, name),
+		Port:      projectPort(config, name),
+	}
+}
+
+// 获取项目启动后需要自动打开的链接
+func launchURLs(config *Config, name string) []string {
+	var urls []string
+	for _, link := range projectLinks(config, name) {
+		if link.OpenOnLaunch {
+			urls = append(urls, link.URL)
+		}
+	}
+	return urls
+}
+
+// 运行服务，运行期间在状态目录中保留运行记录
+func (s *service) run(ctx context.Context) error {
+	if s.Dir == "" {
+		s.Dir, _ = os.Getwd()
+	}
+	if s.Path == "" {
+		s.Path = s.Dir
+	}
+	s.recordFile = statePath("run", fmt.Sprintf("%d-%d.json", os.Getpid(), serviceCount.Add(1)))
+	recordSession(s)
+	s.record = serviceRecord{
+		Project:     s.Project,
+		Path:        s.Path,
+		Dir:         s.Dir,
+		Command:     s.Command,
+		LauncherPID: os.Getpid(),
+		Started:     time.Now(),
+		Health:      s.Health,
+	}
+	defer os.Remove(s.recordFile)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if s.Stats {
+		defer recordStats(s.Project, s.record.Started)
+	}
+	if (s.Stats || len(s.Hooks) > 0) && len(s.Watch) == 0 && s.Stop == nil && !s.PTY && s.IdleStop == 0 {
+		// 未监听文件、不使用伪终端且未配置空闲停止时 Ctrl+C 会直接结束启动器，退出前记录本次运行并执行 stop 事件钩子
+		interrupt, release := claimInterrupt()
+		defer release()
+		go func() {
+			select {
+			case <-interrupt:
+				if s.Stats {
+					recordStats(s.Project, s.record.Started)
+				}
+				s.emit(eventStop, nil)
+				waitHooks()
+				os.Remove(s.recordFile)
+				os.Exit(130)
+			case <-stop:
+			}
+		}()
+	}
+	s.emit(eventLaunch, nil)
+	if s.Health != nil {
+		go func() {
+			if !watchHealth(s.Project, s.Health, stop) {
+				s.setReady(false)
+				return
+			}
+			s.setReady(true)
+			s.emit(eventReady, nil)
+			if s.Timing != nil {
+				s.Timing.add("ready", time.Since(s.record.Started))
+				s.Timing.finish(s.Stats)
+			}
+			if s.Notify {
+				notify("QuickStart", fmt.Sprintf("%s 已就绪 %s", s.Project, s.Health.target()))
+			}
+			s.openURLs()
+		}()
+	} else {
+		// 没有就绪检查时启动即视为就绪
+		s.setReady(true)
+		s.emit(eventReady, nil)
+		if s.Timing != nil {
+			s.Timing.finish(s.Stats)
+		}
+		s.openURLs()
+	}
+	logf("启动 %s: %s", s.label(), strings.Join(s.Command, " "))
+	err := superviseService(ctx, s)
+	s.setReady(false)
+	if err != nil {
+		logf("%s 已退出: %v", s.label(), err)
+	} else {
+		logf("%s 已结束", s.label())
+	}
+	s.emit(eventStop, err)
+	waitHooks()
+	return err
+}
+
+// 记录服务是否就绪并通知等待的服务，只有第一次调用有效
+func (s *service) setReady(ok bool) {
+	s.readyOnce.Do(func() {
+		s.readyOK = ok
+		if s.ready != nil {
+			close(s.ready)
+		}
+	})
+}
+
+// 等待 After 中的服务全部就绪，有服务无法就绪或 stop 关闭时返回 false
+func (s *service) waitAfter(stop <-chan struct{}) bool {
+	for _, dep := range s.After {
+		select {
+		case <-dep.ready:
+		case <-stop:
+			return false
+		}
+		if !dep.readyOK {
+			fmt.Fprintf(s.output(), "%s 未就绪，不再启动\n", dep.label())
+			return false
+		}
+	}
+	return true
+}
+
+// 在浏览器中打开服务的链接
+func (s *service) openURLs() {
+	for _, url := range s.OpenURLs {
+		if err := openURL(url); err != nil {
+			fmt.Fprintln(s.output(), err)
+		}
+	}
+}
+
+// 服务进程启动或重启后更新运行记录
+func (s *service) started(pid int) {
+	s.record.PID = pid
+	if s.idle != nil {
+		s.idle.touch()
+	}
+	if err := os.MkdirAll(filepath.Dir(s.recordFile), 0755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(s.record, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(s.recordFile, data, 0644)
+}
+
+// 服务异常退出时记录失败并发送通知
+func (s *service) crashed(err error) {
+	recordFailure(s, err)
+	s.emit(eventCrash, err)
+	if s.Notify {
+		notify("QuickStart", fmt.Sprintf("%s 异常退出: %v", s.Project, err))
+	}
+}
+
+// 服务在输出前缀和提示中显示的名称，Procfile 进程为 项目/进程名
+func (s *service) label() string {
+	if s.Process != "" {
+		return s.Project + "/" + s.Process
+	}
+	return s.Project
+}
+
+// 服务输出的目标
+func (s *service) output() io.Writer {
+	if s.Output != nil {
+		return s.Output
+	}
+	return processStdout
+}
+
+// 服务进程的输出目标，同时记录最近的输出。输出到终端且不使用伪终端时子进程直接继承终端，
+// 以保留颜色等终端特性，此时不记录
+func (s *service) capture() io.Writer {
+	out := s.output()
+	s.captured = s.Output != nil || s.PTY
+	if !s.captured {
+		return out
+	}
+	if s.idle != nil {
+		return io.MultiWriter(out, &s.tail, s.idle)
+	}
+	return io.MultiWriter(out, &s.tail)
+}
+
+// 读取所有运行中的服务记录，启动器已退出的过期记录会被清理
+func runningServices() []serviceRecord {
+	files, _ := filepath.Glob(statePath("run", "*.json"))
+	var records []serviceRecord
+	for _, file := range files {
+		var record serviceRecord
+		if err := readJSONFile(file, &record); err != nil {
+			continue
+		}
+		if !processAlive(record.LauncherPID) {
+			os.Remove(file)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionEntry 表示会话中运行过的一个服务
+type sessionEntry struct {
+	Project string   `json:"project"`
+	Path    string   `json:"path"`
+	Dir     string   `json:"dir"`
+	Command []string `json:"command"`
+	Process string   `json:"process,omitempty"`
+	Env     []string `json:"env,omitempty"`
+}
+
+// session 为最近一次工作会话，即从第一个服务启动到所有服务停止期间运行过的服务
+type session struct {
+	Updated  time.Time      `json:"updated"`
+	Services []sessionEntry `json:"services"`
+}
+
+var (
+	sessionMu sync.Mutex
+	// 本进程是否已经向会话中记录过服务
+	sessionJoined bool
+)
+
+// 会话文件路径
+func sessionPath() string {
+	return statePath("session.json")
+}
+
+// 读取上次会话，不存在时返回空会话
+func readSession() session {
+	var sess session
+	readJSONFile(sessionPath(), &sess)
+	return sess
+}
+
+// 将启动的服务记录到会话中。没有其他服务在运行时开始新的会话
+func recordSession(s *service) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	var sess session
+	if sessionJoined || len(runningServices()) > 0 {
+		sess = readSession()
+	}
+	sessionJoined = true
+
+	entry := sessionEntry{Project: s.Project, Path: s.Path, Dir: s.Dir, Command: s.Command, Process: s.Process, Env: s.LaunchEnv}
+	services := sess.Services[:0]
+	for _, e := range sess.Services {
+		if e.Project != entry.Project || e.Path != entry.Path || e.Process != entry.Process {
+			services = append(services, e)
+		}
+	}
+	sess.Services = append(services, entry)
+	sess.Updated = time.Now()
+
+	data, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(sessionPath()), 0755); err != nil {
+		return
+	}
+	os.WriteFile(sessionPath(), data, 0644)
+}
+
+// 获取可以恢复的上次会话，当前已有服务在运行时不提供恢复
+func restorableSession() *session {
+	if len(runningServices()) > 0 {
+		return nil
+	}
+	sess := readSession()
+	if len(sess.Services) == 0 {
+		return nil
+	}
+	return &sess
+}
+
+// 会话的简要描述，用于菜单显示
+func (sess *session) summary() string {
+	names := make([]string, 0, len(sess.Services))
+	for _, e := range sess.Services {
+		if !contains(e.Project, names) {
+			names = append(names, e.Project)
+		}
+	}
+	return strings.Join(names, "、")
+}
+
+// 恢复会话：为每个项目打开编辑器，并在当前终端中同时启动所有服务
+func restoreSession(ctx context.Context, config *Config, sess *session) {
+	var services []*service
+	opened := make(map[string]bool)
+	for _, e := range sess.Services {
+		svc := newService(ctx, config, e.Project, e.Command)
+		svc.Path, svc.Dir, svc.Process = e.Path, e.Dir, e.Process
+		svc.LaunchEnv = e.Env
+		svc.Env = append(svc.Env, e.Env...)
+		// 同一项目的多个 Procfile 进程只打开一次编辑器
+		if opened[e.Project] {
+			svc.Health, svc.OpenURLs, svc.Stats = nil, nil, false
+			services = append(services, svc)
+			continue
+		}
+		opened[e.Project] = true
+		fmt.Printf("正在恢复项目：%s\n", e.Project)
+		project := findProject(config, e.Project)
+		if project == nil || project.Remote == "" && project.WSL == "" {
+			if err := os.Chdir(e.Path); err != nil {
+				fmt.Printf("无法进入项目目录 %s: %v\n", e.Path, err)
+				continue
+			}
+		}
+		if err := openEditor(ctx, config, project); err != nil {
+			fmt.Println("无法打开编辑器:", err)
+		}
+		services = append(services, svc)
+	}
+	if len(services) == 0 {
+		return
+	}
+	fmt.Println("5秒后启动所有服务，Ctrl+C 停止")
+	if !waitLaunch(ctx) {
+		return
+	}
+	runServices(ctx, services)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 命令字符串中出现这些字符时需要交给 shell 解释（管道、重定向、多条命令、变量展开等）
+const shellMetaChars = "|&;<>$`%"
+
+// 获取当前系统使用的 shell：优先使用配置的 shell，Windows 下默认为 pwsh（未安装时为 powershell），
+// 其他系统默认为 $SHELL（未设置时为 sh）
+func configuredShell(config *Config) string {
+	if shell := config.Shell[runtime.GOOS]; shell != "" {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		if _, err := exec.LookPath("pwsh"); err == nil {
+			return "pwsh"
+		}
+		return "powershell"
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "sh"
+}
+
+// 生成通过 shell 执行命令字符串的参数
+func shellArgs(shell, command string) []string {
+	switch {
+	case isPowerShell(shell):
+		return []string{shell, "-NoProfile", "-Command", command}
+	case shellName(shell) == "cmd":
+		return []string{shell, "/d", "/s", "/c", command}
+	default:
+		return []string{shell, "-c", command}
+	}
+}
+
+// 获取 shell 的名称，去掉目录和扩展名
+func shellName(shell string) string {
+	return strings.ToLower(strings.TrimSuffix(filepath.Base(shell), filepath.Ext(shell)))
+}
+
+// 判断 shell 是否为 PowerShell
+func isPowerShell(shell string) bool {
+	name := shellName(shell)
+	return name == "pwsh" || name == "powershell"
+}
+
+// 将配置的命令字符串转换为要执行的参数。普通命令直接执行，不经过 shell；
+// 包含管道、重定向等 shell 语法时交给 shell 执行；.ps1 脚本通过 PowerShell 执行
+func commandArgs(config *Config, command string) []string {
+	if strings.ContainsAny(command, shellMetaChars) {
+		// 位于 WSL 中的项目在发行版内执行，使用 Linux 的 sh
+		if _, _, ok := wslLocation("."); ok {
+			return []string{"sh", "-lc", command}
+		}
+		return shellArgs(configuredShell(config), command)
+	}
+	args := splitCommand(command)
+	if len(args) > 0 && strings.EqualFold(filepath.Ext(args[0]), ".ps1") {
+		shell := configuredShell(config)
+		if !isPowerShell(shell) {
+			// Windows 自带 powershell，其他系统需要安装 pwsh
+			shell = "pwsh"
+			if runtime.GOOS == "windows" {
+				shell = "powershell"
+			}
+		}
+		return append([]string{shell, "-NoProfile", "-ExecutionPolicy", "Bypass", "-File"}, args...)
+	}
+	return args
+}
+
+// Windows 下直接执行 .bat/.cmd 脚本时，系统会把整条命令行交给 cmd.exe 重新解析，脚本路径含有空格、
+// & ( ) 等字符时会被错误拆分。带目录的脚本改为通过 cmd /d /c call 执行其绝对路径，
+// 创建命令后由 setBatchCmdLine 为路径加上引号
+func batchCommand(command []string) []string {
+	if runtime.GOOS != "windows" || len(command) == 0 || !strings.ContainsAny(command[0], `\/`) {
+		return command
+	}
+	if ext := strings.ToLower(filepath.Ext(command[0])); ext != ".bat" && ext != ".cmd" {
+		return command
+	}
+	path, err := filepath.Abs(command[0])
+	if err != nil {
+		return command
+	}
+	return append([]string{"cmd", "/d", "/c", "call", path}, command[1:]...)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 菜单支持的排序方式，按此顺序切换
+var sortModes = []string{"name", "modified", "launched", "manual"}
+
+// 排序方式在菜单中显示的名称
+var sortModeNames = map[string]string{
+	"name":     "名称",
+	"modified": "修改时间",
+	"launched": "最近启动",
+	"manual":   "自定义顺序",
+}
+
+// 菜单当前的排序方式，进入子目录后保持不变
+var menuSort string
+
+// 打印当前的排序方式和功能键
+func printSortMode() {
+	name := sortModeNames[menuSort]
+	if name == "" {
+		name = "默认"
+	}
+	fmt.Printf("（排序：%s，按 %c 切换；按 %c 归档项目；按 %c 批量执行；按 %c 停止所有服务；按 %c 编辑备注；输入 %s 退出，%c 刷新）\n", name, sortKey, archiveKey, eachKey, downKey, remarkKey, quitInput, refreshKey)
+}
+
+// 切换到下一种排序方式，未配置 order 时跳过自定义顺序
+func nextSortMode(mode string, config *Config) string {
+	i := -1
+	for j, m := range sortModes {
+		if m == mode {
+			i = j
+		}
+	}
+	next := sortModes[(i+1)%len(sortModes)]
+	if next == "manual" && len(config.Order) == 0 {
+		next = sortModes[0]
+	}
+	return next
+}
+
+// 按排序方式对目录下的项目排序，子目录始终置顶，其次为备注中置顶的项目。未指定排序方式时保持原有顺序，worktree 都排在主仓库之后
+func sortProjects(dir string, projects []*Project, config *Config, mode string) {
+	var less func(a, b *Project) bool
+	switch mode {
+	case "name":
+		less = func(a, b *Project) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	case "modified":
+		modified := make(map[string]time.Time)
+		for _, p := range projects {
+			if info, err := os.Stat(filepath.Join(dir, p.Name)); err == nil {
+				modified[p.Name] = info.ModTime()
+			}
+		}
+		less = func(a, b *Project) bool {
+			return modified[a.Name].After(modified[b.Name])
+		}
+	case "launched":
+		history := launchHistory()
+		less = func(a, b *Project) bool {
+			return history[a.Name].After(history[b.Name])
+		}
+	case "manual":
+		// 未出现在 order 中的项目排在最后，保持原有顺序
+		position := make(map[string]int)
+		for i, name := range config.Order {
+			position[name] = i + 1
+		}
+		rank := func(name string) int {
+			if p, ok := position[name]; ok {
+				return p
+			}
+			return len(config.Order) + 1
+		}
+		less = func(a, b *Project) bool {
+			return rank(a.Name) < rank(b.Name)
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].SubDir != projects[j].SubDir {
+			return projects[i].SubDir
+		}
+		if projects[i].Pinned != projects[j].Pinned {
+			return projects[i].Pinned
+		}
+		return less != nil && less(projects[i], projects[j])
+	})
+	// 同一仓库的 worktree 始终排在主仓库之后
+	groupWorktrees(dir, projects)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 使用统计中按周显示启动次数的周数
+const statsWeeks = 12
+
+// 迷你趋势图使用的字符，从低到高
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+// statsEntry 为一次服务运行的统计记录
+type statsEntry struct {
+	Project string    `json:"project"`
+	Started time.Time `json:"started"`
+	Seconds int64     `json:"seconds"`
+}
+
+// 使用统计文件路径，每行一条 JSON 记录，只追加不改写
+func statsPath() string {
+	return statePath("stats.jsonl")
+}
+
+// 追加一次服务运行的统计记录
+func recordStats(project string, started time.Time) {
+	data, err := json.Marshal(statsEntry{Project: project, Started: started, Seconds: int64(time.Since(started).Seconds())})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(statsPath()), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(statsPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// 读取所有统计记录，忽略无法解析的行
+func readStats() []statsEntry {
+	f, err := os.Open(statsPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var entries []statsEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry statsEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Project != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// projectStats 为单个项目的统计汇总
+type projectStats struct {
+	Name     string
+	Launches int
+	Total    time.Duration
+	Last     time.Time
+	Weeks    [statsWeeks]int // 最近各周的启动次数，最后一项为本周
+}
+
+// 执行 stats 子命令，按启动次数列出各项目的使用情况，从未启动的项目排在最后。
+// 带有 --daily、--weekly、--csv 等参数时按时间汇总或导出服务运行时长
+func runStatsCommand(args []string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %v", err)
+	}
+	if len(args) > 0 {
+		return runTimeCommand(config, args)
+	}
+	entries := readStats()
+	if len(entries) == 0 && len(readTimings()) > 0 {
+		// 服务还在运行或被强制结束时只有启动耗时记录
+		return printTimingStats(os.Stdout)
+	}
+	if len(entries) == 0 {
+		if !config.Stats {
+			fmt.Println(`未开启使用统计，可在配置文件中设置 "stats": true`)
+		} else {
+			fmt.Println("还没有使用统计记录。")
+		}
+		return nil
+	}
+
+	byName := make(map[string]*projectStats)
+	get := func(name string) *projectStats {
+		if byName[name] == nil {
+			byName[name] = &projectStats{Name: name}
+		}
+		return byName[name]
+	}
+	// 列出工作目录中的项目，便于发现长期未使用的项目
+	if projects, err := discoverProjects(config); err == nil {
+		for _, p := range projects {
+			get(p.Name)
+		}
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		stats := get(entry.Project)
+		stats.Launches++
+		stats.Total += time.Duration(entry.Seconds) * time.Second
+		if entry.Started.After(stats.Last) {
+			stats.Last = entry.Started
+		}
+		if week := int(now.Sub(entry.Started).Hours() / 24 / 7); week >= 0 && week < statsWeeks {
+			stats.Weeks[statsWeeks-1-week]++
+		}
+	}
+
+	list := make([]*projectStats, 0, len(byName))
+	peak := 0
+	for _, stats := range byName {
+		list = append(list, stats)
+		for _, n := range stats.Weeks {
+			peak = max(peak, n)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Launches != list[j].Launches {
+			return list[i].Launches > list[j].Launches
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "项目\t启动次数\t平均时长\t上次启动\t近 %d 周\n", statsWeeks)
+	for _, stats := range list {
+		if stats.Launches == 0 {
+			fmt.Fprintf(w, "%s\t0\t-\t从未\t%s\n", stats.Name, sparkline(stats.Weeks[:], peak))
+			continue
+		}
+		average := stats.Total / time.Duration(stats.Launches)
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", stats.Name, stats.Launches, formatDuration(average), relativeTime(stats.Last), sparkline(stats.Weeks[:], peak))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return printTimingStats(os.Stdout)
+}
+
+// 将各周的次数绘制为迷你趋势图，peak 为所有项目中的最大值
+func sparkline(counts []int, peak int) string {
+	var b strings.Builder
+	for _, n := range counts {
+		if peak == 0 {
+			b.WriteRune(sparkChars[0])
+			continue
+		}
+		b.WriteRune(sparkChars[n*(len(sparkChars)-1)/peak])
+	}
+	return b.String()
+}
+
+// 以中文格式显示时长，精确到分钟
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "不到 1 分钟"
+	case d < time.Hour:
+		return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%d 小时 %d 分钟", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
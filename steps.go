@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// 短时命令的默认超时
+const (
+	defaultGitTimeout     = 2 * time.Minute
+	defaultInstallTimeout = 15 * time.Minute
+)
+
+// 检测设备等查询命令的超时
+const queryTimeout = 15 * time.Second
+
+// 短时命令被用户按 Ctrl+C 取消
+var errStepCanceled = errors.New("已取消")
+
+// 获取短时命令的超时，kind 为 git 或 install，配置为 0 时使用默认值
+func stepTimeout(config *Config, kind string) time.Duration {
+	var seconds int
+	if config != nil && config.Timeouts != nil {
+		switch kind {
+		case "git":
+			seconds = config.Timeouts.Git
+		case "install":
+			seconds = config.Timeouts.Install
+		}
+	}
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if kind == "install" {
+		return defaultInstallTimeout
+	}
+	return defaultGitTimeout
+}
+
+// 在前台运行短时命令（如 git pull、依赖安装）。超过 timeout 时结束命令，timeout 为 0 表示不限时；
+// 按下 Ctrl+C 只结束该命令并返回 errStepCanceled，不会退出启动器。ctx 被取消时结束命令并同样返回 errStepCanceled
+func runStep(ctx context.Context, timeout time.Duration, env []string, command ...string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Ctrl+C 由终端发给同一进程组的子进程，启动器自身只需忽略这次中断
+	interrupt, release := claimInterrupt()
+	defer release()
+
+	command = batchCommand(command)
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	setBatchCmdLine(cmd)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = processStdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	select {
+	case <-interrupt:
+		return errStepCanceled
+	default:
+	}
+	if ctx.Err() == context.DeadlineExceeded && timeout > 0 {
+		return fmt.Errorf("超过 %v 未完成，已结束", timeout)
+	}
+	if ctx.Err() != nil {
+		return errStepCanceled
+	}
+	return err
+}
+
+// 执行查询命令并返回标准输出，超过 queryTimeout 时结束
+func queryOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
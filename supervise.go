@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// 服务持续运行超过该时长后视为已稳定，重启计数和等待时间重新计算
+const stableRunDuration = time.Minute
+
+// 运行服务命令，开启自动重启时在异常退出后按指数退避重新启动，
+// 配置了监听文件时在文件变动后结束并重新启动服务，配置了空闲停止时在空闲后结束服务
+func superviseService(ctx context.Context, svc *service) error {
+	supervise, watch := svc.Supervise, svc.Watch
+	var changes chan string
+	if len(watch) > 0 {
+		watcher := newFileWatcher(svc.Dir, watch)
+		changes = make(chan string)
+		// 服务结束时停止监听，避免监听的协程一直扫描目录
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				path := watcher.wait(done)
+				if path == "" {
+					return
+				}
+				select {
+				case changes <- path:
+				case <-done:
+					return
+				}
+			}
+		}()
+		fmt.Fprintf(svc.output(), "正在监听文件变动: %s\n", strings.Join(watch, ", "))
+	}
+	idle := watchIdle(svc)
+
+	retries := 0
+	backoff := time.Duration(supervise.Backoff) * time.Second
+	for {
+		start := time.Now()
+		cmd := exec.Command(svc.Command[0], svc.Command[1:]...)
+		setBatchCmdLine(cmd)
+		cmd.Dir = svc.Dir
+		cmd.Env = svc.Env
+		out := svc.capture()
+		cmd.Stdout = out
+		cmd.Stderr = out
+
+		var err error
+		// 伪终端中的服务在独立的会话中运行，与监听文件时一样需要由启动器转发 Ctrl+C；
+		// 空闲时需要结束整个进程树，同样放入独立的进程组
+		if changes == nil && !svc.PTY && idle == nil {
+			var stopped bool
+			if stopped, err = runStoppable(ctx, svc, cmd); stopped {
+				return nil
+			}
+		} else {
+			changed, idled, interrupted, runErr := runWatched(ctx, svc, cmd, changes, idle)
+			if interrupted {
+				return nil
+			}
+			if idled {
+				if !waitResume(ctx, svc) {
+					return nil
+				}
+				continue
+			}
+			if changed != "" {
+				printBanner(svc.output(), fmt.Sprintf("检测到文件变动 %s，重启服务", changed))
+				continue
+			}
+			err = runErr
+		}
+
+		if err == nil && changes == nil {
+			return nil
+		}
+		// 命令本身无法执行时重启没有意义
+		if _, ok := err.(*exec.ExitError); err != nil && !ok {
+			recordFailure(svc, err)
+			return err
+		}
+		if err != nil {
+			svc.crashed(err)
+		}
+
+		// 异常退出且开启了自动重启时，按指数退避重新启动
+		if err != nil && supervise.Enabled {
+			if time.Since(start) >= stableRunDuration {
+				retries = 0
+				backoff = time.Duration(supervise.Backoff) * time.Second
+			}
+			if retries < supervise.MaxRetries {
+				retries++
+				printBanner(svc.output(), fmt.Sprintf("服务异常退出（%v），%v 后第 %d/%d 次重启", err, backoff, retries, supervise.MaxRetries))
+				select {
+				case <-time.After(backoff):
+				case <-svc.Stop:
+					return nil
+				case <-ctx.Done():
+					return nil
+				}
+				backoff *= 2
+				if maxBackoff := time.Duration(supervise.MaxBackoff) * time.Second; backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+				continue
+			}
+			if changes == nil {
+				return fmt.Errorf("已连续重启 %d 次，不再重试: %v", retries, err)
+			}
+		}
+		if changes == nil {
+			return err
+		}
+
+		// 监听文件时等待下一次变动再启动，与 nodemon 的行为一致
+		if err != nil {
+			fmt.Fprintf(svc.output(), "服务异常退出（%v），等待文件变动后重启\n", err)
+		} else {
+			fmt.Fprintln(svc.output(), "服务已退出，等待文件变动后重启")
+		}
+		if !waitChange(ctx, svc, changes) {
+			return nil
+		}
+		retries = 0
+		backoff = time.Duration(supervise.Backoff) * time.Second
+	}
+}
+
+// 启动进程并等待退出。服务可被停止时放入独立的进程组，收到停止信号或 ctx 被取消后结束整个进程树并返回 stopped 为 true，
+// 自行退出时结束残留的子进程
+func runStoppable(ctx context.Context, svc *service, cmd *exec.Cmd) (stopped bool, err error) {
+	if svc.Stop != nil {
+		setProcessGroup(cmd)
+	}
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+	trackProcessTree(cmd)
+	svc.started(cmd.Process.Pid)
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		reapProcessTree(cmd)
+		return false, err
+	case <-svc.Stop:
+		killProcessTree(cmd, done)
+		return true, nil
+	case <-ctx.Done():
+		killProcessTree(cmd, done)
+		return true, nil
+	}
+}
+
+// 启动进程并同时监听文件变动、空闲、Ctrl+C、停止信号和 ctx 的取消，文件变动、空闲或中断时结束整个进程树。
+// changes 为 nil 时不监听文件，idle 为 nil 时不检查空闲
+func runWatched(ctx context.Context, svc *service, cmd *exec.Cmd, changes <-chan string, idle <-chan struct{}) (changed string, idled, interrupted bool, err error) {
+	setProcessGroup(cmd)
+	wait, keys, err := startProcess(ctx, svc, cmd)
+	if err != nil {
+		return "", false, false, err
+	}
+	trackProcessTree(cmd)
+	svc.started(cmd.Process.Pid)
+	done := make(chan error, 1)
+	go func() { done <- wait() }()
+
+	// 子进程不在前台进程组中，收不到终端的 Ctrl+C，需要由启动器转发
+	interrupt, release := claimInterrupt()
+	defer release()
+
+	select {
+	case err := <-done:
+		reapProcessTree(cmd)
+		return "", false, false, err
+	case path := <-changes:
+		killProcessTree(cmd, done)
+		return path, false, false, nil
+	case <-idle:
+		killProcessTree(cmd, done)
+		return "", true, false, nil
+	case <-interrupt:
+		killProcessTree(cmd, done)
+		return "", false, true, nil
+	case <-keys:
+		killProcessTree(cmd, done)
+		return "", false, true, nil
+	case <-svc.Stop:
+		killProcessTree(cmd, done)
+		return "", false, true, nil
+	case <-ctx.Done():
+		killProcessTree(cmd, done)
+		return "", false, true, nil
+	}
+}
+
+// 等待下一次文件变动，期间按下 Ctrl+C、服务被停止或 ctx 被取消时返回 false
+func waitChange(ctx context.Context, svc *service, changes <-chan string) bool {
+	interrupt, release := claimInterrupt()
+	defer release()
+
+	select {
+	case path := <-changes:
+		printBanner(svc.output(), fmt.Sprintf("检测到文件变动 %s，重启服务", path))
+		return true
+	case <-interrupt:
+		return false
+	case <-svc.Stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// 打印重启时的分隔横幅
+func printBanner(w io.Writer, message string) {
+	banner := strings.Repeat("=", 12)
+	fmt.Fprintf(w, "\n%s %s %s\n\n", banner, message, banner)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// commandData 为配置的命令中可以使用的模板变量
+type commandData struct {
+	ProjectName string // 项目文件夹名
+	ProjectPath string // 项目目录，远程和 WSL 项目为远程路径
+	Port        int    // 项目服务的端口，未配置时为 0
+	Profile     string // 当前生效的配置档
+	// Inputs 为启动前询问的参数，未询问时为上次输入的值或默认值
+	Inputs map[string]string
+}
+
+// 展开命令字符串中的模板变量，如 {{.ProjectName}}、{{.ProjectPath}}、{{.Port}}、{{.Inputs.env}}、{{env "FOO"}}，
+// 不含 {{ 的命令原样返回。env 依次查找项目、全局配置的环境变量和系统环境变量。
+// 展开后的命令会写入会话、运行记录和日志，值为密钥引用的环境变量不能在 env 中使用，只能通过进程的环境变量读取
+func expandCommand(config *Config, folder, command string) (string, error) {
+	if !strings.Contains(command, "{{") {
+		return command, nil
+	}
+	project := findProject(config, folder)
+	configured := func(name, v string) (string, error) {
+		if isSecretRef(v) {
+			return "", fmt.Errorf("环境变量 %s 为密钥引用，不能在命令中展开，请在服务中读取环境变量 %s", name, name)
+		}
+		return v, nil
+	}
+	funcs := template.FuncMap{
+		"env": func(name string) (string, error) {
+			if project != nil {
+				if v, ok := project.Env[name]; ok {
+					return configured(name, v)
+				}
+			}
+			if v, ok := config.Env[name]; ok {
+				return configured(name, v)
+			}
+			return os.Getenv(name), nil
+		},
+	}
+	tmpl, err := template.New("command").Funcs(funcs).Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("命令 %s 格式错误: %v", command, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, projectCommandData(config, folder)); err != nil {
+		return "", fmt.Errorf("无法展开命令 %s: %v", command, err)
+	}
+	return b.String(), nil
+}
+
+// 获取项目的模板变量
+func projectCommandData(config *Config, folder string) commandData {
+	data := commandData{ProjectName: folder, Profile: config.ActiveProfile}
+	project := findProject(config, folder)
+	switch {
+	case project != nil && project.Remote != "":
+		_, data.ProjectPath, _ = parseRemote(project.Remote)
+	case project != nil && project.WSL != "":
+		_, data.ProjectPath, _ = parseWSLProject(project.WSL)
+	default:
+		data.ProjectPath, _ = filepath.Abs(filepath.Join(config.ProjectDir, folder))
+	}
+	data.Port = projectPort(config, folder)
+	data.Inputs = projectInputs(config, folder)
+	return data
+}
+
+// 获取项目服务的端口：优先使用配置的 port，其次为就绪检查的端口或 URL 中的端口
+func projectPort(config *Config, folder string) int {
+	project := findProject(config, folder)
+	if project == nil {
+		return 0
+	}
+	if project.Port > 0 {
+		return project.Port
+	}
+	if h := project.HealthCheck; h != nil {
+		if h.Port > 0 {
+			return h.Port
+		}
+		if u, err := url.Parse(h.URL); err == nil {
+			if port, err := strconv.Atoi(u.Port()); err == nil {
+				return port
+			}
+		}
+	}
+	return 0
+}
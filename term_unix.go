@@ -0,0 +1,50 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// 将终端切换为按键输入模式（关闭行缓冲、回显和信号键），返回恢复函数；标准输入不是终端时返回 false
+func enableRawInput() (func(), bool) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, false
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, false
+	}
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return nil, false
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, true
+}
+
+// 对当前终端执行 stty
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// 其他系统的终端默认使用 UTF-8 并支持 ANSI 转义序列
+func initConsole() bool {
+	return true
+}
+
+// 获取终端的列数和行数，标准输入不是终端时为 80x24
+func terminalSize() (cols, rows int) {
+	out, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	if _, err := fmt.Sscan(out, &rows, &cols); err != nil || cols <= 0 || rows <= 0 {
+		return 80, 24
+	}
+	return cols, rows
+}
@@ -0,0 +1,70 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode     = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode     = kernel32.NewProc("SetConsoleMode")
+	procSetConsoleCP       = kernel32.NewProc("SetConsoleCP")
+	procSetConsoleOutputCP = kernel32.NewProc("SetConsoleOutputCP")
+)
+
+// 控制台输入模式标志
+const (
+	enableProcessedInput = 0x0001
+	enableLineInput      = 0x0002
+	enableEchoInput      = 0x0004
+	// 以 VT 转义序列上报方向键、鼠标点击和光标位置，与其他系统的终端一致
+	enableVirtualTerminalInput = 0x0200
+)
+
+// 控制台输出模式标志，开启后支持 ANSI 转义序列
+const enableVirtualTerminalProcessing = 0x0004
+
+// UTF-8 代码页
+const codePageUTF8 = 65001
+
+// 将控制台的输入输出代码页设为 UTF-8，使中文提示和子进程输出（如 npm）不再乱码，
+// 并开启 ANSI 转义序列支持，返回是否开启成功。代码页在程序退出后保持为 UTF-8
+func initConsole() bool {
+	procSetConsoleCP.Call(codePageUTF8)
+	procSetConsoleOutputCP.Call(codePageUTF8)
+
+	handle := os.Stdout.Fd()
+	var mode uint32
+	if r, _, _ := procGetConsoleMode.Call(handle, uintptr(unsafe.Pointer(&mode))); r == 0 {
+		return false
+	}
+	r, _, _ := procSetConsoleMode.Call(handle, uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
+
+// 将控制台切换为按键输入模式（关闭行输入、回显和 Ctrl+C 处理，开启 VT 输入），返回恢复函数；标准输入不是控制台时返回 false
+func enableRawInput() (func(), bool) {
+	handle := os.Stdin.Fd()
+	var mode uint32
+	if r, _, _ := procGetConsoleMode.Call(handle, uintptr(unsafe.Pointer(&mode))); r == 0 {
+		return nil, false
+	}
+	raw := mode&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if r, _, _ := procSetConsoleMode.Call(handle, uintptr(raw)); r == 0 {
+		return nil, false
+	}
+	return func() { procSetConsoleMode.Call(handle, uintptr(mode)) }, true
+}
+
+// 获取控制台窗口的列数和行数，标准输出不是控制台时为 80x24
+func terminalSize() (cols, rows int) {
+	var info consoleScreenBufferInfo
+	if r, _, _ := procGetConsoleScreenBufferInfo.Call(processStdout.Fd(), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 80, 24
+	}
+	return int(info.Window[2]-info.Window[0]) + 1, int(info.Window[3]-info.Window[1]) + 1
+}
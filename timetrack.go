@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 按日汇总时默认显示的天数
+const timeSummaryDays = 14
+
+// 星期的中文名称
+var weekdayNames = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+
+// timeBucket 为一个项目在一天或一周中服务运行的总时长
+type timeBucket struct {
+	Period   time.Time // 当天零点，按周汇总时为周一零点
+	Project  string
+	Duration time.Duration
+}
+
+// 时间所在的日或周的开始时间，周从周一开始
+func periodStart(t time.Time, weekly bool) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if weekly {
+		day = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// 将各次服务运行的时长按日或按周汇总到项目，跨越零点的运行按实际时间拆分。只汇总 since 之后的时间，
+// 结果按时间先后、同一时段内按时长从长到短排列
+func summarizeTime(entries []statsEntry, weekly bool, since time.Time) []timeBucket {
+	totals := make(map[time.Time]map[string]time.Duration)
+	for _, entry := range entries {
+		start := entry.Started.Local()
+		end := start.Add(time.Duration(entry.Seconds) * time.Second)
+		if start.Before(since) {
+			start = since
+		}
+		for start.Before(end) {
+			period := periodStart(start, weekly)
+			next := period.AddDate(0, 0, 1)
+			if weekly {
+				next = period.AddDate(0, 0, 7)
+			}
+			until := end
+			if next.Before(until) {
+				until = next
+			}
+			if totals[period] == nil {
+				totals[period] = make(map[string]time.Duration)
+			}
+			totals[period][entry.Project] += until.Sub(start)
+			start = until
+		}
+	}
+	var buckets []timeBucket
+	for period, projects := range totals {
+		for project, d := range projects {
+			buckets = append(buckets, timeBucket{Period: period, Project: project, Duration: d})
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if !buckets[i].Period.Equal(buckets[j].Period) {
+			return buckets[i].Period.Before(buckets[j].Period)
+		}
+		if buckets[i].Duration != buckets[j].Duration {
+			return buckets[i].Duration > buckets[j].Duration
+		}
+		return buckets[i].Project < buckets[j].Project
+	})
+	return buckets
+}
+
+// 时段的显示名称，如 2026-10-12 周一、2026-10-12 起的一周
+func periodLabel(period time.Time, weekly bool) string {
+	if weekly {
+		return period.Format("2006-01-02") + " 起的一周"
+	}
+	return period.Format("2006-01-02") + " " + weekdayNames[period.Weekday()]
+}
+
+// 按时段列出各项目的运行时长及合计
+func printTimeSummary(out io.Writer, buckets []timeBucket, weekly bool) error {
+	if len(buckets) == 0 {
+		fmt.Fprintln(out, "这段时间内没有服务运行记录。")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var total time.Duration
+	for i, b := range buckets {
+		if i == 0 || !b.Period.Equal(buckets[i-1].Period) {
+			fmt.Fprintln(w, periodLabel(b.Period, weekly))
+			total = 0
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", b.Project, formatDuration(b.Duration))
+		total += b.Duration
+		if i == len(buckets)-1 || !buckets[i+1].Period.Equal(b.Period) {
+			fmt.Fprintf(w, "  合计\t%s\n", formatDuration(total))
+		}
+	}
+	return w.Flush()
+}
+
+// 以 CSV 输出按时段汇总的时长，hours 保留两位小数便于在表格中计费
+func writeTimeCSV(out io.Writer, buckets []timeBucket, weekly bool) error {
+	column := "date"
+	if weekly {
+		column = "week"
+	}
+	w := csv.NewWriter(out)
+	w.Write([]string{column, "project", "seconds", "hours"})
+	for _, b := range buckets {
+		w.Write([]string{b.Period.Format("2006-01-02"), b.Project, strconv.FormatInt(int64(b.Duration.Seconds()), 10), strconv.FormatFloat(b.Duration.Hours(), 'f', 2, 64)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// 以 CSV 输出 since 之后开始的每次服务运行
+func writeSessionsCSV(out io.Writer, entries []statsEntry, since time.Time) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"project", "start", "end", "seconds"})
+	for _, entry := range entries {
+		if entry.Started.Before(since) {
+			continue
+		}
+		start := entry.Started.Local()
+		end := start.Add(time.Duration(entry.Seconds) * time.Second)
+		w.Write([]string{entry.Project, start.Format(time.RFC3339), end.Format(time.RFC3339), strconv.FormatInt(entry.Seconds, 10)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// 执行 stats 的时间记录部分：--daily、--weekly 按日或按周汇总各项目的服务运行时长，--csv 输出为 CSV，
+// 只有 --csv 时输出每次运行的记录。--since 为开始日期，默认按日为最近 14 天、按周为最近 12 周
+func runTimeCommand(config *Config, args []string) error {
+	usage := fmt.Errorf("用法: quickstart stats [--daily|--weekly] [--since 日期] [--csv]")
+	var (
+		weekly, daily, asCSV bool
+		sinceValue           string
+		since                time.Time
+	)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--daily":
+			daily = true
+		case arg == "--weekly":
+			weekly = true
+		case arg == "--csv":
+			asCSV = true
+		case arg == "--since" && i+1 < len(args):
+			i++
+			sinceValue = args[i]
+		case strings.HasPrefix(arg, "--since="):
+			sinceValue = strings.TrimPrefix(arg, "--since=")
+		default:
+			return usage
+		}
+	}
+	if daily && weekly || !daily && !weekly && !asCSV {
+		return usage
+	}
+	if sinceValue != "" {
+		t, err := time.ParseInLocation("2006-01-02", sinceValue, time.Local)
+		if err != nil {
+			return fmt.Errorf("日期 %s 的格式应为 2006-01-02", sinceValue)
+		}
+		since = t
+	}
+
+	entries := readStats()
+	if len(entries) == 0 && !config.Stats {
+		fmt.Fprintln(os.Stderr, `未开启使用统计，可在配置文件中设置 "stats": true`)
+	}
+	if !daily && !weekly {
+		return writeSessionsCSV(os.Stdout, entries, since)
+	}
+	if since.IsZero() {
+		since = periodStart(time.Now(), weekly).AddDate(0, 0, 1-timeSummaryDays)
+		if weekly {
+			since = periodStart(time.Now(), weekly).AddDate(0, 0, -7*(statsWeeks-1))
+		}
+	}
+	buckets := summarizeTime(entries, weekly, since)
+	if asCSV {
+		return writeTimeCSV(os.Stdout, buckets, weekly)
+	}
+	return printTimeSummary(os.Stdout, buckets, weekly)
+}
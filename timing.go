@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// 启动统计中每个项目参与平均的最近启动次数，构建优化后平均值能较快反映出来
+const timingWindow = 20
+
+// 启动的各阶段，按发生的顺序排列
+var launchPhases = []struct{ Key, Name string }{
+	{"git", "切换分支"},
+	{"editor", "打开编辑器"},
+	{"prepare", "启动准备"},
+	{"discovery", "检测项目"},
+	{"install", "依赖检查"},
+	{"ready", "就绪"},
+}
+
+// timingRecord 为一次启动各阶段的耗时记录
+type timingRecord struct {
+	Project string             `json:"project"`
+	Started time.Time          `json:"started"`
+	Phases  map[string]float64 `json:"phases"` // 阶段 → 秒
+}
+
+// launchTiming 记录正在进行的启动各阶段的耗时，等待用户输入的时间不计入
+type launchTiming struct {
+	timingRecord
+	mu   sync.Mutex
+	once sync.Once
+}
+
+// 当前进程中正在进行的启动
+var currentLaunch *launchTiming
+
+// 启动耗时记录文件路径，每行一条 JSON 记录，只追加不改写
+func timingsPath() string {
+	return statePath("timings.jsonl")
+}
+
+// 开始记录项目的一次启动，同一项目已在记录中时沿用，如切换分支后继续启动
+func beginLaunch(project string) *launchTiming {
+	if currentLaunch == nil || currentLaunch.Project != project {
+		currentLaunch = &launchTiming{timingRecord: timingRecord{Project: project, Started: time.Now(), Phases: map[string]float64{}}}
+	}
+	return currentLaunch
+}
+
+// 开始计时一个阶段，返回结束计时的函数，同一阶段多次计时时累加。没有进行中的启动时不记录
+func timePhase(key string) func() {
+	t := currentLaunch
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.add(key, time.Since(start))
+	}
+}
+
+// 累加阶段的耗时
+func (t *launchTiming) add(key string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Phases[key] += d.Seconds()
+}
+
+// 启动完成（服务就绪或没有就绪检查时服务启动）后显示各阶段的耗时，开启使用统计时追加到记录中。只有第一次调用有效
+func (t *launchTiming) finish(stats bool) {
+	t.once.Do(func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		var parts []string
+		for _, phase := range launchPhases {
+			// 不到 0.1 秒的阶段只记录不显示
+			if seconds := t.Phases[phase.Key]; seconds >= 0.1 {
+				parts = append(parts, fmt.Sprintf("%s %.1fs", phase.Name, seconds))
+			}
+		}
+		if len(parts) > 0 {
+			fmt.Printf("启动耗时：%s\n", strings.Join(parts, " · "))
+		}
+		if stats {
+			appendTiming(t.timingRecord)
+		}
+	})
+}
+
+// 追加一次启动的耗时记录
+func appendTiming(t timingRecord) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(timingsPath()), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(timingsPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// 读取各项目最近 timingWindow 次启动的耗时记录，按时间先后排列，忽略无法解析的行
+func readTimings() map[string][]timingRecord {
+	f, err := os.Open(timingsPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	timings := make(map[string][]timingRecord)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var t timingRecord
+		if json.Unmarshal(scanner.Bytes(), &t) != nil || t.Project == "" {
+			continue
+		}
+		list := append(timings[t.Project], t)
+		if len(list) > timingWindow {
+			list = list[1:]
+		}
+		timings[t.Project] = list
+	}
+	return timings
+}
+
+// 输出各项目启动各阶段的平均耗时，就绪最慢的项目排在最前；某阶段没有记录时显示 -
+func printTimingStats(w io.Writer) error {
+	timings := readTimings()
+	if len(timings) == 0 {
+		return nil
+	}
+	type row struct {
+		name     string
+		count    int
+		averages map[string]float64
+	}
+	var rows []row
+	for name, list := range timings {
+		sums, counts := map[string]float64{}, map[string]int{}
+		for _, t := range list {
+			for key, seconds := range t.Phases {
+				sums[key] += seconds
+				counts[key]++
+			}
+		}
+		averages := make(map[string]float64, len(sums))
+		for key, sum := range sums {
+			averages[key] = sum / float64(counts[key])
+		}
+		rows = append(rows, row{name: name, count: len(list), averages: averages})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].averages["ready"] != rows[j].averages["ready"] {
+			return rows[i].averages["ready"] > rows[j].averages["ready"]
+		}
+		return rows[i].name < rows[j].name
+	})
+
+	fmt.Fprintf(w, "\n启动各阶段平均耗时（每个项目最近 %d 次）：\n", timingWindow)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"项目", "次数"}
+	for _, phase := range launchPhases {
+		header = append(header, phase.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, r := range rows {
+		cells := []string{r.name, fmt.Sprint(r.count)}
+		for _, phase := range launchPhases {
+			if seconds, ok := r.averages[phase.Key]; ok {
+				cells = append(cells, fmt.Sprintf("%.1fs", seconds))
+			} else {
+				cells = append(cells, "-")
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 从版本输出中提取版本号，如 v18.19.0、go1.22.1、PHP 8.2.12
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// toolRequirement 为项目声明的一个所需工具，如 "node >=18"、"go 1.22"、"php ^8.2"
+type toolRequirement struct {
+	Name       string
+	Constraint string // 版本约束，为空表示只要求已安装
+}
+
+// 解析所需工具声明，格式为 <工具> [约束]。约束可为 >=、>、<=、<、= 加版本号，
+// ^ 与 npm 一致表示最左侧非 0 的部分相同（^8.2 为 8.x，^0.2 为 0.2.x），~ 表示主次版本相同，
+// 只写版本号时视为 >=（与 go.mod 中的 go 指令一致）
+func parseRequirement(s string) (toolRequirement, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return toolRequirement{}, fmt.Errorf("所需工具为空")
+	}
+	req := toolRequirement{Name: fields[0], Constraint: strings.Join(fields[1:], "")}
+	if req.Constraint != "" {
+		if _, _, err := splitConstraint(req.Constraint); err != nil {
+			return toolRequirement{}, fmt.Errorf("无法解析 %q 的版本约束: %v", s, err)
+		}
+	}
+	return req, nil
+}
+
+// 拆分约束中的运算符和版本号
+func splitConstraint(constraint string) (op string, version []int, err error) {
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(constraint, candidate) {
+			op = candidate
+			break
+		}
+	}
+	version, err = parseVersion(strings.TrimPrefix(constraint, op))
+	if op == "" {
+		op = ">="
+	}
+	return op, version, err
+}
+
+// 将 1.22.1 形式的版本号解析为数字
+func parseVersion(s string) ([]int, error) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return nil, fmt.Errorf("缺少版本号")
+	}
+	var version []int
+	for _, part := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("版本号 %s 格式错误", s)
+		}
+		version = append(version, n)
+	}
+	return version, nil
+}
+
+// 比较两个版本号，只比较 b 中给出的部分，如 18.19.0 与 18 相等
+func compareVersion(a, b []int) int {
+	for i := range b {
+		x := 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if x != b[i] {
+			if x < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// 判断已安装的版本是否满足约束。与 npm 的 x-range 一致，只比较约束中给出的部分：
+// <=18 允许 18.19，<18 不允许 18.x，>18 要求 19 及以上，=18 允许任意 18.x
+func satisfies(installed []int, constraint string) bool {
+	op, version, err := splitConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	cmp := compareVersion(installed, version)
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	case "=":
+		return cmp == 0
+	case "^":
+		// 锁定到第一个非 0 的部分为止，全为 0 时锁定给出的所有部分，如 ^0.0.3 只允许 0.0.3
+		fixed := len(version)
+		for i, n := range version {
+			if n != 0 {
+				fixed = i + 1
+				break
+			}
+		}
+		return cmp >= 0 && compareVersion(installed, version[:fixed]) == 0
+	case "~":
+		return cmp >= 0 && compareVersion(installed, version[:min(2, len(version))]) == 0
+	}
+	return false
+}
+
+// 获取工具的版本参数和安装提示，未在环境检查中列出的工具使用 --version
+func toolInfo(name string) (args []string, hint string) {
+	for _, tool := range doctorTools {
+		if tool.Name == name {
+			return tool.Args, tool.Hint
+		}
+	}
+	return []string{"--version"}, ""
+}
+
+// 检查一个所需工具，满足时返回已安装的版本，否则返回原因
+func (req toolRequirement) check(ctx context.Context) (version string, err error) {
+	args, hint := toolInfo(req.Name)
+	if hint != "" {
+		hint = "，安装：" + hint
+	}
+	if _, err := exec.LookPath(req.Name); err != nil {
+		return "", fmt.Errorf("未安装%s", hint)
+	}
+	if req.Constraint == "" {
+		return toolVersion(ctx, req.Name, args...), nil
+	}
+	output := toolVersion(ctx, req.Name, args...)
+	version = versionPattern.FindString(output)
+	installed, err := parseVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("无法从 %q 中识别版本，需要 %s", output, req.Constraint)
+	}
+	if !satisfies(installed, req.Constraint) {
+		return "", fmt.Errorf("已安装 %s，需要 %s%s", version, req.Constraint, hint)
+	}
+	return version, nil
+}
+
+// 启动项目前检查所需工具，有不满足的工具时列出并询问是否继续，返回是否继续启动项目
+func ensureTools(ctx context.Context, config *Config, folder string) bool {
+	project := findProject(config, folder)
+	if project == nil || len(project.Requires) == 0 {
+		return true
+	}
+	ok := true
+	for _, s := range project.Requires {
+		req, err := parseRequirement(s)
+		if err != nil {
+			fmt.Printf("✘ %v\n", err)
+			ok = false
+			continue
+		}
+		if _, err := req.check(ctx); err != nil {
+			fmt.Printf("✘ %s %v\n", req.Name, err)
+			ok = false
+		}
+	}
+	return ok || confirm(ctx, "部分所需工具不满足要求，是否继续启动项目？(Y/n): ")
+}
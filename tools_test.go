@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		installed  string
+		constraint string
+		want       bool
+	}{
+		{"18.19.0", ">=18", true},
+		{"17.9.1", ">=18", false},
+		{"1.22.1", "1.22", true},
+		{"1.21.9", "1.22", false},
+		{"18.0.0", ">18", false},
+		{"19.0.0", ">18", true},
+		{"18.19.0", "<=18", true},
+		{"19.0.0", "<=18", false},
+		{"18.19.0", "<18", false},
+		{"17.9.0", "<18", true},
+		{"18.19.0", "=18", true},
+		{"18.19.0", "=18.18", false},
+		{"8.3.1", "^8.2", true},
+		{"8.1.0", "^8.2", false},
+		{"9.0.0", "^8.2", false},
+		{"0.2.5", "^0.2", true},
+		{"0.3.1", "^0.2", false},
+		{"0.2.0", "^0.2.1", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"0.9.0", "^0", true},
+		{"1.0.0", "^0", false},
+		{"1.22.5", "~1.22.1", true},
+		{"1.23.0", "~1.22.1", false},
+		{"1.22.0", "~1.22.1", false},
+		{"8.9.0", "~8", true},
+		{"9.0.0", "~8", false},
+		{"8.2", "8.2.1", false},
+		{"8.2", "8.2.0", true},
+	}
+	for _, tt := range tests {
+		installed, err := parseVersion(tt.installed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := satisfies(installed, tt.constraint); got != tt.want {
+			t.Errorf("satisfies(%s, %q) = %v, want %v", tt.installed, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    toolRequirement
+		wantErr bool
+	}{
+		{s: "node", want: toolRequirement{Name: "node"}},
+		{s: "node >=18", want: toolRequirement{Name: "node", Constraint: ">=18"}},
+		{s: "php ^ 8.2", want: toolRequirement{Name: "php", Constraint: "^8.2"}},
+		{s: "go v1.22", want: toolRequirement{Name: "go", Constraint: "v1.22"}},
+		{s: "", wantErr: true},
+		{s: "node >=", wantErr: true},
+		{s: "node >=x", wantErr: true},
+		{s: "node 18.x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRequirement(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRequirement(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseRequirement(%q) = %+v, want %+v", tt.s, got, tt.want)
+		}
+	}
+}
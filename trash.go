@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 项目已删除，操作菜单不再继续显示
+var errProjectDeleted = errors.New("项目已删除")
+
+// 删除项目：检查没有运行中的服务、未提交的修改和未推送的提交后，要求输入项目名确认，
+// 再将项目文件夹移到回收站；无法移到回收站时询问是否永久删除。删除后返回 errProjectDeleted
+func deleteProject(ctx context.Context, config *Config, folder, path string) error {
+	if project := findProject(config, folder); project != nil && (project.Remote != "" || project.WSL != "") {
+		return fmt.Errorf("远程项目和 WSL 项目不在本机，无法删除")
+	}
+	for _, record := range runningServices() {
+		if record.Project == folder {
+			return fmt.Errorf("%s 的服务正在运行（PID %d），请先停止", folder, record.PID)
+		}
+	}
+	if reasons := unsavedWork(ctx); len(reasons) > 0 {
+		return fmt.Errorf("已拒绝删除 %s：%s", folder, strings.Join(reasons, "；"))
+	}
+
+	input, err := readLine(ctx, fmt.Sprintf("将 %s 移到回收站，请输入项目名 %s 确认: ", path, folder))
+	if err != nil || input != folder {
+		fmt.Println("输入不一致，已取消")
+		setExitCode(exitCanceled)
+		return nil
+	}
+	// 先离开项目目录，Windows 下无法删除当前所在的目录
+	if err := os.Chdir(config.ProjectDir); err != nil {
+		return err
+	}
+	if err := moveToTrash(path); err != nil {
+		fmt.Printf("无法移到回收站: %v\n", err)
+		answer, _ := readLine(ctx, fmt.Sprintf("是否永久删除 %s？此操作无法撤销 (y/N): ", path))
+		if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+			fmt.Println("已取消")
+			setExitCode(exitCanceled)
+			return nil
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("无法删除 %s: %v", path, err)
+		}
+		fmt.Printf("已永久删除项目：%s\n", folder)
+		return errProjectDeleted
+	}
+	fmt.Printf("已将项目 %s 移到回收站\n", folder)
+	return errProjectDeleted
+}
+
+// 检查当前目录的 git 仓库中删除后会丢失的内容：未提交的修改、未推送到任何远程的提交和储藏。不是 git 仓库时返回 nil
+func unsavedWork(ctx context.Context) []string {
+	if _, err := queryOutput(ctx, "git", "rev-parse", "--git-dir"); err != nil {
+		return nil
+	}
+	var reasons []string
+	if out, err := queryOutput(ctx, "git", "status", "--porcelain"); err != nil {
+		reasons = append(reasons, fmt.Sprintf("无法读取 git 状态: %v", err))
+	} else if n := countLines(out); n > 0 {
+		reasons = append(reasons, fmt.Sprintf("有 %d 个文件未提交", n))
+	}
+	if out, err := queryOutput(ctx, "git", "log", "--branches", "--not", "--remotes", "--oneline"); err == nil {
+		if n := countLines(out); n > 0 {
+			reasons = append(reasons, fmt.Sprintf("有 %d 个提交未推送到远程仓库", n))
+		}
+	}
+	if out, err := queryOutput(ctx, "git", "stash", "list"); err == nil {
+		if n := countLines(out); n > 0 {
+			reasons = append(reasons, fmt.Sprintf("有 %d 个储藏（stash）", n))
+		}
+	}
+	return reasons
+}
+
+// 统计命令输出中的非空行数
+func countLines(out []byte) int {
+	n := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// 将文件夹移到系统回收站：Windows 通过 PowerShell 调用回收站接口，macOS 通过访达，
+// 其他系统使用 gio 或 trash-cli
+func moveToTrash(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		// 路径经环境变量传入，避免在 PowerShell 命令中转义
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"Add-Type -AssemblyName Microsoft.VisualBasic; [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteDirectory($env:QUICKSTART_TRASH, 'OnlyErrorDialogs', 'SendToRecycleBin')")
+		cmd.Env = append(os.Environ(), "QUICKSTART_TRASH="+path)
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", "on run argv", "-e", `tell application "Finder" to delete POSIX file (item 1 of argv)`, "-e", "end run", path)
+	default:
+		for _, candidate := range [][]string{{"gio", "trash"}, {"trash-put"}} {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				cmd = exec.Command(candidate[0], append(candidate[1:], path)...)
+				break
+			}
+		}
+		if cmd == nil {
+			return fmt.Errorf("未找到回收站工具，请安装 gio（glib2）或 trash-cli")
+		}
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	if fileExists(path) {
+		return fmt.Errorf("%s 仍然存在", path)
+	}
+	return nil
+}
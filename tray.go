@@ -0,0 +1,271 @@
+//go:build !darwin || cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+
+	"go-quickStart/ipc"
+)
+
+// 托盘菜单刷新项目和服务状态的间隔
+const trayRefreshInterval = 3 * time.Second
+
+// tray 为系统托盘中的启动器，通过守护进程启动和停止服务
+type tray struct {
+	ctx       context.Context // 启动器的根上下文，退出时取消
+	mu        sync.Mutex
+	client    *ipc.Client // 守护进程重启后重新连接
+	refresh   chan struct{}
+	signature string        // 当前菜单对应的项目和服务状态，状态变化时才重建菜单
+	done      chan struct{} // 重建菜单时关闭，结束旧菜单项的点击处理
+}
+
+// 执行 tray 子命令：在系统托盘中列出项目和运行中的服务，可从菜单启动、停止服务和打开编辑器。
+// 服务由守护进程运行，守护进程未运行时在后台启动
+func runTray(ctx context.Context) error {
+	// Linux 的托盘图标通过桌面会话的 D-Bus 提供，没有会话时 systray 只输出日志而不会退出
+	if runtime.GOOS == "linux" && os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return fmt.Errorf("未找到桌面会话（DBUS_SESSION_BUS_ADDRESS 为空），托盘模式需要在图形桌面中运行")
+	}
+	client, err := dialDaemon()
+	if err != nil {
+		return err
+	}
+	t := &tray{ctx: ctx, client: client, refresh: make(chan struct{}, 1), done: make(chan struct{})}
+	systray.Run(t.ready, func() { t.daemon().Close() })
+	return nil
+}
+
+// 连接守护进程，未运行时在后台启动后重试
+func dialDaemon() (*ipc.Client, error) {
+	if client, err := ipc.Dial(); err == nil {
+		return client, nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("无法获取当前执行文件的路径: %v", err)
+	}
+	cmd := exec.Command(exe, "daemon")
+	// 守护进程不随托盘退出
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("无法启动守护进程: %v", err)
+	}
+	go cmd.Wait()
+	var lastErr error
+	for i := 0; i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		client, err := ipc.Dial()
+		if err == nil {
+			return client, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (t *tray) ready() {
+	systray.SetIcon(trayIcon())
+	systray.SetTitle("QuickStart")
+	systray.SetTooltip("QuickStart")
+	if config, err := loadConfig(); err == nil && config.Hotkey != nil && config.Hotkey.Keys != "" {
+		if err := startHotkey(config); err != nil {
+			fmt.Println(err)
+			notify("QuickStart", err.Error())
+		} else {
+			systray.SetTooltip("QuickStart（" + config.Hotkey.Keys + " 选择项目）")
+		}
+	}
+	go func() {
+		ticker := time.NewTicker(trayRefreshInterval)
+		defer ticker.Stop()
+		for {
+			t.update()
+			select {
+			case <-ticker.C:
+			case <-t.refresh:
+			}
+		}
+	}()
+}
+
+// 当前的守护进程连接
+func (t *tray) daemon() *ipc.Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.client
+}
+
+// 请求立即刷新菜单
+func (t *tray) requestRefresh() {
+	select {
+	case t.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// 读取项目和服务状态，有变化时重建菜单。守护进程退出后重新连接
+func (t *tray) update() {
+	projects, err := t.daemon().ListProjects()
+	if err != nil {
+		t.daemon().Close()
+		client, dialErr := dialDaemon()
+		if dialErr != nil {
+			t.rebuild("error:"+dialErr.Error(), func(done <-chan struct{}) {
+				item := systray.AddMenuItem("无法连接守护进程", dialErr.Error())
+				item.Disable()
+			})
+			return
+		}
+		t.mu.Lock()
+		t.client = client
+		t.mu.Unlock()
+		if projects, err = client.ListProjects(); err != nil {
+			return
+		}
+	}
+	// 在终端中启动的服务也显示出来，但只能在终端中停止
+	external := make(map[string]bool)
+	for _, record := range runningServices() {
+		external[record.Project] = true
+	}
+
+	var signature strings.Builder
+	for _, p := range projects {
+		fmt.Fprintf(&signature, "%s:%t:%t:%s\n", p.Name, p.Running, external[p.Name], p.Remark)
+	}
+	t.rebuild(signature.String(), func(done <-chan struct{}) {
+		running := 0
+		for _, p := range projects {
+			if p.Running || external[p.Name] {
+				running++
+			}
+		}
+		header := systray.AddMenuItem(fmt.Sprintf("%d 个服务运行中", running), "")
+		header.Disable()
+		systray.AddSeparator()
+		for _, p := range projects {
+			t.addProject(p, external[p.Name] && !p.Running, done)
+		}
+	})
+}
+
+// 状态有变化时清空菜单并重新添加菜单项，最后添加退出
+func (t *tray) rebuild(signature string, build func(done <-chan struct{})) {
+	if signature == t.signature {
+		return
+	}
+	t.signature = signature
+	close(t.done)
+	t.done = make(chan struct{})
+	systray.ResetMenu()
+	build(t.done)
+	systray.AddSeparator()
+	quit := systray.AddMenuItem("退出", "退出托盘，守护进程中的服务继续运行")
+	onClick(quit, t.done, systray.Quit)
+}
+
+// 添加项目的菜单项，子菜单中可启动或停止服务、打开编辑器和文件管理器。external 为服务在终端中运行
+func (t *tray) addProject(p ipc.Project, external bool, done <-chan struct{}) {
+	title := p.Name
+	if external {
+		title += "（终端中运行）"
+	}
+	item := systray.AddMenuItemCheckbox(title, p.Remark, p.Running || external)
+	switch {
+	case p.Running:
+		onClick(item.AddSubMenuItem("停止服务", ""), done, func() {
+			if err := t.daemon().Stop(p.Name); err != nil {
+				notify("QuickStart", fmt.Sprintf("无法停止 %s: %v", p.Name, err))
+			}
+			t.requestRefresh()
+		})
+	case external:
+		stop := item.AddSubMenuItem("停止服务", "服务在终端中运行，请在终端中停止")
+		stop.Disable()
+	default:
+		onClick(item.AddSubMenuItem("启动服务", ""), done, func() {
+			if _, err := t.daemon().Launch(p.Name); err != nil {
+				notify("QuickStart", fmt.Sprintf("无法启动 %s: %v", p.Name, err))
+			}
+			t.requestRefresh()
+		})
+	}
+	onClick(item.AddSubMenuItem("打开编辑器", ""), done, func() {
+		config, err := loadConfig()
+		if err == nil {
+			if err = os.Chdir(p.Path); err == nil {
+				err = openEditor(t.ctx, config, findProject(config, p.Name))
+			}
+		}
+		if err != nil {
+			notify("QuickStart", fmt.Sprintf("无法打开编辑器: %v", err))
+		}
+	})
+	onClick(item.AddSubMenuItem("在文件管理器中打开", ""), done, func() {
+		if err := openFileManager(p.Path); err != nil {
+			notify("QuickStart", err.Error())
+		}
+	})
+}
+
+// 菜单项被点击时执行 action，done 关闭后不再处理
+func onClick(item *systray.MenuItem, done <-chan struct{}, action func()) {
+	go func() {
+		for {
+			select {
+			case <-item.ClickedCh:
+				action()
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// 生成托盘图标：蓝色圆形中的白色三角形。Windows 需要 ICO 格式，将 PNG 放入 ICO 容器中
+func trayIcon() []byte {
+	const size = 32
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	blue := color.RGBA{0x2d, 0x7f, 0xf9, 0xff}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)-15.5, float64(y)-15.5
+			if dx*dx+dy*dy > 15.5*15.5 {
+				continue
+			}
+			img.Set(x, y, blue)
+			// 三角形顶点为 (11,8)、(11,24)、(24,16)
+			if x >= 11 && x <= 24 && float64(y) >= 8+float64(x-11)*8/13 && float64(y) <= 24-float64(x-11)*8/13 {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	if runtime.GOOS != "windows" {
+		return buf.Bytes()
+	}
+	var ico bytes.Buffer
+	binary.Write(&ico, binary.LittleEndian, []uint16{0, 1, 1})
+	ico.Write([]byte{size, size, 0, 0})
+	binary.Write(&ico, binary.LittleEndian, []uint16{1, 32})
+	binary.Write(&ico, binary.LittleEndian, []uint32{uint32(buf.Len()), 22})
+	ico.Write(buf.Bytes())
+	return ico.Bytes()
+}
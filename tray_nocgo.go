@@ -0,0 +1,13 @@
+//go:build darwin && !cgo
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// macOS 的托盘图标需要通过 cgo 调用系统框架
+func runTray(_ context.Context) error {
+	return fmt.Errorf("托盘模式需要启用 cgo 编译（CGO_ENABLED=1）")
+}
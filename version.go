@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// 版本信息，发布时通过 -ldflags "-X main.version=v1.0.0 -X main.commit=... -X main.date=..." 写入
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// 获取构建信息，未通过 ldflags 指定时从 Go 嵌入的 VCS 信息中读取
+func buildInfo() (ver, rev, built string) {
+	ver, rev, built = version, commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if ver == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			ver = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && rev == "":
+				rev = setting.Value
+			case setting.Key == "vcs.time" && built == "":
+				built = setting.Value
+			}
+		}
+	}
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+	if built == "" {
+		built = "unknown"
+	}
+	return ver, rev, built
+}
+
+// 打印版本信息
+func printVersion() {
+	ver, rev, built := buildInfo()
+	fmt.Printf("quickstart %s\n", ver)
+	fmt.Printf("commit: %s\n", rev)
+	fmt.Printf("built:  %s\n", built)
+	fmt.Printf("go:     %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("license: %s（quickstart license 查看全文）\n", licenseID)
+}
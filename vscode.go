@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// vscodeTask 为 .vscode/tasks.json 中的一个任务或 launch.json 中可以还原为命令的启动配置
+type vscodeTask struct {
+	Name    string   // 菜单中显示的名称
+	Command []string // 要执行的命令及参数
+	Dir     string   // 执行目录，为空时为项目目录
+	Env     []string // 追加的环境变量
+}
+
+// vscodeValue 为 tasks.json 中可写为字符串或 {"value": "..."} 的字段
+type vscodeValue string
+
+func (v *vscodeValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = vscodeValue(s)
+		return nil
+	}
+	var quoted struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &quoted); err != nil {
+		return err
+	}
+	*v = vscodeValue(quoted.Value)
+	return nil
+}
+
+// vscodeOptions 为任务的 options 字段
+type vscodeOptions struct {
+	Cwd   string            `json:"cwd"`
+	Env   map[string]string `json:"env"`
+	Shell *struct {
+		Executable string `json:"executable"`
+	} `json:"shell"`
+}
+
+// vscodeTaskCommand 为任务中可按系统覆盖的字段
+type vscodeTaskCommand struct {
+	Command vscodeValue    `json:"command"`
+	Args    []vscodeValue  `json:"args"`
+	Options *vscodeOptions `json:"options"`
+}
+
+// vscodeTaskJSON 为 tasks.json 中的一个任务
+type vscodeTaskJSON struct {
+	vscodeTaskCommand
+	Label   string             `json:"label"`
+	TaskID  string             `json:"taskName"` // 0.1.0 版本格式的任务名
+	Type    string             `json:"type"`
+	Script  string             `json:"script"` // npm 任务的脚本名
+	Path    string             `json:"path"`   // npm 任务所在的子目录
+	Windows *vscodeTaskCommand `json:"windows"`
+	Linux   *vscodeTaskCommand `json:"linux"`
+	OSX     *vscodeTaskCommand `json:"osx"`
+}
+
+// vscodeLaunchJSON 为 launch.json 中的一个启动配置
+type vscodeLaunchJSON struct {
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	Request           string            `json:"request"`
+	Program           string            `json:"program"`
+	Module            string            `json:"module"`
+	Args              []string          `json:"args"`
+	Cwd               string            `json:"cwd"`
+	Env               map[string]string `json:"env"`
+	RuntimeExecutable string            `json:"runtimeExecutable"`
+	RuntimeArgs       []string          `json:"runtimeArgs"`
+}
+
+// 任务命令中可以替换的 VS Code 变量，其他变量（如 ${file}、${input:...}）依赖编辑器状态，包含它们的任务不会列出
+var vscodeVariable = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// 读取编辑器目录下 .vscode 中的任务和启动配置，无法还原为命令的条目会被跳过
+func vscodeTasks(config *Config, folder string) []vscodeTask {
+	editorDir := "."
+	if project := findProject(config, folder); project != nil && project.EditorDir != "" {
+		editorDir = project.EditorDir
+	}
+	workspace, err := filepath.Abs(editorDir)
+	if err != nil {
+		return nil
+	}
+
+	var tasks []vscodeTask
+	var tasksFile struct {
+		vscodeTaskJSON
+		Tasks []vscodeTaskJSON `json:"tasks"`
+	}
+	if err := readJSONCFile(filepath.Join(workspace, ".vscode", "tasks.json"), &tasksFile); err == nil {
+		for _, t := range tasksFile.Tasks {
+			if task, ok := t.resolve(config, workspace, &tasksFile.vscodeTaskJSON); ok {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	var launchFile struct {
+		Configurations []vscodeLaunchJSON `json:"configurations"`
+	}
+	if err := readJSONCFile(filepath.Join(workspace, ".vscode", "launch.json"), &launchFile); err == nil {
+		for _, c := range launchFile.Configurations {
+			if task, ok := c.resolve(workspace); ok {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks
+}
+
+// 将任务转换为要执行的命令。global 为 tasks.json 顶层的默认设置
+func (t vscodeTaskJSON) resolve(config *Config, workspace string, global *vscodeTaskJSON) (vscodeTask, bool) {
+	name := t.Label
+	if name == "" {
+		name = t.TaskID
+	}
+	cmd := mergeTaskCommand(global.vscodeTaskCommand, global.platform())
+	cmd = mergeTaskCommand(cmd, &t.vscodeTaskCommand)
+	cmd = mergeTaskCommand(cmd, t.platform())
+	taskType := t.Type
+	if taskType == "" {
+		taskType = global.Type
+	}
+
+	task := vscodeTask{Name: "任务: " + name}
+	if cmd.Options != nil {
+		task.Dir = cmd.Options.Cwd
+		for k, v := range cmd.Options.Env {
+			task.Env = append(task.Env, k+"="+v)
+		}
+	}
+	switch taskType {
+	case "npm":
+		if t.Script == "" {
+			return vscodeTask{}, false
+		}
+		task.Command = []string{nodePackageManager(), "run", t.Script}
+		if t.Path != "" {
+			task.Dir = t.Path
+		}
+	case "process":
+		if cmd.Command == "" {
+			return vscodeTask{}, false
+		}
+		task.Command = []string{string(cmd.Command)}
+		for _, arg := range cmd.Args {
+			task.Command = append(task.Command, string(arg))
+		}
+	case "shell", "":
+		if cmd.Command == "" {
+			return vscodeTask{}, false
+		}
+		line := string(cmd.Command)
+		for _, arg := range cmd.Args {
+			line += " " + quoteShellArg(string(arg))
+		}
+		shell := configuredShell(config)
+		if cmd.Options != nil && cmd.Options.Shell != nil && cmd.Options.Shell.Executable != "" {
+			shell = cmd.Options.Shell.Executable
+		}
+		task.Command = shellArgs(shell, line)
+	default:
+		// 扩展提供的任务类型无法在编辑器外执行
+		return vscodeTask{}, false
+	}
+	return task.expand(workspace)
+}
+
+// 获取当前系统对应的覆盖设置
+func (t *vscodeTaskJSON) platform() *vscodeTaskCommand {
+	switch runtime.GOOS {
+	case "windows":
+		return t.Windows
+	case "darwin":
+		return t.OSX
+	default:
+		return t.Linux
+	}
+}
+
+// 用 override 中设置了的字段覆盖 base
+func mergeTaskCommand(base vscodeTaskCommand, override *vscodeTaskCommand) vscodeTaskCommand {
+	if override == nil {
+		return base
+	}
+	if override.Command != "" {
+		base.Command = override.Command
+	}
+	if override.Args != nil {
+		base.Args = override.Args
+	}
+	if override.Options != nil {
+		base.Options = override.Options
+	}
+	return base
+}
+
+// 将启动配置还原为命令，支持 node、go 和 python 的 launch 配置
+func (c vscodeLaunchJSON) resolve(workspace string) (vscodeTask, bool) {
+	if c.Request != "launch" {
+		return vscodeTask{}, false
+	}
+	var command []string
+	switch c.Type {
+	case "node", "pwa-node":
+		if c.RuntimeExecutable != "" {
+			command = append([]string{c.RuntimeExecutable}, c.RuntimeArgs...)
+		} else if c.Program != "" {
+			command = append([]string{"node"}, c.RuntimeArgs...)
+			command = append(command, c.Program)
+		}
+	case "go":
+		if c.Program != "" {
+			command = []string{"go", "run", c.Program}
+		}
+	case "python", "debugpy":
+		switch {
+		case c.Module != "":
+			command = []string{"python", "-m", c.Module}
+		case c.Program != "":
+			command = []string{"python", c.Program}
+		}
+	}
+	if len(command) == 0 {
+		return vscodeTask{}, false
+	}
+	task := vscodeTask{Name: "启动配置: " + c.Name, Command: append(command, c.Args...), Dir: c.Cwd}
+	for k, v := range c.Env {
+		task.Env = append(task.Env, k+"="+v)
+	}
+	return task.expand(workspace)
+}
+
+// 替换命令、目录和环境变量中的 VS Code 变量，存在无法替换的变量时返回 false
+func (t vscodeTask) expand(workspace string) (vscodeTask, bool) {
+	ok := true
+	replace := func(s string) string {
+		return vscodeVariable.ReplaceAllStringFunc(s, func(match string) string {
+			name := match[2 : len(match)-1]
+			switch {
+			case name == "workspaceFolder" || name == "workspaceRoot" || name == "cwd":
+				return workspace
+			case name == "workspaceFolderBasename":
+				return filepath.Base(workspace)
+			case name == "pathSeparator" || name == "/":
+				return string(os.PathSeparator)
+			case strings.HasPrefix(name, "env:"):
+				return os.Getenv(strings.TrimPrefix(name, "env:"))
+			}
+			ok = false
+			return match
+		})
+	}
+	command := make([]string, len(t.Command))
+	for i, arg := range t.Command {
+		command[i] = replace(arg)
+	}
+	t.Command = command
+	env := make([]string, len(t.Env))
+	for i, kv := range t.Env {
+		env[i] = replace(kv)
+	}
+	t.Env = env
+	if t.Dir = replace(t.Dir); t.Dir != "" && !filepath.IsAbs(t.Dir) {
+		t.Dir = filepath.Join(workspace, t.Dir)
+	}
+	return t, ok
+}
+
+// 含空白的参数加上双引号，与 VS Code 拼接 shell 任务命令的方式一致
+func quoteShellArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t") && !strings.HasPrefix(arg, `"`) {
+		return `"` + arg + `"`
+	}
+	return arg
+}
+
+// 读取 JSONC 文件（允许注释和尾随逗号，VS Code 的配置文件均为此格式）并解析到 v
+func readJSONCFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(stripJSONC(data), v)
+}
+
+// 去掉 JSONC 中的注释和对象、数组末尾多余的逗号
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			// 去掉前面的尾随逗号
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// 选择并运行 .vscode 中的一个任务或启动配置，结束后返回
+func runVSCodeTask(ctx context.Context, config *Config, folder, _ string) error {
+	tasks := vscodeTasks(config, folder)
+	if len(tasks) == 0 {
+		fmt.Println("未找到可在编辑器外运行的 VS Code 任务或启动配置")
+		return nil
+	}
+	fmt.Println("VS Code 任务：")
+	for i, task := range tasks {
+		fmt.Printf("%d. %s\n", i+1, task.Name)
+	}
+	for {
+		choice, err := getUserChoice(ctx, "请输入任务编号: ", len(tasks))
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		task := tasks[choice-1]
+		if task.Dir != "" {
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if err := os.Chdir(task.Dir); err != nil {
+				return fmt.Errorf("无法进入任务目录 %s: %v", task.Dir, err)
+			}
+			defer os.Chdir(dir)
+		}
+		command := wrapCommand(task.Command)
+		if err := runStep(ctx, 0, append(projectEnv(ctx, config, folder), task.Env...), command...); err != nil {
+			return fmt.Errorf("%s 执行失败: %v", strings.Join(command, " "), err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 文件变动的轮询间隔
+const watchInterval = 500 * time.Millisecond
+
+// 监听时默认跳过的目录
+var watchSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+}
+
+// fileWatcher 通过轮询修改时间监听匹配模式的文件变动
+type fileWatcher struct {
+	dir     string
+	include []string
+	exclude []string
+	files   map[string]time.Time
+}
+
+// 创建文件监听，模式相对于监听目录，支持 ** 通配符和 ! 排除
+func newFileWatcher(dir string, patterns []string) *fileWatcher {
+	w := &fileWatcher{dir: dir}
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			w.exclude = append(w.exclude, strings.TrimPrefix(pattern, "!"))
+		} else {
+			w.include = append(w.include, pattern)
+		}
+	}
+	w.files = w.scan()
+	return w
+}
+
+// 扫描监听目录下匹配的文件及其修改时间，路径相对于监听目录
+func (w *fileWatcher) scan() map[string]time.Time {
+	files := make(map[string]time.Time)
+	filepath.WalkDir(w.dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		path, _ = filepath.Rel(w.dir, path)
+		if entry.IsDir() {
+			if path != "." && (watchSkipDirs[entry.Name()] || matchAny(w.exclude, path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !matchAny(w.include, path) || matchAny(w.exclude, path) {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			files[path] = info.ModTime()
+		}
+		return nil
+	})
+	return files
+}
+
+// 检查自上次检查以来是否有文件新增、修改或删除，返回其中一个变动的文件
+func (w *fileWatcher) changed() string {
+	files := w.scan()
+	changed := ""
+	for path, modTime := range files {
+		if old, ok := w.files[path]; !ok || !old.Equal(modTime) {
+			changed = path
+			break
+		}
+	}
+	if changed == "" {
+		for path := range w.files {
+			if _, ok := files[path]; !ok {
+				changed = path
+				break
+			}
+		}
+	}
+	w.files = files
+	return changed
+}
+
+// 等待文件变动，短时间内的连续变动只触发一次。done 关闭时停止等待并返回空字符串
+func (w *fileWatcher) wait(done <-chan struct{}) string {
+	for {
+		select {
+		case <-time.After(watchInterval):
+		case <-done:
+			return ""
+		}
+		if path := w.changed(); path != "" {
+			time.Sleep(watchInterval)
+			w.changed()
+			return path
+		}
+	}
+}
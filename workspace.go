@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// workspaceFolder 为多根工作区中的一个项目目录
+type workspaceFolder struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path"` // 相对于工作区文件所在目录
+}
+
+// 项目组的多根工作区文件，位于工作目录中，名称为项目组名
+func groupWorkspacePath(config *Config, name string) string {
+	return filepath.Join(config.ProjectDir, name+".code-workspace")
+}
+
+// 生成包含组内所有项目的多根工作区文件，按启动顺序排列，项目配置了 editorDir 时使用该目录。
+// 文件已存在时只更新 folders，保留用户添加的 settings、extensions 等内容
+func writeGroupWorkspace(config *Config, name string, levels [][]string) (string, error) {
+	path := groupWorkspacePath(config, name)
+	workspace := map[string]json.RawMessage{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &workspace); err != nil {
+			return "", fmt.Errorf("%s 不是有效的 JSON，未覆盖: %v", path, err)
+		}
+	}
+
+	var folders []workspaceFolder
+	for _, level := range levels {
+		for _, project := range level {
+			dir := project
+			if p := findProject(config, project); p != nil && p.EditorDir != "" {
+				dir = filepath.Join(project, p.EditorDir)
+			}
+			folders = append(folders, workspaceFolder{Name: project, Path: filepath.ToSlash(dir)})
+		}
+	}
+	data, err := json.Marshal(folders)
+	if err != nil {
+		return "", err
+	}
+	workspace["folders"] = data
+	if data, err = json.MarshalIndent(workspace, "", "  "); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// 生成项目组的工作区文件并用 VS Code 系列编辑器打开，其他编辑器不支持多根工作区，跳过
+func openWorkspace(ctx context.Context, config *Config, name string, levels [][]string) error {
+	editor, err := resolveEditor(ctx, config, nil)
+	if err != nil {
+		return err
+	}
+	if !isVSCodeLike(editor) {
+		fmt.Printf("编辑器 %s 不支持多根工作区，已跳过\n", editor)
+		return nil
+	}
+	path, err := writeGroupWorkspace(config, name, levels)
+	if err != nil {
+		return fmt.Errorf("无法生成工作区文件: %v", err)
+	}
+	fmt.Printf("打开工作区 %s\n", path)
+	cmd := exec.Command(editor, path)
+	cmd.Stdout = processStdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// 打开项目组的工作区，失败时提示并询问是否继续启动服务，返回是否继续
+func openGroupWorkspace(ctx context.Context, config *Config, name string, levels [][]string) bool {
+	err := openWorkspace(ctx, config, name, levels)
+	if err == nil {
+		return true
+	}
+	fmt.Println("无法打开编辑器:", err)
+	return confirm(ctx, "是否跳过编辑器继续启动？(Y/n): ")
+}
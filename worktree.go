@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// worktreeInfo 为菜单中项目的 git worktree 信息
+type worktreeInfo struct {
+	Main   string // 主仓库的文件夹名，项目本身为主仓库时为空
+	Branch string // 当前分支，分离 HEAD 时为提交的前几位
+}
+
+// 读取目录的 git 仓库信息：返回所有 worktree 共用的 git 目录和当前分支，不是 git 仓库时 ok 为 false。
+// 直接读取 .git 文件，不启动 git，菜单刷新时开销较小
+func readGitDir(dir string) (commonDir, branch string, ok bool) {
+	gitPath := filepath.Join(dir, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return "", "", false
+	}
+	gitDir := gitPath
+	if !info.IsDir() {
+		// 链接的 worktree 中 .git 为文件，内容为 gitdir: <主仓库>/.git/worktrees/<名称>
+		data, err := os.ReadFile(gitPath)
+		if err != nil || !strings.HasPrefix(string(data), "gitdir:") {
+			return "", "", false
+		}
+		gitDir = strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+	}
+	commonDir = gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	if head, err := os.ReadFile(filepath.Join(gitDir, "HEAD")); err == nil {
+		ref := strings.TrimSpace(string(head))
+		if strings.HasPrefix(ref, "ref: refs/heads/") {
+			branch = strings.TrimPrefix(ref, "ref: refs/heads/")
+		} else if len(ref) >= 7 {
+			branch = ref[:7]
+		}
+	}
+	return filepath.Clean(commonDir), branch, true
+}
+
+// 将同一仓库的 worktree 排到主仓库之后，返回有 worktree 的仓库及 worktree 的信息。
+// 主仓库不在列表中的 worktree 保持原位置
+func groupWorktrees(dir string, projects []*Project) map[string]worktreeInfo {
+	type repo struct {
+		common, branch string
+		linked         bool
+	}
+	repos := make(map[string]repo, len(projects))
+	mains := make(map[string]string) // 共用 git 目录 → 主仓库文件夹名
+	for _, p := range projects {
+		common, branch, ok := readGitDir(filepath.Join(dir, p.Name))
+		if !ok {
+			continue
+		}
+		linked := !fileIsDir(filepath.Join(dir, p.Name, ".git"))
+		repos[p.Name] = repo{common: common, branch: branch, linked: linked}
+		if !linked {
+			mains[common] = p.Name
+		}
+	}
+
+	info := make(map[string]worktreeInfo)
+	children := make(map[string][]*Project)
+	for _, p := range projects {
+		r, ok := repos[p.Name]
+		if !ok || !r.linked {
+			continue
+		}
+		if main, ok := mains[r.common]; ok {
+			info[p.Name] = worktreeInfo{Main: main, Branch: r.branch}
+			info[main] = worktreeInfo{Branch: repos[main].branch}
+			children[main] = append(children[main], p)
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	ordered := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		if info[p.Name].Main != "" {
+			continue
+		}
+		ordered = append(ordered, p)
+		ordered = append(ordered, children[p.Name]...)
+	}
+	copy(projects, ordered)
+	return info
+}
+
+// 判断两个路径是否指向同一个目录，git 输出的路径可能已解析符号链接
+func samePath(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// 判断路径是否为目录
+func fileIsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// 为当前项目的仓库新建 worktree 并打开编辑器、启动服务。worktree 放在项目旁边，
+// 名为 <主仓库>-<分支>；分支不存在时从当前提交创建，只存在于远程时创建跟踪分支
+func createWorktree(ctx context.Context, config *Config, folder, path string) error {
+	common, _, ok := readGitDir(path)
+	if !ok {
+		return fmt.Errorf("%s 不是 git 仓库", folder)
+	}
+	branch, err := readLine(ctx, "新 worktree 的分支名（直接回车取消）: ")
+	if err != nil || branch == "" {
+		return nil
+	}
+	name, target, err := addWorktree(ctx, folder, path, common, branch)
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(target); err != nil {
+		return err
+	}
+	return openAndLaunch(ctx, config, name, target)
+}
+
+// 在项目旁边为分支新建名为 <主仓库>-<分支> 的 worktree，返回 worktree 的文件夹名和路径。
+// common 为仓库共用的 git 目录；分支不存在时从当前提交创建，只存在于远程时创建跟踪分支
+func addWorktree(ctx context.Context, folder, path, common, branch string) (name, target string, err error) {
+	if _, err := queryOutput(ctx, "git", "check-ref-format", "--branch", branch); err != nil {
+		return "", "", fmt.Errorf("分支名 %s 无效", branch)
+	}
+
+	// 从 worktree 中新建时同样以主仓库命名
+	repoName := folder
+	if filepath.Base(common) == ".git" {
+		repoName = filepath.Base(filepath.Dir(common))
+	}
+	parent := filepath.Dir(path)
+	name = repoName + "-" + strings.NewReplacer("/", "-", `\`, "-").Replace(branch)
+	target = filepath.Join(parent, name)
+	if fileExists(target) {
+		return "", "", fmt.Errorf("%s 已存在", target)
+	}
+
+	args := []string{"worktree", "add", target, branch}
+	if _, err := queryOutput(ctx, "git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		if remote, err := queryOutput(ctx, "git", "for-each-ref", "--format=%(refname)", "refs/remotes/*/"+branch); err != nil || strings.TrimSpace(string(remote)) == "" {
+			args = []string{"worktree", "add", "-b", branch, target}
+		}
+	}
+	fmt.Printf("git %s\n", strings.Join(args, " "))
+	if err := runStep(ctx, 0, nil, append([]string{"git"}, args...)...); err != nil {
+		return "", "", fmt.Errorf("无法创建 worktree: %v", err)
+	}
+	return name, target, nil
+}
+
+// 查找已检出分支的 worktree，返回其路径，没有时返回空字符串
+func branchWorktree(ctx context.Context, branch string) string {
+	out, err := queryOutput(ctx, "git", "worktree", "list", "--porcelain")
+	if err != nil {
+		return ""
+	}
+	dir := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			dir = path
+		} else if line == "branch refs/heads/"+branch {
+			return filepath.FromSlash(dir)
+		}
+	}
+	return ""
+}
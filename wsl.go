@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Windows 访问 WSL 文件系统的 UNC 路径前缀
+var wslPrefixes = []string{`\\wsl$\`, `\\wsl.localhost\`}
+
+// 解析 \\wsl$\<distro>\path 形式的路径，返回发行版名称和 Linux 路径
+func parseWSLPath(path string) (distro, linuxPath string, ok bool) {
+	path = filepath.FromSlash(path)
+	for _, prefix := range wslPrefixes {
+		if len(path) <= len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+			continue
+		}
+		rest := path[len(prefix):]
+		distro, linuxPath, _ = strings.Cut(rest, `\`)
+		return distro, "/" + strings.ReplaceAll(linuxPath, `\`, "/"), distro != ""
+	}
+	return "", "", false
+}
+
+// 判断目录是否位于 WSL 中，仅在 Windows 下生效
+func wslLocation(dir string) (distro, linuxPath string, ok bool) {
+	if runtime.GOOS != "windows" {
+		return "", "", false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", false
+	}
+	return parseWSLPath(abs)
+}
+
+// 当前目录位于 WSL 中时，将命令改为通过 wsl.exe 在对应发行版中执行
+func wrapCommand(command []string) []string {
+	distro, linuxPath, ok := wslLocation(".")
+	if !ok {
+		return command
+	}
+	return append([]string{"wsl.exe", "-d", distro, "--cd", linuxPath, "--"}, command...)
+}
+
+// 拆分 <distro>:/path 格式的 WSL 项目路径
+func parseWSLProject(location string) (distro, path string, err error) {
+	distro, path, found := strings.Cut(location, ":")
+	if !found || distro == "" || !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("WSL 路径格式错误，应为 <发行版>:/path: %s", location)
+	}
+	return distro, path, nil
+}
+
+// 通过 VS Code Remote-WSL 打开配置的 WSL 项目，配置了启动命令时通过 wsl.exe 运行
+func launchWSL(ctx context.Context, project *ProjectConfig, config *Config) error {
+	if project.Command == "" {
+		return openEditor(ctx, config, project)
+	}
+	if !openEditorOrContinue(ctx, config, project.Name, project) || !askInputs(ctx, config, project.Name) {
+		return nil
+	}
+	distro, command, err := wslCommand(config, project)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("5秒后在 WSL %s 中启动服务，Ctrl+C 停止\n", distro)
+	if !waitLaunch(ctx) {
+		return nil
+	}
+	if err := newService(ctx, config, project.Name, command).run(ctx); err != nil {
+		fmt.Println("无法启动 WSL 服务:", err)
+	}
+	return nil
+}
+
+// 生成通过 wsl.exe 在 WSL 项目目录中执行启动命令的命令行
+func wslCommand(config *Config, project *ProjectConfig) (distro string, command []string, err error) {
+	distro, path, err := parseWSLProject(project.WSL)
+	if err != nil {
+		return "", nil, err
+	}
+	line, err := expandCommand(config, project.Name, project.Command)
+	if err != nil {
+		return "", nil, err
+	}
+	workDir := path
+	if project.WorkDir != "" {
+		workDir = strings.TrimSuffix(path, "/") + "/" + project.WorkDir
+	}
+	// 使用登录 shell 执行，保证 nvm 等工具配置的 PATH 生效
+	return distro, []string{"wsl.exe", "-d", distro, "--cd", workDir, "--exec", "sh", "-lc", line}, nil
+}